@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bufio"
+	"os"
+	"path/filepath"
+
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db/kv"
+	"github.com/lukso-network/lukso-orchestrator/shared/cmd"
+	"github.com/lukso-network/lukso-orchestrator/shared/params"
+	"github.com/pkg/errors"
+	"github.com/urfave/cli/v2"
+)
+
+var restoreCommand = &cli.Command{
+	Name:        "restore",
+	Usage:       "Restores a database snapshot into the data directory",
+	Description: "The snapshot is verified before it replaces the database. The orchestrator must be stopped.",
+	Flags:       []cli.Flag{cmd.RestoreSourceFileFlag, cmd.DataDirFlag},
+	Action:      restoreDB,
+}
+
+var dbCommand = &cli.Command{
+	Name:  "db",
+	Usage: "Commands for the orchestrator database",
+	Subcommands: []*cli.Command{
+		{
+			Name:        "export",
+			Usage:       "Exports the history of the database into a portable file",
+			Description: "Consensus infos, verified slot infos with their payloads, invalid, skipped and timed out slot infos, verdicts, equivocation evidence and the latest pointers are written to a checksummed JSON lines file. Pending headers and shard infos are not exported. The orchestrator must be stopped.",
+			Flags:       []cli.Flag{cmd.DBExportFileFlag, cmd.DataDirFlag},
+			Action:      exportDB,
+		},
+		{
+			Name:        "import",
+			Usage:       "Imports an exported history into an empty database",
+			Description: "The checksum of the file is verified before anything is stored. Records are trusted and are not verified again. The orchestrator must be stopped.",
+			Flags:       []cli.Flag{cmd.DBImportFileFlag, cmd.DataDirFlag},
+			Action:      importDB,
+		},
+	},
+}
+
+// restoreDB swaps the verified snapshot in as the database of the data directory
+func restoreDB(ctx *cli.Context) error {
+	snapshotPath := ctx.String(cmd.RestoreSourceFileFlag.Name)
+	dbPath := filepath.Join(ctx.String(cmd.DataDirFlag.Name), kv.OrchestratorNodeDbDirName)
+	keptPath, err := kv.RestoreSnapshot(snapshotPath, dbPath)
+	if err != nil {
+		return errors.Wrap(err, "could not restore database")
+	}
+	if keptPath != "" {
+		log.WithField("path", keptPath).Info("Kept replaced database")
+	}
+	log.WithField("snapshot", snapshotPath).WithField("database-path", dbPath).Info("Restored database")
+	return nil
+}
+
+// exportDB writes the history of the database into the export file. The database is opened read only. The file only
+// gets its name when the export is complete.
+func exportDB(ctx *cli.Context) error {
+	exportPath := ctx.String(cmd.DBExportFileFlag.Name)
+	dbPath := filepath.Join(ctx.String(cmd.DataDirFlag.Name), kv.OrchestratorNodeDbDirName)
+
+	tmpPath := exportPath + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, params.OrchestratorIoConfig().ReadWritePermissions)
+	if err != nil {
+		return err
+	}
+	writer := bufio.NewWriter(file)
+	count, err := kv.ExportDatabase(ctx.Context, dbPath, writer)
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, exportPath)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return errors.Wrap(err, "could not export database")
+	}
+	log.WithField("file", exportPath).WithField("records", count).Info("Exported database")
+	return nil
+}
+
+// importDB reads the export file into the empty database of the data directory
+func importDB(ctx *cli.Context) error {
+	importPath := ctx.String(cmd.DBImportFileFlag.Name)
+	dbPath := filepath.Join(ctx.String(cmd.DataDirFlag.Name), kv.OrchestratorNodeDbDirName)
+	file, err := os.Open(importPath)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	store, err := kv.NewKVStore(ctx.Context, dbPath, &kv.Config{})
+	if err != nil {
+		return err
+	}
+	defer store.Close()
+	count, err := store.Import(ctx.Context, file)
+	if err != nil {
+		return errors.Wrap(err, "could not import database")
+	}
+	log.WithField("file", importPath).WithField("records", count).Info("Imported database")
+	return nil
+}
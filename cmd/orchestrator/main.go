@@ -3,6 +3,7 @@ package main
 import (
 	"fmt"
 	joonix "github.com/joonix/log"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db/kv"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/node"
 	"github.com/lukso-network/lukso-orchestrator/shared/cmd"
 	"github.com/lukso-network/lukso-orchestrator/shared/journald"
@@ -12,6 +13,7 @@ import (
 	"github.com/urfave/cli/v2"
 	prefixed "github.com/x-cray/logrus-prefixed-formatter"
 	"os"
+	"path/filepath"
 	"runtime"
 	runtimeDebug "runtime/debug"
 )
@@ -19,6 +21,10 @@ import (
 var appFlags = []cli.Flag{
 	cmd.VanguardGRPCEndpoint,
 	cmd.PandoraRPCEndpoint,
+	cmd.PendingSlotTimeoutFlag,
+	cmd.VerificationRulesFlag,
+	cmd.SlotTimeToleranceFlag,
+	cmd.CatchUpBatchSizeFlag,
 	cmd.VerbosityFlag,
 	cmd.IPCPathFlag,
 	cmd.HTTPEnabledFlag,
@@ -30,12 +36,20 @@ var appFlags = []cli.Flag{
 	cmd.DataDirFlag,
 	cmd.ClearDB,
 	cmd.ForceClearDB,
+	cmd.DBMigrateDryRunFlag,
+	cmd.DBPrunePolicyFlag,
+	cmd.DBPruneRetainEpochsFlag,
+	cmd.DBPruneIntervalFlag,
+	cmd.DBPayloadRetainEpochsFlag,
+	cmd.DBBackupDirFlag,
+	cmd.DBBackupIntervalFlag,
+	cmd.DBBackupKeepFlag,
 	cmd.LogFileName,
 	cmd.LogFormat,
 }
 
 func init() {
-	appFlags = cmd.WrapFlags(append(appFlags))
+	appFlags = cmd.WrapFlags(appFlags)
 }
 
 func main() {
@@ -46,6 +60,7 @@ func main() {
 	app.Version = version.Version()
 
 	app.Flags = appFlags
+	app.Commands = []*cli.Command{restoreCommand, dbCommand}
 	app.Before = func(ctx *cli.Context) error {
 		format := ctx.String(cmd.LogFormat.Name)
 		switch format {
@@ -105,6 +120,10 @@ func startNode(ctx *cli.Context) error {
 	}
 	logrus.SetLevel(level)
 
+	if ctx.Bool(cmd.DBMigrateDryRunFlag.Name) {
+		return reportPendingMigrations(ctx)
+	}
+
 	orchestrator, err := node.New(ctx)
 	if err != nil {
 		return err
@@ -112,3 +131,21 @@ func startNode(ctx *cli.Context) error {
 	orchestrator.Start()
 	return nil
 }
+
+// reportPendingMigrations logs the migrations which would be applied to the database at the next start
+func reportPendingMigrations(ctx *cli.Context) error {
+	dbPath := filepath.Join(ctx.String(cmd.DataDirFlag.Name), kv.OrchestratorNodeDbDirName)
+	migrations, err := kv.PendingMigrations(dbPath)
+	if err != nil {
+		return err
+	}
+	if len(migrations) == 0 {
+		log.WithField("database-path", dbPath).WithField("schemaVersion", kv.SchemaVersion).
+			Info("Database is up to date, no pending migrations")
+		return nil
+	}
+	for _, migration := range migrations {
+		log.WithField("version", migration.Version).WithField("name", migration.Name).Info("Pending database migration")
+	}
+	return nil
+}
@@ -45,6 +45,14 @@ var appHelpFlagGroups = []flagGroup{
 			cmd.VerbosityFlag,
 			cmd.ForceClearDB,
 			cmd.ClearDB,
+			cmd.DBMigrateDryRunFlag,
+			cmd.DBPrunePolicyFlag,
+			cmd.DBPruneRetainEpochsFlag,
+			cmd.DBPruneIntervalFlag,
+			cmd.DBPayloadRetainEpochsFlag,
+			cmd.DBBackupDirFlag,
+			cmd.DBBackupIntervalFlag,
+			cmd.DBBackupKeepFlag,
 			cmd.BoltMMapInitialSizeFlag,
 		},
 	},
@@ -60,6 +68,10 @@ var appHelpFlagGroups = []flagGroup{
 			cmd.WSPortFlag,
 			cmd.VanguardGRPCEndpoint,
 			cmd.PandoraRPCEndpoint,
+			cmd.PendingSlotTimeoutFlag,
+			cmd.VerificationRulesFlag,
+			cmd.SlotTimeToleranceFlag,
+			cmd.CatchUpBatchSizeFlag,
 		},
 	},
 	{
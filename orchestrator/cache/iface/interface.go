@@ -6,16 +6,21 @@ import (
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
 )
 
+// PandoraHeaderCache interface for pandora header cache. Headers are keyed by slot and shard index.
 type PandoraHeaderCache interface {
-	Put(ctx context.Context, slot uint64, header *eth1Types.Header) error
-	Get(ctx context.Context, slot uint64) (*eth1Types.Header, error)
+	Put(ctx context.Context, slot uint64, shardIndex uint64, header *eth1Types.Header) error
+	Get(ctx context.Context, slot uint64, shardIndex uint64) (*eth1Types.Header, error)
 	GetAll() ([]*eth1Types.Header, error)
-	Remove(ctx context.Context, slot uint64)
+	Remove(ctx context.Context, slot uint64, shardIndex uint64) map[uint64]*eth1Types.Header
+	Delete(ctx context.Context, slot uint64, shardIndex uint64)
+	Keys() []types.ShardSlot
 }
 
-// VanguardShardInfoCache interface for pandora sharding info cache
+// VanguardShardInfoCache interface for pandora sharding info cache. Sharding infos are keyed by slot and shard index.
 type VanguardShardInfoCache interface {
-	Put(ctx context.Context, slot uint64, shardInfo *types.VanguardShardInfo) error
-	Get(ctx context.Context, slot uint64) (*types.VanguardShardInfo, error)
-	Remove(ctx context.Context, slot uint64)
+	Put(ctx context.Context, slot uint64, shardIndex uint64, shardInfo *types.VanguardShardInfo) error
+	Get(ctx context.Context, slot uint64, shardIndex uint64) (*types.VanguardShardInfo, error)
+	Remove(ctx context.Context, slot uint64, shardIndex uint64) map[uint64]*types.VanguardShardInfo
+	Delete(ctx context.Context, slot uint64, shardIndex uint64)
+	Keys() []types.ShardSlot
 }
@@ -6,6 +6,7 @@ import (
 
 	eth1Types "github.com/ethereum/go-ethereum/core/types"
 	lru "github.com/hashicorp/golang-lru"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
 )
 
@@ -13,6 +14,8 @@ import (
 type PanHeaderCache struct {
 	cache *lru.Cache
 	lock  sync.RWMutex
+	// store keeps the cached headers across restarts. It is nil for an in-memory cache.
+	store db.PendingHeaderDB
 }
 
 // NewPanHeaderCache initializes the map and underlying cache.
@@ -26,16 +29,56 @@ func NewPanHeaderCache() *PanHeaderCache {
 	}
 }
 
+// NewPersistentPanHeaderCache initializes the cache which writes its headers through to the db. Headers which are
+// left in the db by the previous run are loaded into the cache.
+func NewPersistentPanHeaderCache(store db.PendingHeaderDB) (*PanHeaderCache, error) {
+	cache, err := lru.New(maxCacheSize)
+	if err != nil {
+		return nil, err
+	}
+	c := &PanHeaderCache{cache: cache, store: store}
+
+	headerInfos, err := store.PendingHeaders()
+	if err != nil {
+		return nil, err
+	}
+	for _, headerInfo := range headerInfos {
+		// headers beyond the cache size are evicted like in Put so their rows must not outlive them
+		c.add(types.ShardSlot{Slot: headerInfo.Slot, ShardIndex: headerInfo.ShardIndex}, headerInfo.Header)
+	}
+	return c, nil
+}
+
 // Put
-func (c *PanHeaderCache) Put(ctx context.Context, slot uint64, header *eth1Types.Header) error {
+func (c *PanHeaderCache) Put(ctx context.Context, slot uint64, shardIndex uint64, header *eth1Types.Header) error {
 	copyHeader := types.CopyHeader(header)
-	c.cache.Add(slot, copyHeader)
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.store != nil {
+		if err := c.store.SavePendingHeader(&types.PandoraHeaderInfo{
+			Slot:       slot,
+			ShardIndex: shardIndex,
+			Header:     copyHeader,
+		}); err != nil {
+			return err
+		}
+	}
+	c.add(types.ShardSlot{Slot: slot, ShardIndex: shardIndex}, copyHeader)
 	return nil
 }
 
+// add adds the header to the cache and removes the evicted oldest header from the db as well
+func (c *PanHeaderCache) add(key types.ShardSlot, header *eth1Types.Header) {
+	oldest, _, _ := c.cache.GetOldest()
+	if evicted := c.cache.Add(key, header); evicted {
+		c.deletePending(oldest.(types.ShardSlot))
+	}
+}
+
 // Get
-func (c *PanHeaderCache) Get(ctx context.Context, slot uint64) (*eth1Types.Header, error) {
-	item, exists := c.cache.Get(slot)
+func (c *PanHeaderCache) Get(ctx context.Context, slot uint64, shardIndex uint64) (*eth1Types.Header, error) {
+	item, exists := c.cache.Get(types.ShardSlot{Slot: slot, ShardIndex: shardIndex})
 	if exists && item != nil {
 		header := item.(*eth1Types.Header)
 		copiedHeader := types.CopyHeader(header)
@@ -44,13 +87,58 @@ func (c *PanHeaderCache) Get(ctx context.Context, slot uint64) (*eth1Types.Heade
 	return nil, errInvalidSlot
 }
 
-func (c *PanHeaderCache) Remove(ctx context.Context, slot uint64) {
-	for i := slot; i > 0; i-- {
-		if c.cache.Contains(i) {
-			// removed all the previous slot number from cache. Now return
-			c.cache.Remove(i)
+// Remove removes the header of the given slot and all the previous slots of the shard from the cache.
+// It returns the removed headers keyed by slot.
+func (c *PanHeaderCache) Remove(ctx context.Context, slot uint64, shardIndex uint64) map[uint64]*eth1Types.Header {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	removedHeaders := make(map[uint64]*eth1Types.Header)
+	for _, item := range c.cache.Keys() {
+		key := item.(types.ShardSlot)
+		if key.ShardIndex != shardIndex || key.Slot > slot {
+			continue
+		}
+		if header, exists := c.cache.Peek(key); exists {
+			c.cache.Remove(key)
+			c.deletePending(key)
+			if header != nil {
+				removedHeaders[key.Slot] = header.(*eth1Types.Header)
+			}
 		}
 	}
+	return removedHeaders
+}
+
+// Delete removes the header of the given slot and shard only
+func (c *PanHeaderCache) Delete(ctx context.Context, slot uint64, shardIndex uint64) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	key := types.ShardSlot{Slot: slot, ShardIndex: shardIndex}
+	c.cache.Remove(key)
+	c.deletePending(key)
+}
+
+// deletePending removes the header from the db of a persistent cache
+func (c *PanHeaderCache) deletePending(key types.ShardSlot) {
+	if c.store == nil {
+		return
+	}
+	if err := c.store.DeletePendingHeader(key.Slot, key.ShardIndex); err != nil {
+		log.WithField("slot", key.Slot).WithField("shardIndex", key.ShardIndex).WithError(err).
+			Warn("Failed to delete pending header from db")
+	}
+}
+
+// Keys returns the shard slots of all the cached headers
+func (c *PanHeaderCache) Keys() []types.ShardSlot {
+	keys := c.cache.Keys()
+	shardSlots := make([]types.ShardSlot, 0, len(keys))
+	for _, key := range keys {
+		shardSlots = append(shardSlots, key.(types.ShardSlot))
+	}
+	return shardSlots
 }
 
 func (c *PanHeaderCache) GetAll() ([]*eth1Types.Header, error) {
@@ -58,8 +146,7 @@ func (c *PanHeaderCache) GetAll() ([]*eth1Types.Header, error) {
 	pendingHeaders := make([]*eth1Types.Header, 0)
 
 	for _, key := range keys {
-		slot := key.(uint64)
-		item, exists := c.cache.Get(slot)
+		item, exists := c.cache.Get(key)
 		if exists && item != nil {
 			header := item.(*eth1Types.Header)
 			copiedHeader := types.CopyHeader(header)
@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	testDB "github.com/lukso-network/lukso-orchestrator/orchestrator/db/testing"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
@@ -28,8 +29,8 @@ func Test_PandoraHeaderCache_Apis(t *testing.T) {
 
 	for slot := 1; slot <= 100; slot++ {
 		slotUint64 := uint64(slot)
-		pc.Put(ctx, slotUint64, expectedPanHeaders[slotUint64])
-		actualHeader, err := pc.Get(ctx, slotUint64)
+		pc.Put(ctx, slotUint64, 0, expectedPanHeaders[slotUint64])
+		actualHeader, err := pc.Get(ctx, slotUint64, 0)
 		require.NoError(t, err)
 		assert.DeepEqual(t, expectedPanHeaders[slotUint64], actualHeader)
 	}
@@ -44,14 +45,14 @@ func Test_PandoraHeaderCache_Size(t *testing.T) {
 
 	for slot := 1; slot <= 100; slot++ {
 		slotUint64 := uint64(slot)
-		pc.Put(ctx, slotUint64, expectedPanHeaders[slotUint64])
+		pc.Put(ctx, slotUint64, 0, expectedPanHeaders[slotUint64])
 	}
 
 	// Should not found slot-0 because cache size is 10
-	actualHeader, err := pc.Get(ctx, 88)
+	actualHeader, err := pc.Get(ctx, 88, 0)
 	require.ErrorContains(t, "Invalid slot", err, "Should not found because cache size is 10")
 
-	actualHeader, err = pc.Get(ctx, 91)
+	actualHeader, err = pc.Get(ctx, 91, 0)
 	require.NoError(t, err, "Should be found slot 90")
 	assert.DeepEqual(t, expectedPanHeaders[91], actualHeader)
 }
@@ -64,21 +65,21 @@ func Test_PandoraHeaderRemoveCache(t *testing.T) {
 
 	for slot := 1; slot <= 100; slot++ {
 		slotUint64 := uint64(slot)
-		pc.Put(ctx, slotUint64, expectedPanHeaders[slotUint64])
+		pc.Put(ctx, slotUint64, 0, expectedPanHeaders[slotUint64])
 	}
 	// now remove a slot from the cache and check if previous slots are removed
 	removedSlotNumber := uint64(rand.Int31n(80))
 	// slot is removed
-	pc.Remove(ctx, removedSlotNumber)
+	pc.Remove(ctx, removedSlotNumber, 0)
 
 	// now all slots from removedSlotNumber to 0 is null
 	for i := int(removedSlotNumber); i > 0; i-- {
-		_, err := pc.Get(ctx, uint64(i))
+		_, err := pc.Get(ctx, uint64(i), 0)
 		require.ErrorContains(t, "Invalid slot", err, "Should not be found because it is removed")
 	}
 
 	for i := int(removedSlotNumber) + 1; i <= 100; i++ {
-		actualHeader, err := pc.Get(ctx, uint64(i))
+		actualHeader, err := pc.Get(ctx, uint64(i), 0)
 		require.NoError(t, err, "Should be found slot")
 		assert.DeepEqual(t, expectedPanHeaders[uint64(i)], actualHeader)
 	}
@@ -93,10 +94,82 @@ func Test_PandoraHeaderGetAll(t *testing.T) {
 
 	for slot := 1; slot <= 100; slot++ {
 		slotUint64 := uint64(slot)
-		pc.Put(ctx, slotUint64, expectedPanHeaders[slotUint64])
+		pc.Put(ctx, slotUint64, 0, expectedPanHeaders[slotUint64])
 	}
 
 	actualPanHeaders, err := pc.GetAll()
 	require.NoError(t, err)
 	assert.Equal(t, len(expectedPanHeaders), len(actualPanHeaders))
 }
+
+func Test_PandoraHeaderCache_Shards(t *testing.T) {
+	maxCacheSize = 1 << 10
+	pc := NewPanHeaderCache()
+	ctx := context.Background()
+	setup(10)
+
+	for slot := uint64(1); slot <= 10; slot++ {
+		require.NoError(t, pc.Put(ctx, slot, 0, expectedPanHeaders[slot]))
+		require.NoError(t, pc.Put(ctx, slot, 1, expectedPanHeaders[slot]))
+	}
+	// removing slots of shard 0 keeps the headers of shard 1
+	removedHeaders := pc.Remove(ctx, 5, 0)
+	assert.Equal(t, 5, len(removedHeaders))
+	_, err := pc.Get(ctx, 5, 0)
+	require.ErrorContains(t, "Invalid slot", err)
+	actualHeader, err := pc.Get(ctx, 5, 1)
+	require.NoError(t, err)
+	assert.DeepEqual(t, expectedPanHeaders[5], actualHeader)
+
+	pc.Delete(ctx, 6, 1)
+	assert.Equal(t, 14, len(pc.Keys()))
+}
+
+func Test_PersistentPandoraHeaderCache(t *testing.T) {
+	maxCacheSize = 1 << 10
+	db := testDB.SetupDB(t)
+	ctx := context.Background()
+	setup(10)
+
+	pc, err := NewPersistentPanHeaderCache(db)
+	require.NoError(t, err)
+	for slot := uint64(1); slot <= 10; slot++ {
+		require.NoError(t, pc.Put(ctx, slot, 0, expectedPanHeaders[slot]))
+	}
+	pc.Remove(ctx, 5, 0)
+	pc.Delete(ctx, 7, 0)
+
+	// headers which are still pending are reloaded after restart
+	reloadedCache, err := NewPersistentPanHeaderCache(db)
+	require.NoError(t, err)
+	assert.Equal(t, 4, len(reloadedCache.Keys()))
+	for _, slot := range []uint64{6, 8, 9, 10} {
+		actualHeader, err := reloadedCache.Get(ctx, slot, 0)
+		require.NoError(t, err)
+		assert.Equal(t, expectedPanHeaders[slot].Hash(), actualHeader.Hash())
+	}
+}
+
+func Test_PersistentPandoraHeaderCache_TrimOnLoad(t *testing.T) {
+	maxCacheSize = 1 << 10
+	db := testDB.SetupDB(t)
+	ctx := context.Background()
+	setup(10)
+
+	pc, err := NewPersistentPanHeaderCache(db)
+	require.NoError(t, err)
+	for slot := uint64(1); slot <= 10; slot++ {
+		require.NoError(t, pc.Put(ctx, slot, 0, expectedPanHeaders[slot]))
+	}
+
+	// headers which do not fit into a smaller cache are evicted together with their rows
+	maxCacheSize = 3
+	defer func() { maxCacheSize = 1 << 10 }()
+	smallCache, err := NewPersistentPanHeaderCache(db)
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(smallCache.Keys()))
+	headerInfos, err := db.PendingHeaders()
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(headerInfos))
+	assert.Equal(t, uint64(8), headerInfos[0].Slot)
+}
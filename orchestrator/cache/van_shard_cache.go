@@ -3,6 +3,7 @@ package cache
 import (
 	"context"
 	lru "github.com/hashicorp/golang-lru"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
 	"sync"
 )
@@ -11,6 +12,8 @@ import (
 type VanShardingInfoCache struct {
 	cache *lru.Cache
 	lock  sync.RWMutex
+	// store keeps the cached sharding infos across restarts. It is nil for an in-memory cache.
+	store db.PendingShardInfoDB
 }
 
 // NewVanShardInfoCache initializes the map and underlying cache.
@@ -24,15 +27,56 @@ func NewVanShardInfoCache(cacheSize int) *VanShardingInfoCache {
 	}
 }
 
+// NewPersistentVanShardInfoCache initializes the cache which writes its sharding infos through to the db. Sharding
+// infos which are left in the db by the previous run are loaded into the cache.
+func NewPersistentVanShardInfoCache(cacheSize int, store db.PendingShardInfoDB) (*VanShardingInfoCache, error) {
+	cache, err := lru.New(cacheSize)
+	if err != nil {
+		return nil, err
+	}
+	vc := &VanShardingInfoCache{cache: cache, store: store}
+
+	shardInfos, err := store.PendingShardInfos()
+	if err != nil {
+		return nil, err
+	}
+	for _, shardInfo := range shardInfos {
+		// sharding infos beyond the cache size are evicted like in Put so their rows must not outlive them
+		vc.add(types.ShardSlot{Slot: shardInfo.Slot, ShardIndex: shardInfo.ShardIndex}, shardInfo)
+	}
+	return vc, nil
+}
+
 // Put puts sharding info into a lru cache. return error if fails.
-func (vc *VanShardingInfoCache) Put(ctx context.Context, slot uint64, shardInfo *types.VanguardShardInfo) error {
-	vc.cache.Add(slot, shardInfo)
+func (vc *VanShardingInfoCache) Put(
+	ctx context.Context,
+	slot uint64,
+	shardIndex uint64,
+	shardInfo *types.VanguardShardInfo,
+) error {
+	vc.lock.Lock()
+	defer vc.lock.Unlock()
+
+	if vc.store != nil {
+		if err := vc.store.SavePendingShardInfo(shardInfo); err != nil {
+			return err
+		}
+	}
+	vc.add(types.ShardSlot{Slot: slot, ShardIndex: shardIndex}, shardInfo)
 	return nil
 }
 
+// add adds the sharding info to the cache and removes the evicted oldest sharding info from the db as well
+func (vc *VanShardingInfoCache) add(key types.ShardSlot, shardInfo *types.VanguardShardInfo) {
+	oldest, _, _ := vc.cache.GetOldest()
+	if evicted := vc.cache.Add(key, shardInfo); evicted {
+		vc.deletePending(oldest.(types.ShardSlot))
+	}
+}
+
 // Get retrieves sharding info from a cache. returns error if fails
-func (vc *VanShardingInfoCache) Get(ctx context.Context, slot uint64) (*types.VanguardShardInfo, error) {
-	item, exists := vc.cache.Get(slot)
+func (vc *VanShardingInfoCache) Get(ctx context.Context, slot uint64, shardIndex uint64) (*types.VanguardShardInfo, error) {
+	item, exists := vc.cache.Get(types.ShardSlot{Slot: slot, ShardIndex: shardIndex})
 	if exists && item != nil {
 		shardingInfo := item.(*types.VanguardShardInfo)
 		return shardingInfo, nil
@@ -40,11 +84,60 @@ func (vc *VanShardingInfoCache) Get(ctx context.Context, slot uint64) (*types.Va
 	return nil, errInvalidSlot
 }
 
-func (vc *VanShardingInfoCache) Remove(ctx context.Context, slot uint64) {
-	for i := slot; i > 0; i-- {
-		if vc.cache.Contains(i) {
-			// removed all the previous slot number from cache. Now return
-			vc.cache.Remove(i)
+// Remove removes sharding info of the given slot and all the previous slots of the shard from the cache.
+// It returns the removed sharding infos keyed by slot.
+func (vc *VanShardingInfoCache) Remove(
+	ctx context.Context,
+	slot uint64,
+	shardIndex uint64,
+) map[uint64]*types.VanguardShardInfo {
+	vc.lock.Lock()
+	defer vc.lock.Unlock()
+
+	removedShardInfos := make(map[uint64]*types.VanguardShardInfo)
+	for _, item := range vc.cache.Keys() {
+		key := item.(types.ShardSlot)
+		if key.ShardIndex != shardIndex || key.Slot > slot {
+			continue
 		}
+		if shardInfo, exists := vc.cache.Peek(key); exists {
+			vc.cache.Remove(key)
+			vc.deletePending(key)
+			if shardInfo != nil {
+				removedShardInfos[key.Slot] = shardInfo.(*types.VanguardShardInfo)
+			}
+		}
+	}
+	return removedShardInfos
+}
+
+// Delete removes sharding info of the given slot and shard only
+func (vc *VanShardingInfoCache) Delete(ctx context.Context, slot uint64, shardIndex uint64) {
+	vc.lock.Lock()
+	defer vc.lock.Unlock()
+
+	key := types.ShardSlot{Slot: slot, ShardIndex: shardIndex}
+	vc.cache.Remove(key)
+	vc.deletePending(key)
+}
+
+// deletePending removes the sharding info from the db of a persistent cache
+func (vc *VanShardingInfoCache) deletePending(key types.ShardSlot) {
+	if vc.store == nil {
+		return
+	}
+	if err := vc.store.DeletePendingShardInfo(key.Slot, key.ShardIndex); err != nil {
+		log.WithField("slot", key.Slot).WithField("shardIndex", key.ShardIndex).WithError(err).
+			Warn("Failed to delete pending sharding info from db")
+	}
+}
+
+// Keys returns the shard slots of all the cached sharding infos
+func (vc *VanShardingInfoCache) Keys() []types.ShardSlot {
+	keys := vc.cache.Keys()
+	shardSlots := make([]types.ShardSlot, 0, len(keys))
+	for _, key := range keys {
+		shardSlots = append(shardSlots, key.(types.ShardSlot))
 	}
+	return shardSlots
 }
@@ -6,6 +6,8 @@ import (
 	"math/rand"
 	"testing"
 
+	testDB "github.com/lukso-network/lukso-orchestrator/orchestrator/db/testing"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
@@ -82,11 +84,11 @@ func TestVanguardShardingInfoCacheAPIs(t *testing.T) {
 	}
 
 	for slotNumber, genInfo := range generatedShardInfos {
-		err := vanguardCache.Put(ctx, slotNumber, genInfo)
+		err := vanguardCache.Put(ctx, slotNumber, 0, genInfo)
 		if err != nil {
 			t.Error("failed while putting element vanguard cache", "slot number", slotNumber, "error", err)
 		}
-		receivedDataFromCache, err := vanguardCache.Get(ctx, slotNumber)
+		receivedDataFromCache, err := vanguardCache.Get(ctx, slotNumber, 0)
 		if err != nil {
 			t.Error("failed while retrieving data from the vanguard cache", "slot number", slotNumber, "error", err)
 		}
@@ -109,14 +111,14 @@ func TestVanguardShardingInfoCacheSize(t *testing.T) {
 
 	for slot := 1; slot <= 100; slot++ {
 		slotUint64 := uint64(slot)
-		vanguardCache.Put(ctx, slotUint64, generatedShardInfos[slotUint64])
+		vanguardCache.Put(ctx, slotUint64, 0, generatedShardInfos[slotUint64])
 	}
 
 	// Should not found slot-0 because cache size is 10
-	actualHeader, err := vanguardCache.Get(ctx, 88)
+	actualHeader, err := vanguardCache.Get(ctx, 88, 0)
 	require.ErrorContains(t, "Invalid slot", err, "Should not be found because cache size is 10")
 
-	actualHeader, err = vanguardCache.Get(ctx, 91)
+	actualHeader, err = vanguardCache.Get(ctx, 91, 0)
 	require.NoError(t, err, "Should be found slot 90")
 	assert.DeepEqual(t, generatedShardInfos[91], actualHeader)
 
@@ -134,23 +136,67 @@ func TestVanguardRemoveShardInfo(t *testing.T) {
 
 	for slot := 1; slot < 100; slot++ {
 		slotUint64 := uint64(slot)
-		vanguardCache.Put(ctx, slotUint64, generatedShardInfos[slotUint64])
+		vanguardCache.Put(ctx, slotUint64, 0, generatedShardInfos[slotUint64])
 	}
 
 	// now remove a slot from the cache and check if previous slots are removed
 	removedSlotNumber := uint64(rand.Int31n(100))
 	// slot is removed
-	vanguardCache.Remove(ctx, removedSlotNumber)
+	vanguardCache.Remove(ctx, removedSlotNumber, 0)
 
 	// now all slots from removedSlotNumber to 0 is null
 	for i := int(removedSlotNumber); i >= 0; i-- {
-		_, err := vanguardCache.Get(ctx, uint64(i))
+		_, err := vanguardCache.Get(ctx, uint64(i), 0)
 		require.ErrorContains(t, "Invalid slot", err, "Should not be found because it is removed")
 	}
 
 	for i := int(removedSlotNumber) + 1; i < 100; i++ {
-		actualHeader, err := vanguardCache.Get(ctx, uint64(i))
+		actualHeader, err := vanguardCache.Get(ctx, uint64(i), 0)
 		require.NoError(t, err, "Should be found slot")
 		assert.DeepEqual(t, generatedShardInfos[uint64(i)], actualHeader)
 	}
 }
+
+func TestPersistentVanShardInfoCache(t *testing.T) {
+	db := testDB.SetupDB(t)
+	ctx := context.Background()
+
+	vc, err := NewPersistentVanShardInfoCache(1<<10, db)
+	require.NoError(t, err)
+	for slot := uint64(1); slot <= 10; slot++ {
+		shardInfo := testutil.NewVanguardShardInfo(slot, testutil.NewEth1Header(slot))
+		require.NoError(t, vc.Put(ctx, slot, 0, shardInfo))
+	}
+	vc.Remove(ctx, 5, 0)
+
+	// sharding infos which are still pending are reloaded after restart
+	reloadedCache, err := NewPersistentVanShardInfoCache(1<<10, db)
+	require.NoError(t, err)
+	assert.Equal(t, 5, len(reloadedCache.Keys()))
+	shardInfo, err := reloadedCache.Get(ctx, 6, 0)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), shardInfo.Slot)
+	_, err = reloadedCache.Get(ctx, 5, 0)
+	require.ErrorContains(t, "Invalid slot", err)
+}
+
+func TestPersistentVanShardInfoCache_TrimOnLoad(t *testing.T) {
+	db := testDB.SetupDB(t)
+	ctx := context.Background()
+
+	vc, err := NewPersistentVanShardInfoCache(1<<10, db)
+	require.NoError(t, err)
+	for slot := uint64(1); slot <= 10; slot++ {
+		shardInfo := testutil.NewVanguardShardInfo(slot, testutil.NewEth1Header(slot))
+		require.NoError(t, vc.Put(ctx, slot, 0, shardInfo))
+	}
+
+	// sharding infos which do not fit into a smaller cache are evicted together with their rows
+	smallCache, err := NewPersistentVanShardInfoCache(3, db)
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(smallCache.Keys()))
+	shardInfos, err := db.PendingShardInfos()
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(shardInfos))
+	assert.Equal(t, uint64(8), shardInfos[0].Slot)
+}
@@ -0,0 +1,280 @@
+package consensus
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"sort"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// catchUpPair is a slot whose pandora header and vanguard shard info have both arrived in the same catch-up batch
+type catchUpPair struct {
+	headerInfo     *types.PandoraHeaderInfo
+	shardInfo      *types.VanguardShardInfo
+	headerIndex    int
+	shardInfoIndex int
+	verdicts       []*types.RuleVerdict
+	reason         types.MismatchReason
+	verification   *VerificationInput
+}
+
+// isCatchingUp tells whether more events are already queued behind the received one. It happens when the
+// orchestrator is behind vanguard and pandora, for example after a reconnect.
+func (s *Service) isCatchingUp(panHeaderInfoCh chan *types.PandoraHeaderInfo, vanShardInfoCh chan *types.VanguardShardInfo) bool {
+	return s.catchUpBatchSize > 1 && len(panHeaderInfoCh)+len(vanShardInfoCh) > 0
+}
+
+// queuedEvents collects the received event and the queued pandora header infos and vanguard shard infos without
+// waiting, until the batch is full
+func (s *Service) queuedEvents(
+	first interface{},
+	panHeaderInfoCh chan *types.PandoraHeaderInfo,
+	vanShardInfoCh chan *types.VanguardShardInfo,
+) []interface{} {
+	events := []interface{}{first}
+	for uint64(len(events)) < s.catchUpBatchSize {
+		select {
+		case headerInfo := <-panHeaderInfoCh:
+			events = append(events, headerInfo)
+		case shardInfo := <-vanShardInfoCh:
+			events = append(events, shardInfo)
+		default:
+			return events
+		}
+	}
+	return events
+}
+
+// StepBatch processes a batch of queued pandora header infos and vanguard shard infos. Slots whose header and shard
+// info are both in the batch are verified in parallel and every linked run of verified slots of a shard is stored in
+// one transaction. The rest of the events are processed one by one in their order, like Step does. Unpaired events
+// of slots before a stored run are skipped, they would stay pending behind the verified chain otherwise.
+func (s *Service) StepBatch(events []interface{}) error {
+	pairs, err := s.matchCatchUpEvents(events)
+	if err != nil {
+		return err
+	}
+	s.verifyCatchUpPairs(pairs)
+
+	consumed := make(map[int]bool)
+	committedShards := make(map[uint64]bool)
+	for shardIndex, shardPairs := range pairsByShard(pairs) {
+		committed, err := s.commitVerifiedRun(shardPairs)
+		if err != nil {
+			return err
+		}
+		for _, pair := range committed {
+			consumed[pair.headerIndex] = true
+			consumed[pair.shardInfoIndex] = true
+		}
+		if len(committed) > 0 {
+			committedShards[shardIndex] = true
+		}
+	}
+
+	for i, ev := range events {
+		if consumed[i] {
+			continue
+		}
+		if err := s.Step(ev); err != nil {
+			return err
+		}
+	}
+	for shardIndex := range committedShards {
+		if err := s.skipPendingSlotsBeforeVerified(shardIndex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// skipPendingSlotsBeforeVerified marks the pending slots of the shard up to the latest verified slot as skipped
+func (s *Service) skipPendingSlotsBeforeVerified(shardIndex uint64) error {
+	latestVerifiedSlot := s.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(shardIndex)
+	removedHeaders := s.pandoraPendingHeaderCache.Remove(s.ctx, latestVerifiedSlot, shardIndex)
+	removedShardInfos := s.vanguardPendingShardingCache.Remove(s.ctx, latestVerifiedSlot, shardIndex)
+	return s.markSkippedSlots(latestVerifiedSlot, shardIndex, removedHeaders, removedShardInfos)
+}
+
+// matchCatchUpEvents pairs the pandora header info and vanguard shard info of the same slot. A slot is only paired
+// when it has exactly one header and one shard info in the batch and nothing is known about it yet, so that
+// duplicates, equivocations and reorgs are still handled by the single event path.
+func (s *Service) matchCatchUpEvents(events []interface{}) ([]*catchUpPair, error) {
+	headerIndexes := make(map[types.ShardSlot][]int)
+	shardInfoIndexes := make(map[types.ShardSlot][]int)
+	for i, ev := range events {
+		switch ev := ev.(type) {
+		case *types.PandoraHeaderInfo:
+			shardSlot := types.ShardSlot{Slot: ev.Slot, ShardIndex: ev.ShardIndex}
+			headerIndexes[shardSlot] = append(headerIndexes[shardSlot], i)
+		case *types.VanguardShardInfo:
+			shardSlot := types.ShardSlot{Slot: ev.Slot, ShardIndex: ev.ShardIndex}
+			shardInfoIndexes[shardSlot] = append(shardInfoIndexes[shardSlot], i)
+		case *types.FinalizedCheckpoint, *types.MinimalEpochConsensusInfo:
+		default:
+			return nil, fmt.Errorf("unsupported consensus event %T", ev)
+		}
+	}
+
+	pairs := make([]*catchUpPair, 0)
+	for shardSlot, indexes := range headerIndexes {
+		if len(indexes) != 1 || len(shardInfoIndexes[shardSlot]) != 1 || !s.isUnknownSlot(shardSlot) {
+			continue
+		}
+		pair := &catchUpPair{
+			headerInfo:     events[indexes[0]].(*types.PandoraHeaderInfo),
+			shardInfo:      events[shardInfoIndexes[shardSlot][0]].(*types.VanguardShardInfo),
+			headerIndex:    indexes[0],
+			shardInfoIndex: shardInfoIndexes[shardSlot][0],
+		}
+		pair.verification = s.verificationInput(shardSlot.Slot, pair.shardInfo, pair.headerInfo.Header)
+		pairs = append(pairs, pair)
+	}
+	return pairs, nil
+}
+
+// eventShardSlot returns the slot of a pandora header info or a vanguard shard info
+func eventShardSlot(ev interface{}) (types.ShardSlot, bool) {
+	switch ev := ev.(type) {
+	case *types.PandoraHeaderInfo:
+		return types.ShardSlot{Slot: ev.Slot, ShardIndex: ev.ShardIndex}, true
+	case *types.VanguardShardInfo:
+		return types.ShardSlot{Slot: ev.Slot, ShardIndex: ev.ShardIndex}, true
+	}
+	return types.ShardSlot{}, false
+}
+
+// isUnknownSlot returns true when the slot of the shard has no stored status and nothing pending in the caches
+func (s *Service) isUnknownSlot(shardSlot types.ShardSlot) bool {
+	slot, shardIndex := shardSlot.Slot, shardSlot.ShardIndex
+	if s.isDecidedSlot(slot, shardIndex) {
+		return false
+	}
+	if slotInfo, _ := s.skippedSlotInfoDB.SkippedSlotInfo(slot, shardIndex); slotInfo != nil {
+		return false
+	}
+	if slotInfo, _ := s.timedOutSlotInfoDB.TimedOutSlotInfo(slot, shardIndex); slotInfo != nil {
+		return false
+	}
+	if header, _ := s.pandoraPendingHeaderCache.Get(s.ctx, slot, shardIndex); header != nil {
+		return false
+	}
+	if shardInfo, _ := s.vanguardPendingShardingCache.Get(s.ctx, slot, shardIndex); shardInfo != nil {
+		return false
+	}
+	return true
+}
+
+// verifyCatchUpPairs runs the verification rules of the pairs in parallel. Rules only read their input, so the
+// expensive signature checks of a batch are spread over all cpus.
+func (s *Service) verifyCatchUpPairs(pairs []*catchUpPair) {
+	pairCh := make(chan *catchUpPair)
+	var wg sync.WaitGroup
+	for worker := 0; worker < runtime.NumCPU(); worker++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for pair := range pairCh {
+				pair.verdicts, pair.reason = s.verifiers.Verify(pair.verification)
+			}
+		}()
+	}
+	for _, pair := range pairs {
+		pairCh <- pair
+	}
+	close(pairCh)
+	wg.Wait()
+}
+
+// commitVerifiedRun stores the longest run of valid pairs which extends the verified chain of the shard in one
+// transaction and notifies the subscribers. Pairs are sorted by slot. It returns the committed pairs, the others are
+// left to the single event path.
+func (s *Service) commitVerifiedRun(pairs []*catchUpPair) ([]*catchUpPair, error) {
+	shardIndex := pairs[0].headerInfo.ShardIndex
+	latestVerifiedSlot := s.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(shardIndex)
+	latestHeaderHash := s.verifiedSlotInfoDB.InMemoryLatestVerifiedHeaderHash(shardIndex)
+
+	verifiedSlots := make([]*types.VerifiedSlot, 0, len(pairs))
+	committed := make([]*catchUpPair, 0, len(pairs))
+	for _, pair := range pairs {
+		slot, header := pair.headerInfo.Slot, pair.headerInfo.Header
+		if pair.reason != types.NoMismatch {
+			break
+		}
+		if latestHeaderHash != (common.Hash{}) && (slot <= latestVerifiedSlot || header.ParentHash != latestHeaderHash) {
+			break
+		}
+		verifiedSlots = append(verifiedSlots, &types.VerifiedSlot{
+			Slot: slot,
+			SlotInfo: &types.SlotInfo{
+				PandoraHeaderHash: header.Hash(),
+				VanguardBlockHash: common.BytesToHash(pair.shardInfo.BlockHash[:]),
+			},
+			Header:    header,
+			ShardInfo: pair.shardInfo,
+			Verdicts:  pair.verdicts,
+		})
+		latestVerifiedSlot, latestHeaderHash = slot, header.Hash()
+		committed = append(committed, pair)
+	}
+	if len(committed) == 0 {
+		return committed, nil
+	}
+
+	// verdicts, payloads, slot infos and the latest pointers of the whole run are stored in one transaction
+	if err := s.verifiedSlotInfoDB.CommitVerifiedSlots(shardIndex, verifiedSlots); err != nil {
+		var conflictErr *db.VerifiedSlotConflictError
+		if errors.As(err, &conflictErr) {
+			// nothing is stored, the pairs are left to the single event path
+			log.WithField("shardIndex", shardIndex).WithError(err).
+				Warn("Catch-up run does not extend the verified chain anymore")
+			return []*catchUpPair{}, nil
+		}
+		log.WithField("shardIndex", shardIndex).WithError(err).Error("Failed to commit verified catch-up run")
+		return nil, err
+	}
+
+	s.closeVerificationGap(latestVerifiedSlot, shardIndex)
+	// pending slots before the run will never be verified
+	if err := s.skipPendingSlotsBeforeVerified(shardIndex); err != nil {
+		return nil, err
+	}
+	for _, verifiedSlot := range verifiedSlots {
+		slotInfo := verifiedSlot.SlotInfo
+		s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+			PandoraHeaderHash: slotInfo.PandoraHeaderHash,
+			VanguardBlockHash: slotInfo.VanguardBlockHash,
+			ShardIndex:        shardIndex,
+			Status:            types.Verified,
+		})
+	}
+	log.WithField("fromSlot", committed[0].headerInfo.Slot).WithField("toSlot", latestVerifiedSlot).
+		WithField("shardIndex", shardIndex).WithField("count", len(committed)).
+		Info("Successfully verified sharding infos in catch-up batch")
+
+	if err := s.finalizeVerifiedSlots(shardIndex); err != nil {
+		return nil, err
+	}
+	if err := s.verifyPendingChild(latestVerifiedSlot, shardIndex, latestHeaderHash); err != nil {
+		return nil, err
+	}
+	return committed, nil
+}
+
+// pairsByShard groups the pairs by shard index and sorts every group by slot
+func pairsByShard(pairs []*catchUpPair) map[uint64][]*catchUpPair {
+	shardPairs := make(map[uint64][]*catchUpPair)
+	for _, pair := range pairs {
+		shardIndex := pair.headerInfo.ShardIndex
+		shardPairs[shardIndex] = append(shardPairs[shardIndex], pair)
+	}
+	for _, pairs := range shardPairs {
+		sort.Slice(pairs, func(i, j int) bool { return pairs[i].headerInfo.Slot < pairs[j].headerInfo.Slot })
+	}
+	return shardPairs
+}
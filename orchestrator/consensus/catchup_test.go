@@ -0,0 +1,175 @@
+package consensus
+
+import (
+	"context"
+	"sort"
+	"testing"
+
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// catchUpEvents interleaves the pandora header infos and vanguard shard infos in the order of arrival
+func catchUpEvents(headerInfos []*types.PandoraHeaderInfo, shardInfos []*types.VanguardShardInfo) []interface{} {
+	events := make([]interface{}, 0, len(headerInfos)+len(shardInfos))
+	for i := range headerInfos {
+		events = append(events, headerInfos[i])
+		if i < len(shardInfos) {
+			events = append(events, shardInfos[i])
+		}
+	}
+	return events
+}
+
+// saveConsensusInfos stores the consensus infos of the epochs after the genesis epoch which is stored by setup
+func saveConsensusInfos(tb testing.TB, svc *Service, toEpoch uint64) {
+	consensusInfoDB := svc.consensusInfoDB.(db.ConsensusInfoAccessDB)
+	for epoch := uint64(1); epoch <= toEpoch; epoch++ {
+		require.NoError(tb, consensusInfoDB.SaveConsensusInfo(context.Background(), testutil.NewMinimalConsensusInfo(epoch)))
+	}
+}
+
+func TestService_StepBatch(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	saveConsensusInfos(t, svc, 2)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 65)
+	// the batch does not depend on the order of the events
+	events := catchUpEvents(headerInfos, shardInfos)
+	for i, j := 0, len(events)-1; i < j; i, j = i+1, j-1 {
+		events[i], events[j] = events[j], events[i]
+	}
+
+	statusCh := make(chan *types.SlotInfoWithStatus, len(headerInfos))
+	sub := svc.SubscribeVerifiedSlotInfoEvent(statusCh)
+	defer sub.Unsubscribe()
+
+	require.NoError(t, svc.StepBatch(events))
+
+	for _, headerInfo := range headerInfos {
+		slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(headerInfo.Slot, 0)
+		require.NoError(t, err)
+		require.NotNil(t, slotInfo)
+		assert.Equal(t, headerInfo.Header.Hash(), slotInfo.PandoraHeaderHash)
+
+		verdicts, err := svc.verdictDB.Verdicts(headerInfo.Slot, 0)
+		require.NoError(t, err)
+		assert.Equal(t, len(DefaultVerifierRules), len(verdicts))
+
+		header, err := svc.verifiedPayloadDB.VerifiedHeader(headerInfo.Slot, 0)
+		require.NoError(t, err)
+		require.NotNil(t, header)
+		assert.Equal(t, headerInfo.Header.Hash(), header.Hash())
+
+		status := <-statusCh
+		assert.Equal(t, types.Verified, status.Status)
+		assert.Equal(t, headerInfo.Header.Hash(), status.PandoraHeaderHash)
+	}
+	// latest pointers are stored together with the slot infos
+	assert.Equal(t, uint64(64), svc.verifiedSlotInfoDB.LatestSavedVerifiedSlot(0))
+	assert.Equal(t, headerInfos[63].Header.Hash(), svc.verifiedSlotInfoDB.LatestVerifiedHeaderHash(0))
+	assert.Equal(t, 0, len(svc.pandoraPendingHeaderCache.Keys()))
+	assert.Equal(t, 0, len(svc.vanguardPendingShardingCache.Keys()))
+}
+
+// TestService_StepBatch_SameAsStep checks that a batch decides every slot in the same way as processing its events
+// one by one
+func TestService_StepBatch_SameAsStep(t *testing.T) {
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 32)
+	// slot 10 is invalid, so later slots are not linked to the verified chain. Slot 20 gets its shard info twice
+	shardInfos[9] = testutil.NewVanguardShardInfo(10, testutil.NewEth1Header(10))
+	shardInfos = append(shardInfos, shardInfos[19])
+	// slot 25 has no vanguard shard info
+	shardInfos = append(shardInfos[:24], shardInfos[25:]...)
+	events := catchUpEvents(headerInfos, shardInfos)
+
+	ctx := context.Background()
+	batchSvc, _ := setup(ctx, t)
+	require.NoError(t, batchSvc.StepBatch(events))
+	stepSvc, _ := setup(ctx, t)
+	for _, ev := range events {
+		require.NoError(t, stepSvc.Step(ev))
+	}
+
+	for _, headerInfo := range headerInfos {
+		slot := headerInfo.Slot
+		verifiedSlotInfo, err := stepSvc.verifiedSlotInfoDB.VerifiedSlotInfo(slot, 0)
+		require.NoError(t, err)
+		batchVerifiedSlotInfo, err := batchSvc.verifiedSlotInfoDB.VerifiedSlotInfo(slot, 0)
+		require.NoError(t, err)
+		assert.DeepEqual(t, verifiedSlotInfo, batchVerifiedSlotInfo, "slot %d", slot)
+
+		invalidSlotInfo, err := stepSvc.invalidSlotInfoDB.InvalidSlotInfo(slot, 0)
+		require.NoError(t, err)
+		batchInvalidSlotInfo, err := batchSvc.invalidSlotInfoDB.InvalidSlotInfo(slot, 0)
+		require.NoError(t, err)
+		assert.DeepEqual(t, invalidSlotInfo, batchInvalidSlotInfo, "slot %d", slot)
+
+		skippedSlotInfo, err := stepSvc.skippedSlotInfoDB.SkippedSlotInfo(slot, 0)
+		require.NoError(t, err)
+		batchSkippedSlotInfo, err := batchSvc.skippedSlotInfoDB.SkippedSlotInfo(slot, 0)
+		require.NoError(t, err)
+		assert.DeepEqual(t, skippedSlotInfo, batchSkippedSlotInfo, "slot %d", slot)
+	}
+	assert.DeepEqual(t, sortedKeys(stepSvc.pandoraPendingHeaderCache.Keys()),
+		sortedKeys(batchSvc.pandoraPendingHeaderCache.Keys()))
+	assert.DeepEqual(t, sortedKeys(stepSvc.vanguardPendingShardingCache.Keys()),
+		sortedKeys(batchSvc.vanguardPendingShardingCache.Keys()))
+	assert.Equal(t, uint64(9), batchSvc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, headerInfos[8].Header.Hash(), batchSvc.verifiedSlotInfoDB.InMemoryLatestVerifiedHeaderHash(0))
+}
+
+func TestService_StepBatch_UnpairedEarlierSlot(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 5)
+	// slot 1 has no pandora header and its shard info arrives after the later slots
+	events := append(catchUpEvents(headerInfos[1:], shardInfos[1:]), shardInfos[0])
+
+	require.NoError(t, svc.StepBatch(events))
+
+	assert.Equal(t, uint64(4), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	skippedSlotInfo, err := svc.skippedSlotInfoDB.SkippedSlotInfo(1, 0)
+	require.NoError(t, err)
+	require.NotNil(t, skippedSlotInfo)
+	assert.Equal(t, 0, len(svc.pandoraPendingHeaderCache.Keys()))
+	assert.Equal(t, 0, len(svc.vanguardPendingShardingCache.Keys()))
+}
+
+func sortedKeys(shardSlots []types.ShardSlot) []types.ShardSlot {
+	sort.Slice(shardSlots, func(i, j int) bool { return shardSlots[i].Slot < shardSlots[j].Slot })
+	return shardSlots
+}
+
+func BenchmarkService_Step(b *testing.B) {
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 257)
+	events := catchUpEvents(headerInfos, shardInfos)
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		svc, _ := setup(context.Background(), b)
+		saveConsensusInfos(b, svc, 8)
+		b.StartTimer()
+		for _, ev := range events {
+			if err := svc.Step(ev); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkService_StepBatch(b *testing.B) {
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 257)
+	events := catchUpEvents(headerInfos, shardInfos)
+	for i := 0; i < b.N; i++ {
+		b.StopTimer()
+		svc, _ := setup(context.Background(), b)
+		saveConsensusInfos(b, svc, 8)
+		b.StartTimer()
+		if err := svc.StepBatch(events); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -0,0 +1,16 @@
+package consensus
+
+import "time"
+
+// Clock tells the current time to the service. Tests inject a clock which is moved by hand so that the expiry of
+// pending slots is deterministic.
+type Clock interface {
+	Now() time.Time
+}
+
+// systemClock is the wall clock
+type systemClock struct{}
+
+func (systemClock) Now() time.Time {
+	return time.Now()
+}
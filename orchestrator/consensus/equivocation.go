@@ -0,0 +1,109 @@
+package consensus
+
+import (
+	"bytes"
+
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// detectPandoraEquivocation stores the evidence when the pandora header of a slot conflicts with the header which
+// the slot already holds, either pending or verified. Both headers must carry a valid signature of the slot
+// proposer, otherwise the proposer cannot be held accountable for them.
+func (s *Service) detectPandoraEquivocation(headerInfo *types.PandoraHeaderInfo) error {
+	slot, shardIndex := headerInfo.Slot, headerInfo.ShardIndex
+	knownHeader := s.knownPandoraHeader(slot, shardIndex)
+	if knownHeader == nil || knownHeader.Hash() == headerInfo.Header.Hash() {
+		return nil
+	}
+	if err := s.verifyPandoraSignature(slot, knownHeader); err != nil {
+		return nil
+	}
+	if err := s.verifyPandoraSignature(slot, headerInfo.Header); err != nil {
+		return nil
+	}
+	return s.saveEquivocation(&types.Equivocation{
+		Kind:           types.PandoraEquivocation,
+		Slot:           slot,
+		ShardIndex:     shardIndex,
+		PandoraHeaders: []*eth1Types.Header{knownHeader, headerInfo.Header},
+	})
+}
+
+// detectVanguardEquivocation stores the evidence when the vanguard shard info of a slot belongs to a different
+// vanguard block than the shard info which the slot already holds, either pending or verified. A different block
+// alone is no evidence, it is also delivered on an honest reorg. Both shard infos must carry a valid signature of
+// the slot proposer from the validator list of the epoch, so that both blocks come from the same proposer.
+func (s *Service) detectVanguardEquivocation(vanShardInfo *types.VanguardShardInfo) error {
+	slot, shardIndex := vanShardInfo.Slot, vanShardInfo.ShardIndex
+	knownShardInfo := s.knownVanguardShardInfo(slot, shardIndex)
+	if knownShardInfo == nil || bytes.Equal(knownShardInfo.BlockHash, vanShardInfo.BlockHash) {
+		return nil
+	}
+	if err := s.verifyShardInfoSignature(slot, knownShardInfo.ShardInfo); err != nil {
+		return nil
+	}
+	if err := s.verifyShardInfoSignature(slot, vanShardInfo.ShardInfo); err != nil {
+		return nil
+	}
+	return s.saveEquivocation(&types.Equivocation{
+		Kind:               types.VanguardEquivocation,
+		Slot:               slot,
+		ShardIndex:         shardIndex,
+		VanguardShardInfos: []*types.VanguardShardInfo{knownShardInfo, vanShardInfo},
+	})
+}
+
+// knownPandoraHeader returns the pending header of the slot or the stored header of the verified slot. It returns
+// nil when the slot holds no header or the payload of the verified slot is pruned.
+func (s *Service) knownPandoraHeader(slot uint64, shardIndex uint64) *eth1Types.Header {
+	if header, _ := s.pandoraPendingHeaderCache.Get(s.ctx, slot, shardIndex); header != nil {
+		return header
+	}
+	header, err := s.verifiedPayloadDB.VerifiedHeader(slot, shardIndex)
+	if err != nil {
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithError(err).
+			Warn("Could not read verified pandora header")
+		return nil
+	}
+	return header
+}
+
+// knownVanguardShardInfo returns the pending shard info of the slot or the stored shard info of the verified slot.
+// It returns nil when the slot holds no shard info or the payload of the verified slot is pruned.
+func (s *Service) knownVanguardShardInfo(slot uint64, shardIndex uint64) *types.VanguardShardInfo {
+	if shardInfo, _ := s.vanguardPendingShardingCache.Get(s.ctx, slot, shardIndex); shardInfo != nil {
+		return shardInfo
+	}
+	shardInfo, err := s.verifiedPayloadDB.VerifiedShardInfo(slot, shardIndex)
+	if err != nil {
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithError(err).
+			Warn("Could not read verified vanguard shard info")
+		return nil
+	}
+	return shardInfo
+}
+
+// saveEquivocation fills the slot proposer from the validator list of the slot's epoch into the evidence and
+// stores it into equivocation db
+func (s *Service) saveEquivocation(equivocation *types.Equivocation) error {
+	consensusInfo, err := s.consensusInfoBySlot(equivocation.Slot)
+	if err != nil {
+		return err
+	}
+	proposerIndex, proposerPubKey, err := slotProposer(equivocation.Slot, consensusInfo)
+	if err != nil {
+		return err
+	}
+	equivocation.ProposerIndex, equivocation.ProposerPublicKey = proposerIndex, proposerPubKey
+
+	if err := s.equivocationDB.SaveEquivocation(equivocation); err != nil {
+		log.WithField("slot", equivocation.Slot).WithField("shardIndex", equivocation.ShardIndex).
+			WithError(err).Error("Failed to store equivocation")
+		return err
+	}
+	log.WithField("slot", equivocation.Slot).WithField("shardIndex", equivocation.ShardIndex).
+		WithField("kind", equivocation.Kind).WithField("proposerIndex", equivocation.ProposerIndex).
+		Warn("Detected equivocation")
+	return nil
+}
@@ -0,0 +1,114 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestService_PandoraEquivocation(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, _ := getHeaderInfosAndShardInfos(1, 2)
+	require.NoError(t, svc.processPandoraHeader(headerInfos[0]))
+	// same header again is not an equivocation
+	require.NoError(t, svc.processPandoraHeader(headerInfos[0]))
+
+	// header which is not signed by the proposer is not an evidence
+	forgedHeader := testutil.NewEth1Header(1)
+	forgedHeader.Time++
+	testutil.SignEth1Header(forgedHeader, types.ExtraData{Slot: 1, ProposerIndex: 1}, testutil.ValidatorKeys[2])
+	require.NoError(t, svc.processPandoraHeader(&types.PandoraHeaderInfo{Slot: 1, Header: forgedHeader}))
+	equivocations, err := svc.equivocationDB.Equivocations(0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(equivocations))
+
+	doubleHeader := testutil.NewEth1Header(1)
+	doubleHeader.Time += 2
+	testutil.SignEth1Header(doubleHeader, types.ExtraData{Slot: 1, ProposerIndex: 1}, testutil.ValidatorKeys[1])
+	require.NoError(t, svc.processPandoraHeader(headerInfos[0]))
+	require.NoError(t, svc.processPandoraHeader(&types.PandoraHeaderInfo{Slot: 1, Header: doubleHeader}))
+
+	equivocations, err = svc.equivocationDB.Equivocations(0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(equivocations))
+	assert.Equal(t, types.PandoraEquivocation, equivocations[0].Kind)
+	assert.Equal(t, uint64(1), equivocations[0].ProposerIndex)
+	assert.Equal(t, testutil.NewMinimalConsensusInfo(0).ValidatorList[1], equivocations[0].ProposerPublicKey)
+	assert.Equal(t, headerInfos[0].Header.Hash(), equivocations[0].PandoraHeaders[0].Hash())
+	assert.Equal(t, doubleHeader.Hash(), equivocations[0].PandoraHeaders[1].Hash())
+}
+
+func TestService_VanguardEquivocation(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	_, shardInfos := getHeaderInfosAndShardInfos(1, 2)
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[0]))
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[0]))
+
+	// block of the same slot which is not signed by the proposer is not an evidence, e.g. an honest reorg
+	forgedHeader := testutil.NewEth1Header(1)
+	forgedHeader.Time++
+	testutil.SignEth1Header(forgedHeader, types.ExtraData{Slot: 1, ProposerIndex: 1}, testutil.ValidatorKeys[2])
+	forgedShardInfo := testutil.NewVanguardShardInfo(1, forgedHeader)
+	forgedShardInfo.BlockHash = make([]byte, 32)
+	require.NoError(t, svc.processVanguardShardInfo(forgedShardInfo))
+	equivocations, err := svc.equivocationDB.Equivocations(0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(equivocations))
+
+	doubleShardInfo := testutil.NewVanguardShardInfo(1, testutil.NewEth1Header(1))
+	doubleShardInfo.BlockHash = make([]byte, 32)
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[0]))
+	require.NoError(t, svc.processVanguardShardInfo(doubleShardInfo))
+
+	equivocations, err = svc.equivocationDB.Equivocations(0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(equivocations))
+	assert.Equal(t, types.VanguardEquivocation, equivocations[0].Kind)
+	assert.Equal(t, uint64(1), equivocations[0].Slot)
+	assert.Equal(t, uint64(1), equivocations[0].ProposerIndex)
+	assert.Equal(t, testutil.NewMinimalConsensusInfo(0).ValidatorList[1], equivocations[0].ProposerPublicKey)
+	assert.DeepEqual(t, shardInfos[0].BlockHash, equivocations[0].VanguardShardInfos[0].BlockHash)
+	assert.DeepEqual(t, doubleShardInfo.BlockHash, equivocations[0].VanguardShardInfos[1].BlockHash)
+}
+
+func TestService_EquivocationOfVerifiedSlot(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 3)
+	for i := range headerInfos {
+		require.NoError(t, svc.Step(headerInfos[i]))
+		require.NoError(t, svc.Step(shardInfos[i]))
+	}
+
+	// conflicting vanguard block of a verified slot is stored before the slot is reverted
+	doubleShardInfo := testutil.NewVanguardShardInfo(2, headerInfos[1].Header)
+	doubleShardInfo.BlockHash = make([]byte, 32)
+	require.NoError(t, svc.Step(doubleShardInfo))
+	equivocations, err := svc.equivocationDB.Equivocations(0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(equivocations))
+	assert.Equal(t, types.VanguardEquivocation, equivocations[0].Kind)
+	assert.DeepEqual(t, shardInfos[1], equivocations[0].VanguardShardInfos[0])
+	assert.DeepEqual(t, doubleShardInfo.BlockHash, equivocations[0].VanguardShardInfos[1].BlockHash)
+	// reverted header of slot 2 is pending again and the double vanguard block holds the same header
+	assert.Equal(t, uint64(2), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+
+	// conflicting signed pandora header of a verified slot
+	doubleHeader := testutil.NewEth1HeaderWithParent(1, headerInfos[0].Header.ParentHash)
+	doubleHeader.Time += 2
+	testutil.SignEth1Header(doubleHeader, types.ExtraData{Slot: 1, ProposerIndex: 1}, testutil.ValidatorKeys[1])
+	require.NoError(t, svc.Step(&types.PandoraHeaderInfo{Slot: 1, Header: doubleHeader}))
+	equivocations, err = svc.equivocationDB.Equivocations(0)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(equivocations))
+	// evidences are ordered by slot
+	assert.Equal(t, types.PandoraEquivocation, equivocations[0].Kind)
+	assert.Equal(t, headerInfos[0].Header.Hash(), equivocations[0].PandoraHeaders[0].Hash())
+	assert.Equal(t, doubleHeader.Hash(), equivocations[0].PandoraHeaders[1].Hash())
+}
@@ -0,0 +1,107 @@
+package consensus
+
+import (
+	"fmt"
+	"time"
+
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// time interval for checking the deadline of pending slots
+var pendingSlotCheckPeriod = time.Second
+
+// slotDeadline returns the time after which the given slot is declared as timed out. It returns false when
+// consensus info of the slot's epoch is not available yet.
+func (s *Service) slotDeadline(slot uint64) (time.Time, bool) {
+	epoch := slot / types.SlotsPerEpoch
+	consensusInfo, err := s.consensusInfoDB.ConsensusInfo(s.ctx, epoch)
+	if err != nil || consensusInfo == nil {
+		return time.Time{}, false
+	}
+	deadline := consensusInfo.SlotStartTime(slot) + s.pendingSlotTimeout*uint64(consensusInfo.SlotTimeDuration)
+	return time.Unix(int64(deadline), 0), true
+}
+
+// expirePendingSlots removes the pending slots whose deadline has passed from the caches, stores them into
+// timed out slot info db and notifies the subscribers.
+func (s *Service) expirePendingSlots(now time.Time) error {
+	// expiry is disabled
+	if s.pendingSlotTimeout == 0 {
+		return nil
+	}
+
+	headers := make(map[uint64]map[uint64]*eth1Types.Header)
+	for _, shardSlot := range s.pandoraPendingHeaderCache.Keys() {
+		header, _ := s.pandoraPendingHeaderCache.Get(s.ctx, shardSlot.Slot, shardSlot.ShardIndex)
+		if header == nil {
+			continue
+		}
+		if headers[shardSlot.ShardIndex] == nil {
+			headers[shardSlot.ShardIndex] = make(map[uint64]*eth1Types.Header)
+		}
+		headers[shardSlot.ShardIndex][shardSlot.Slot] = header
+	}
+	vanShardInfos := make(map[uint64]map[uint64]*types.VanguardShardInfo)
+	for _, shardSlot := range s.vanguardPendingShardingCache.Keys() {
+		vanShardInfo, _ := s.vanguardPendingShardingCache.Get(s.ctx, shardSlot.Slot, shardSlot.ShardIndex)
+		if vanShardInfo == nil {
+			continue
+		}
+		if vanShardInfos[shardSlot.ShardIndex] == nil {
+			vanShardInfos[shardSlot.ShardIndex] = make(map[uint64]*types.VanguardShardInfo)
+		}
+		vanShardInfos[shardSlot.ShardIndex][shardSlot.Slot] = vanShardInfo
+	}
+
+	shardIndexes := make(map[uint64]bool)
+	for shardIndex := range headers {
+		shardIndexes[shardIndex] = true
+	}
+	for shardIndex := range vanShardInfos {
+		shardIndexes[shardIndex] = true
+	}
+	for shardIndex := range shardIndexes {
+		if err := s.expirePendingShardSlots(now, shardIndex, headers[shardIndex], vanShardInfos[shardIndex]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// expirePendingShardSlots expires the pending slots of a single shard
+func (s *Service) expirePendingShardSlots(
+	now time.Time,
+	shardIndex uint64,
+	headers map[uint64]*eth1Types.Header,
+	vanShardInfos map[uint64]*types.VanguardShardInfo,
+) error {
+	slotInfos := pendingSlotInfos(headers, vanShardInfos)
+	for _, slot := range sortedSlots(slotInfos) {
+		deadline, ok := s.slotDeadline(slot)
+		if !ok || now.Before(deadline) {
+			continue
+		}
+		s.pandoraPendingHeaderCache.Delete(s.ctx, slot, shardIndex)
+		s.vanguardPendingShardingCache.Delete(s.ctx, slot, shardIndex)
+		if s.isDecidedSlot(slot, shardIndex) {
+			continue
+		}
+
+		slotInfo := slotInfos[slot]
+		if err := s.timedOutSlotInfoDB.SaveTimedOutSlotInfo(slot, shardIndex, slotInfo); err != nil {
+			log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithField(
+				"slotInfo", fmt.Sprintf("%+v", slotInfo)).WithError(err).Error("Failed to store timed out slot info")
+			return err
+		}
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithField("deadline", deadline).
+			Info("Pending sharding info timed out")
+		s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+			PandoraHeaderHash: slotInfo.PandoraHeaderHash,
+			VanguardBlockHash: slotInfo.VanguardBlockHash,
+			ShardIndex:        shardIndex,
+			Status:            types.TimedOut,
+		})
+	}
+	return nil
+}
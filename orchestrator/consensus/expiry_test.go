@@ -0,0 +1,45 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestService_ExpirePendingSlots(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	svc.pendingSlotTimeout = 2
+	headerInfos, _ := getHeaderInfosAndShardInfos(1, 6)
+
+	consensusInfo, err := svc.consensusInfoDB.ConsensusInfo(ctx, 0)
+	require.NoError(t, err)
+
+	slotInfoCh := make(chan *types.SlotInfoWithStatus, 10)
+	sub := svc.SubscribeVerifiedSlotInfoEvent(slotInfoCh)
+	defer sub.Unsubscribe()
+
+	require.NoError(t, svc.processPandoraHeader(headerInfos[0]))
+	deadline := time.Unix(int64(consensusInfo.SlotStartTime(1)+2*uint64(consensusInfo.SlotTimeDuration)), 0)
+
+	// deadline is not passed yet so slot stays pending
+	require.NoError(t, svc.expirePendingSlots(deadline.Add(-time.Second)))
+	slotInfo, err := svc.timedOutSlotInfoDB.TimedOutSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+
+	require.NoError(t, svc.expirePendingSlots(deadline))
+	slotInfo, err = svc.timedOutSlotInfoDB.TimedOutSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, headerInfos[0].Header.Hash(), slotInfo.PandoraHeaderHash)
+
+	slotInfoWithStatus := <-slotInfoCh
+	assert.Equal(t, types.TimedOut, slotInfoWithStatus.Status)
+
+	_, err = svc.pandoraPendingHeaderCache.Get(ctx, 1, 0)
+	require.ErrorContains(t, "Invalid slot", err)
+}
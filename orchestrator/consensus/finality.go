@@ -0,0 +1,139 @@
+package consensus
+
+import (
+	"errors"
+
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// onNewFinalizedCheckpoint finalizes the verified slots of every shard up to the slot of the vanguard finalized
+// checkpoint
+func (s *Service) onNewFinalizedCheckpoint(checkpoint *types.FinalizedCheckpoint) error {
+	if checkpoint.Slot <= s.vanguardFinalizedSlot {
+		return nil
+	}
+	s.vanguardFinalizedSlot, s.vanguardFinalizedRoot = checkpoint.Slot, checkpoint.BlockRoot
+
+	for shardIndex := uint64(0); shardIndex < s.shardCount; shardIndex++ {
+		if err := s.finalizeVerifiedSlots(shardIndex); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finalizeVerifiedSlots moves the latest finalized slot of the shard up to the vanguard finalized slot, or up to
+// the latest verified slot when verification is behind. Only verified slots which are on the chain of the finalized
+// checkpoint are finalized. Verified slots which conflict with the checkpoint are reverted, so the latest finalized
+// slot of the shard never moves past them. Subscribers are notified with Finalized status of every newly finalized slot.
+func (s *Service) finalizeVerifiedSlots(shardIndex uint64) error {
+	latestFinalizedSlot := s.verifiedSlotInfoDB.LatestFinalizedSlot(shardIndex)
+	finalizedSlot, forkSlot, err := s.finalizableSlot(shardIndex, latestFinalizedSlot)
+	if err != nil {
+		return err
+	}
+	if forkSlot > 0 {
+		log.WithField("checkpointSlot", s.vanguardFinalizedSlot).WithField("shardIndex", shardIndex).
+			WithField("blockRoot", s.vanguardFinalizedRoot).WithField("forkSlot", forkSlot).
+			Error("Verified slot infos conflict with vanguard finalized checkpoint, reverting them")
+		if err := s.revertVerifiedSlots(forkSlot, shardIndex, types.FinalityConflict); err != nil {
+			var prunedErr *db.PrunedRevertError
+			if errors.As(err, &prunedErr) {
+				// finality is held, the verified chain can not go back past its pruned slots
+				return nil
+			}
+			return err
+		}
+		// remaining verified slots up to the checkpoint block are on the finalized chain
+		if finalizedSlot, _, err = s.finalizableSlot(shardIndex, latestFinalizedSlot); err != nil {
+			return err
+		}
+	}
+	if finalizedSlot <= latestFinalizedSlot {
+		return nil
+	}
+	if err := s.verifiedSlotInfoDB.SaveLatestFinalizedSlot(finalizedSlot, shardIndex); err != nil {
+		log.WithField("finalizedSlot", finalizedSlot).WithField("shardIndex", shardIndex).WithError(err).
+			Error("Failed to store latest finalized slot")
+		return err
+	}
+
+	for slot := latestFinalizedSlot + 1; slot <= finalizedSlot; slot++ {
+		slotInfo, err := s.verifiedSlotInfoDB.VerifiedSlotInfo(slot, shardIndex)
+		if err != nil {
+			return err
+		}
+		// skipped, invalid and timed out slots have no verified slot info
+		if slotInfo == nil {
+			continue
+		}
+		s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+			PandoraHeaderHash: slotInfo.PandoraHeaderHash,
+			VanguardBlockHash: slotInfo.VanguardBlockHash,
+			ShardIndex:        shardIndex,
+			Status:            types.Finalized,
+		})
+	}
+	log.WithField("finalizedSlot", finalizedSlot).WithField("shardIndex", shardIndex).
+		WithField("previousFinalizedSlot", latestFinalizedSlot).Info("Finalized verified slots")
+	return nil
+}
+
+// finalizableSlot searches the block root of the vanguard finalized checkpoint among the verified slots of the shard
+// between the latest finalized slot and the checkpoint slot. The checkpoint block may be proposed before the
+// checkpoint slot when the following slots are empty.
+//
+// When the block is verified, the verified slots up to the checkpoint slot can be finalized unless a verified slot
+// follows the block before the checkpoint slot. Such slot is not on the finalized chain and is returned as the fork
+// slot. When the block is not verified although verification has passed the checkpoint slot, the verified slots are
+// not on the finalized chain either. Otherwise the block is still pending and nothing can be finalized yet.
+func (s *Service) finalizableSlot(shardIndex uint64, latestFinalizedSlot uint64) (uint64, uint64, error) {
+	checkpointSlot := s.vanguardFinalizedSlot
+	if checkpointSlot <= latestFinalizedSlot {
+		return latestFinalizedSlot, 0, nil
+	}
+	latestVerifiedSlot := s.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(shardIndex)
+	finalizedSlot := checkpointSlot
+	if latestVerifiedSlot < finalizedSlot {
+		finalizedSlot = latestVerifiedSlot
+	}
+
+	// lowest verified slot which follows the checkpoint block
+	followingSlot := uint64(0)
+	for slot := finalizedSlot; slot > latestFinalizedSlot; slot-- {
+		slotInfo, err := s.verifiedSlotInfoDB.VerifiedSlotInfo(slot, shardIndex)
+		if err != nil {
+			return 0, 0, err
+		}
+		if slotInfo == nil {
+			continue
+		}
+		if slotInfo.VanguardBlockHash != s.vanguardFinalizedRoot {
+			followingSlot = slot
+			continue
+		}
+		if followingSlot > 0 {
+			return latestFinalizedSlot, followingSlot, nil
+		}
+		return finalizedSlot, 0, nil
+	}
+	if latestVerifiedSlot >= checkpointSlot {
+		return latestFinalizedSlot, latestFinalizedSlot + 1, nil
+	}
+	return latestFinalizedSlot, 0, nil
+}
+
+// isFinalized tells whether the verified slot of the shard is finalized and can not be reverted anymore
+func (s *Service) isFinalized(slot uint64, shardIndex uint64) bool {
+	latestFinalizedSlot := s.verifiedSlotInfoDB.LatestFinalizedSlot(shardIndex)
+	return latestFinalizedSlot > 0 && slot <= latestFinalizedSlot
+}
+
+// verifiedStatus returns the status of a verified slot
+func (s *Service) verifiedStatus(slot uint64, shardIndex uint64) types.Status {
+	if s.isFinalized(slot, shardIndex) {
+		return types.Finalized
+	}
+	return types.Verified
+}
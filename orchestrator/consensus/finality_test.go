@@ -0,0 +1,130 @@
+package consensus
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestService_FinalizedCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	svc, mockedFeed := setup(ctx, t)
+	defer svc.Stop()
+	headerInfos, shardInfos := getFinalityTestInfos(1, 5)
+	for i := range headerInfos[:3] {
+		require.NoError(t, svc.processPandoraHeader(headerInfos[i]))
+		require.NoError(t, svc.processVanguardShardInfo(shardInfos[i]))
+	}
+
+	slotInfoCh := make(chan *types.SlotInfoWithStatus, 10)
+	sub := svc.SubscribeVerifiedSlotInfoEvent(slotInfoCh)
+	defer sub.Unsubscribe()
+	svc.Start()
+	time.Sleep(100 * time.Millisecond)
+
+	// vanguard has finalized the block of slot 4 but only slots up to 3 are verified, so nothing is finalized
+	mockedFeed.finalizedCheckpointFeed.Send(&types.FinalizedCheckpoint{
+		Slot:      4,
+		BlockRoot: common.BytesToHash(shardInfos[3].BlockHash),
+	})
+	select {
+	case slotInfoWithStatus := <-slotInfoCh:
+		t.Fatalf("unexpected %s status before the checkpoint block is verified", slotInfoWithStatus.Status)
+	case <-time.After(100 * time.Millisecond):
+	}
+	assert.Equal(t, uint64(0), svc.verifiedSlotInfoDB.LatestFinalizedSlot(0))
+
+	// slots up to 4 are finalized as soon as the checkpoint block is verified
+	mockedFeed.headerInfoFeed.Send(headerInfos[3])
+	mockedFeed.shardInfoFeed.Send(shardInfos[3])
+	assert.Equal(t, types.Verified, (<-slotInfoCh).Status)
+	for i := range headerInfos {
+		slotInfoWithStatus := <-slotInfoCh
+		assert.Equal(t, types.Finalized, slotInfoWithStatus.Status)
+		assert.Equal(t, headerInfos[i].Header.Hash(), slotInfoWithStatus.PandoraHeaderHash)
+	}
+	assert.Equal(t, uint64(4), svc.verifiedSlotInfoDB.LatestFinalizedSlot(0))
+
+	// finalized slot can not be reorged
+	reorgedHeader := testutil.NewEth1HeaderWithParent(2, headerInfos[0].Header.Hash())
+	reorgedHeader.Time++
+	testutil.SignEth1Header(reorgedHeader, types.ExtraData{Slot: 2, ProposerIndex: 2}, testutil.ValidatorKeys[2])
+	mockedFeed.headerInfoFeed.Send(&types.PandoraHeaderInfo{Slot: 2, Header: reorgedHeader})
+	slotInfoWithStatus := <-slotInfoCh
+	assert.Equal(t, types.Invalid, slotInfoWithStatus.Status)
+	assert.Equal(t, reorgedHeader.Hash(), slotInfoWithStatus.PandoraHeaderHash)
+	assert.Equal(t, uint64(4), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+
+	// replayed header of a finalized slot gets finalized status
+	mockedFeed.headerInfoFeed.Send(headerInfos[1])
+	assert.Equal(t, types.Finalized, (<-slotInfoCh).Status)
+}
+
+// getFinalityTestInfos returns linked headers with shard infos whose vanguard blocks have distinct hashes
+func getFinalityTestInfos(fromSlot uint64, num uint64) ([]*types.PandoraHeaderInfo, []*types.VanguardShardInfo) {
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(fromSlot, num)
+	for _, shardInfo := range shardInfos {
+		shardInfo.BlockHash = common.BigToHash(new(big.Int).SetUint64(shardInfo.Slot)).Bytes()
+	}
+	return headerInfos, shardInfos
+}
+
+func TestService_FinalizedCheckpoint_EmptySlots(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, shardInfos := getFinalityTestInfos(1, 5)
+	for i := range headerInfos {
+		require.NoError(t, svc.Step(headerInfos[i]))
+		require.NoError(t, svc.Step(shardInfos[i]))
+	}
+	assert.Equal(t, uint64(4), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+
+	// checkpoint block of slot 4 is proposed in slot 3, so verified slot 4 is not on the finalized chain
+	require.NoError(t, svc.Step(&types.FinalizedCheckpoint{
+		Slot:      4,
+		BlockRoot: common.BytesToHash(shardInfos[2].BlockHash),
+	}))
+	slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(4, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+	assert.Equal(t, uint64(3), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, uint64(3), svc.verifiedSlotInfoDB.LatestFinalizedSlot(0))
+}
+
+func TestService_FinalizedCheckpoint_Conflict(t *testing.T) {
+	ctx := context.Background()
+	unknownRoot := common.HexToHash("0xff")
+	verifiedService := func() *Service {
+		svc, _ := setup(ctx, t)
+		headerInfos, shardInfos := getFinalityTestInfos(1, 4)
+		for i := range headerInfos {
+			require.NoError(t, svc.Step(headerInfos[i]))
+			require.NoError(t, svc.Step(shardInfos[i]))
+		}
+		return svc
+	}
+
+	// finalized block of slot 5 is not verified yet, so nothing is finalized
+	svc := verifiedService()
+	require.NoError(t, svc.Step(&types.FinalizedCheckpoint{Slot: 5, BlockRoot: unknownRoot}))
+	assert.Equal(t, uint64(0), svc.verifiedSlotInfoDB.LatestFinalizedSlot(0))
+	assert.Equal(t, uint64(3), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+
+	// verified chain passes the checkpoint slot without the finalized block, so it is reverted
+	svc = verifiedService()
+	require.NoError(t, svc.Step(&types.FinalizedCheckpoint{Slot: 2, BlockRoot: unknownRoot}))
+	assert.Equal(t, uint64(0), svc.verifiedSlotInfoDB.LatestFinalizedSlot(0))
+	assert.Equal(t, uint64(0), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	for slot := uint64(1); slot <= 3; slot++ {
+		slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(slot, 0)
+		require.NoError(t, err)
+		assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+	}
+}
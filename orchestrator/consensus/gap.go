@@ -0,0 +1,66 @@
+package consensus
+
+import (
+	"sort"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// verificationGap is a pending slot of a shard whose pandora header does not extend the latest verified header
+type verificationGap struct {
+	waitingSlot uint64
+	parentHash  common.Hash
+}
+
+// recordVerificationGap remembers the lowest pending slot of the shard which waits for its missing parent
+func (s *Service) recordVerificationGap(slot uint64, shardIndex uint64, parentHash common.Hash) {
+	s.gapLock.Lock()
+	defer s.gapLock.Unlock()
+
+	if gap, exists := s.verificationGaps[shardIndex]; exists && gap.waitingSlot < slot && s.isPendingSlot(gap.waitingSlot, shardIndex) {
+		return
+	}
+	s.verificationGaps[shardIndex] = &verificationGap{waitingSlot: slot, parentHash: parentHash}
+}
+
+// closeVerificationGap forgets the gap of the shard once the waiting slot or a later slot is verified
+func (s *Service) closeVerificationGap(verifiedSlot uint64, shardIndex uint64) {
+	s.gapLock.Lock()
+	defer s.gapLock.Unlock()
+
+	if gap, exists := s.verificationGaps[shardIndex]; exists && gap.waitingSlot <= verifiedSlot {
+		delete(s.verificationGaps, shardIndex)
+	}
+}
+
+// VerificationGaps returns the gaps between the latest verified slot and the lowest pending slot which waits for its
+// missing parent, for every shard that has such a slot. Gaps whose waiting slot is no longer pending are dropped.
+func (s *Service) VerificationGaps() []*types.VerificationGap {
+	s.gapLock.Lock()
+	defer s.gapLock.Unlock()
+
+	gaps := make([]*types.VerificationGap, 0, len(s.verificationGaps))
+	for shardIndex, gap := range s.verificationGaps {
+		latestVerifiedSlot := s.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(shardIndex)
+		if gap.waitingSlot <= latestVerifiedSlot || !s.isPendingSlot(gap.waitingSlot, shardIndex) {
+			delete(s.verificationGaps, shardIndex)
+			continue
+		}
+		gaps = append(gaps, &types.VerificationGap{
+			ShardIndex:         shardIndex,
+			LatestVerifiedSlot: latestVerifiedSlot,
+			LatestHeaderHash:   s.verifiedSlotInfoDB.InMemoryLatestVerifiedHeaderHash(shardIndex),
+			WaitingSlot:        gap.waitingSlot,
+			ParentHash:         gap.parentHash,
+		})
+	}
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i].ShardIndex < gaps[j].ShardIndex })
+	return gaps
+}
+
+// isPendingSlot returns true when the pandora header of the slot is still in the pending cache
+func (s *Service) isPendingSlot(slot uint64, shardIndex uint64) bool {
+	header, _ := s.pandoraPendingHeaderCache.Get(s.ctx, slot, shardIndex)
+	return header != nil
+}
@@ -1,18 +1,28 @@
 package consensus
 
 import (
+	"errors"
 	"fmt"
+	"sort"
 
 	"github.com/ethereum/go-ethereum/common"
 	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
 )
 
 // processPandoraHeader
 func (s *Service) processPandoraHeader(headerInfo *types.PandoraHeaderInfo) error {
-	slot := headerInfo.Slot
-	s.pandoraPendingHeaderCache.Put(s.ctx, slot, headerInfo.Header)
-	vanShardInfo, _ := s.vanguardPendingShardingCache.Get(s.ctx, slot)
+	slot, shardIndex := headerInfo.Slot, headerInfo.ShardIndex
+	if err := s.detectPandoraEquivocation(headerInfo); err != nil {
+		return err
+	}
+	if err := s.pandoraPendingHeaderCache.Put(s.ctx, slot, shardIndex, headerInfo.Header); err != nil {
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithError(err).
+			Error("Failed to store pending pandora header")
+		return err
+	}
+	vanShardInfo, _ := s.vanguardPendingShardingCache.Get(s.ctx, slot, shardIndex)
 	if vanShardInfo != nil {
 		return s.verifyShardingInfo(slot, vanShardInfo, headerInfo.Header)
 	}
@@ -21,61 +31,306 @@ func (s *Service) processPandoraHeader(headerInfo *types.PandoraHeaderInfo) erro
 
 // processVanguardShardInfo
 func (s *Service) processVanguardShardInfo(vanShardInfo *types.VanguardShardInfo) error {
-	slot := vanShardInfo.Slot
-	s.vanguardPendingShardingCache.Put(s.ctx, slot, vanShardInfo)
-	headerInfo, _ := s.pandoraPendingHeaderCache.Get(s.ctx, slot)
+	slot, shardIndex := vanShardInfo.Slot, vanShardInfo.ShardIndex
+	if err := s.detectVanguardEquivocation(vanShardInfo); err != nil {
+		return err
+	}
+	if err := s.vanguardPendingShardingCache.Put(s.ctx, slot, shardIndex, vanShardInfo); err != nil {
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithError(err).
+			Error("Failed to store pending vanguard shard info")
+		return err
+	}
+	headerInfo, _ := s.pandoraPendingHeaderCache.Get(s.ctx, slot, shardIndex)
 	if headerInfo != nil {
 		return s.verifyShardingInfo(slot, vanShardInfo, headerInfo)
 	}
 	return nil
 }
 
-// verifyShardingInfo
+// verifyShardingInfo verifies the pandora header against the pandora shard of the vanguard block. The pending
+// children which were waiting for the verified header are verified in turn. Every shard of a slot is verified
+// independently.
 func (s *Service) verifyShardingInfo(slot uint64, vanShardInfo *types.VanguardShardInfo, header *eth1Types.Header) error {
+	for header != nil {
+		verified, err := s.verifySlot(slot, vanShardInfo, header)
+		if err != nil || !verified {
+			return err
+		}
+		slot, vanShardInfo, header = s.pendingChild(slot, vanShardInfo.ShardIndex, header.Hash())
+	}
+	return nil
+}
+
+// verifySlot verifies a single slot and stores its status. It returns true when the slot is verified, false when
+// it is invalid or stays pending.
+func (s *Service) verifySlot(
+	slot uint64,
+	vanShardInfo *types.VanguardShardInfo,
+	header *eth1Types.Header,
+) (bool, error) {
+	shardIndex := vanShardInfo.ShardIndex
 	slotInfo := &types.SlotInfo{
 		PandoraHeaderHash: header.Hash(),
 		VanguardBlockHash: common.BytesToHash(vanShardInfo.BlockHash[:]),
 	}
-	status := CompareShardingInfo(header, vanShardInfo.ShardInfo)
+	verdicts, reason := s.verifiers.Verify(s.verificationInput(slot, vanShardInfo, header))
+	if reason == types.ConsensusInfoMissing {
+		// slot stays pending until the consensus info of its epoch arrives, see onNewConsensusInfo
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).
+			Debug("Consensus info is not available yet, sharding info stays pending")
+		return false, nil
+	}
+	if reason == types.NoMismatch && !s.isLinkedToVerifiedChain(slot, shardIndex, header) {
+		// slot stays pending until its parent is verified
+		return false, nil
+	}
 	slotInfoWithStatus := &types.SlotInfoWithStatus{
 		PandoraHeaderHash: header.Hash(),
 		VanguardBlockHash: common.BytesToHash(vanShardInfo.BlockHash[:]),
+		ShardIndex:        shardIndex,
 	}
-	if !status{
+	if reason != types.NoMismatch {
+		if err := s.verdictDB.SaveVerdicts(slot, shardIndex, verdicts); err != nil {
+			log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithError(err).
+				Error("Failed to store verdicts of verification rules")
+			return false, err
+		}
+		invalidSlotInfo := &types.InvalidSlotInfo{SlotInfo: *slotInfo, Reason: reason}
 		// store invalid slot info into invalid slot info bucket
-		if err := s.invalidSlotInfoDB.SaveInvalidSlotInfo(slot, slotInfo); err != nil {
-			log.WithField("slot", slot).WithField(
-				"slotInfo", fmt.Sprintf("%+v", slotInfo)).WithError(err).Error(
+		if err := s.invalidSlotInfoDB.SaveInvalidSlotInfo(slot, shardIndex, invalidSlotInfo); err != nil {
+			log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithField(
+				"slotInfo", fmt.Sprintf("%+v", invalidSlotInfo)).WithError(err).Error(
 				"Failed to store invalid slot info")
-			return err
+			return false, err
 		}
 		slotInfoWithStatus.Status = types.Invalid
-		log.WithField("slot", slot).Info("Invalid sharding info")
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithField("reason", reason).
+			Info("Invalid sharding info")
 		// sending verified slot info to rpc service
 		s.verifiedSlotInfoFeed.Send(slotInfoWithStatus)
-		return nil
+		return false, nil
 	}
 
-	// store verified slot info into verified slot info bucket
-	if err := s.verifiedSlotInfoDB.SaveVerifiedSlotInfo(slot, slotInfo); err != nil {
-		log.WithField("slot", slot).WithField(
-			"slotInfo", fmt.Sprintf("%+v", slotInfo)).WithError(err).Error("Failed to store verified slot info")
-		return err
-	}
-	// storing latest verified slot into db
-	if err := s.verifiedSlotInfoDB.SaveLatestVerifiedSlot(s.ctx); err != nil {
-		log.WithError(err).Error("Failed to store latest verified slot")
-	}
-	// storing latest verified pandora header hash into db
-	if err := s.verifiedSlotInfoDB.SaveLatestVerifiedHeaderHash(); err != nil {
-		log.WithError(err).Error("Failed to store latest verified slot")
+	// slot info, its verdicts, its payload and the latest pointers are stored together
+	if err := s.verifiedSlotInfoDB.CommitVerifiedSlots(shardIndex, []*types.VerifiedSlot{{
+		Slot:      slot,
+		SlotInfo:  slotInfo,
+		Header:    header,
+		ShardInfo: vanShardInfo,
+		Verdicts:  verdicts,
+	}}); err != nil {
+		var conflictErr *db.VerifiedSlotConflictError
+		if errors.As(err, &conflictErr) {
+			// latest verified slot has moved since the link was checked, slot stays pending like an unlinked slot
+			log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithError(err).
+				Warn("Verified slot does not extend the verified chain anymore")
+			return false, nil
+		}
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithField(
+			"slotInfo", fmt.Sprintf("%+v", slotInfo)).WithError(err).Error("Failed to commit verified slot")
+		return false, err
 	}
 	slotInfoWithStatus.Status = types.Verified
+	s.closeVerificationGap(slot, shardIndex)
 	//removing previous cached slots which dont verified yet. By convention, they are skipped
-	s.pandoraPendingHeaderCache.Remove(s.ctx, slot)
-	s.vanguardPendingShardingCache.Remove(s.ctx, slot)
-	log.WithField("slot", slot).Info("Successfully verified sharding info")
+	removedHeaders := s.pandoraPendingHeaderCache.Remove(s.ctx, slot, shardIndex)
+	removedShardInfos := s.vanguardPendingShardingCache.Remove(s.ctx, slot, shardIndex)
+	if err := s.markSkippedSlots(slot, shardIndex, removedHeaders, removedShardInfos); err != nil {
+		return false, err
+	}
+	log.WithField("slot", slot).WithField("shardIndex", shardIndex).Info("Successfully verified sharding info")
 	// sending verified slot info to rpc service
 	s.verifiedSlotInfoFeed.Send(slotInfoWithStatus)
+	// slot may already be finalized by vanguard when it is verified late
+	if err := s.finalizeVerifiedSlots(shardIndex); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// isLinkedToVerifiedChain checks that the parent of the pandora header is the latest verified pandora header, so that
+// verified slots always form one linear chain. The first verified header starts the chain.
+func (s *Service) isLinkedToVerifiedChain(slot uint64, shardIndex uint64, header *eth1Types.Header) bool {
+	latestVerifiedSlot := s.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(shardIndex)
+	latestHeaderHash := s.verifiedSlotInfoDB.InMemoryLatestVerifiedHeaderHash(shardIndex)
+	if latestHeaderHash == (common.Hash{}) {
+		return true
+	}
+	if slot <= latestVerifiedSlot {
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).
+			WithField("latestVerifiedSlot", latestVerifiedSlot).Warn("Sharding info is behind the latest verified slot")
+		return false
+	}
+	if header.ParentHash != latestHeaderHash {
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).
+			WithField("latestVerifiedSlot", latestVerifiedSlot).WithField("parentHash", header.ParentHash).WithField("latestHeaderHash", latestHeaderHash).
+			Warn("Pandora header is not linked to the latest verified header, waiting for the missing parent")
+		// gap is exposed over the rpc api until the missing parent is verified
+		s.recordVerificationGap(slot, shardIndex, header.ParentHash)
+		return false
+	}
+	return true
+}
+
+// verifyPendingChild verifies the pending slot whose pandora header is the child of the newly verified header and
+// the pending descendants of that slot. Such slots were waiting for their parent to be verified.
+func (s *Service) verifyPendingChild(verifiedSlot uint64, shardIndex uint64, verifiedHeaderHash common.Hash) error {
+	slot, vanShardInfo, header := s.pendingChild(verifiedSlot, shardIndex, verifiedHeaderHash)
+	if header == nil {
+		return nil
+	}
+	return s.verifyShardingInfo(slot, vanShardInfo, header)
+}
+
+// pendingChild returns the pending slot whose pandora header is the child of the verified header and whose vanguard
+// shard info has arrived as well. The header is nil when there is no such slot.
+func (s *Service) pendingChild(
+	verifiedSlot uint64,
+	shardIndex uint64,
+	verifiedHeaderHash common.Hash,
+) (uint64, *types.VanguardShardInfo, *eth1Types.Header) {
+	slots := make([]uint64, 0)
+	for _, shardSlot := range s.pandoraPendingHeaderCache.Keys() {
+		if shardSlot.ShardIndex == shardIndex && shardSlot.Slot > verifiedSlot {
+			slots = append(slots, shardSlot.Slot)
+		}
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	for _, slot := range slots {
+		header, _ := s.pandoraPendingHeaderCache.Get(s.ctx, slot, shardIndex)
+		if header == nil || header.ParentHash != verifiedHeaderHash {
+			continue
+		}
+		vanShardInfo, _ := s.vanguardPendingShardingCache.Get(s.ctx, slot, shardIndex)
+		if vanShardInfo == nil {
+			continue
+		}
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithField("parentSlot", verifiedSlot).
+			Debug("Verifying pending child slot")
+		return slot, vanShardInfo, header
+	}
+	return 0, nil, nil
+}
+
+// onNewConsensusInfo verifies the pending slots of the epoch whose consensus info has arrived. These slots were
+// kept pending because their consensus info was missing.
+func (s *Service) onNewConsensusInfo(consensusInfo *types.MinimalEpochConsensusInfo) error {
+	fromSlot := consensusInfo.Epoch * types.SlotsPerEpoch
+	toSlot := fromSlot + types.SlotsPerEpoch
+	shardSlots := make([]types.ShardSlot, 0)
+	for _, shardSlot := range s.pandoraPendingHeaderCache.Keys() {
+		if shardSlot.Slot >= fromSlot && shardSlot.Slot < toSlot {
+			shardSlots = append(shardSlots, shardSlot)
+		}
+	}
+	sort.Slice(shardSlots, func(i, j int) bool { return shardSlots[i].Slot < shardSlots[j].Slot })
+	for _, shardSlot := range shardSlots {
+		// an earlier slot of the epoch may have verified or skipped this one in the meantime
+		header, _ := s.pandoraPendingHeaderCache.Get(s.ctx, shardSlot.Slot, shardSlot.ShardIndex)
+		vanShardInfo, _ := s.vanguardPendingShardingCache.Get(s.ctx, shardSlot.Slot, shardSlot.ShardIndex)
+		if header == nil || vanShardInfo == nil {
+			continue
+		}
+		log.WithField("slot", shardSlot.Slot).WithField("shardIndex", shardSlot.ShardIndex).
+			WithField("epoch", consensusInfo.Epoch).Debug("Verifying pending slot with new consensus info")
+		if err := s.verifyShardingInfo(shardSlot.Slot, vanShardInfo, header); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// verificationInput collects the input of the verification rules. Consensus info stays nil when it is not
+// available for the slot's epoch.
+func (s *Service) verificationInput(
+	slot uint64,
+	vanShardInfo *types.VanguardShardInfo,
+	header *eth1Types.Header,
+) *VerificationInput {
+	consensusInfo, err := s.consensusInfoBySlot(slot)
+	if err != nil {
+		log.WithField("slot", slot).WithError(err).Warn("Consensus info is not available for verification")
+		consensusInfo = nil
+	}
+	return &VerificationInput{
+		Slot:          slot,
+		ShardIndex:    vanShardInfo.ShardIndex,
+		Header:        header,
+		ShardInfo:     vanShardInfo.ShardInfo,
+		ConsensusInfo: consensusInfo,
+	}
+}
+
+// markSkippedSlots stores the pending slots which are dropped from the caches when a later slot is verified
+// into skipped slot info db and notifies the subscribers. These slots will never be verified.
+func (s *Service) markSkippedSlots(
+	verifiedSlot uint64,
+	shardIndex uint64,
+	headers map[uint64]*eth1Types.Header,
+	vanShardInfos map[uint64]*types.VanguardShardInfo,
+) error {
+	skippedSlotInfos := pendingSlotInfos(headers, vanShardInfos)
+	delete(skippedSlotInfos, verifiedSlot)
+
+	for _, slot := range sortedSlots(skippedSlotInfos) {
+		if s.isDecidedSlot(slot, shardIndex) {
+			continue
+		}
+		slotInfo := skippedSlotInfos[slot]
+		if err := s.skippedSlotInfoDB.SaveSkippedSlotInfo(slot, shardIndex, slotInfo); err != nil {
+			log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithField(
+				"slotInfo", fmt.Sprintf("%+v", slotInfo)).WithError(err).Error("Failed to store skipped slot info")
+			return err
+		}
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithField("verifiedSlot", verifiedSlot).
+			Info("Skipped sharding info")
+		s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+			PandoraHeaderHash: slotInfo.PandoraHeaderHash,
+			VanguardBlockHash: slotInfo.VanguardBlockHash,
+			ShardIndex:        shardIndex,
+			Status:            types.Skipped,
+		})
+	}
 	return nil
 }
+
+// isDecidedSlot returns true when the slot of the shard is already verified or invalid. Such slots keep their status.
+func (s *Service) isDecidedSlot(slot uint64, shardIndex uint64) bool {
+	if slotInfo, _ := s.invalidSlotInfoDB.InvalidSlotInfo(slot, shardIndex); slotInfo != nil {
+		return true
+	}
+	if slotInfo, _ := s.verifiedSlotInfoDB.VerifiedSlotInfo(slot, shardIndex); slotInfo != nil {
+		return true
+	}
+	return false
+}
+
+// pendingSlotInfos merges pending pandora headers and vanguard shard infos into partial slot infos keyed by slot
+func pendingSlotInfos(
+	headers map[uint64]*eth1Types.Header,
+	vanShardInfos map[uint64]*types.VanguardShardInfo,
+) map[uint64]*types.SlotInfo {
+	slotInfos := make(map[uint64]*types.SlotInfo)
+	for slot, header := range headers {
+		slotInfos[slot] = &types.SlotInfo{PandoraHeaderHash: header.Hash()}
+	}
+	for slot, vanShardInfo := range vanShardInfos {
+		slotInfo, exists := slotInfos[slot]
+		if !exists {
+			slotInfo = new(types.SlotInfo)
+			slotInfos[slot] = slotInfo
+		}
+		slotInfo.VanguardBlockHash = common.BytesToHash(vanShardInfo.BlockHash[:])
+	}
+	return slotInfos
+}
+
+// sortedSlots returns the slots of slot infos in ascending order so that subscribers are notified in order
+func sortedSlots(slotInfos map[uint64]*types.SlotInfo) []uint64 {
+	slots := make([]uint64, 0, len(slotInfos))
+	for slot := range slotInfos {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+	return slots
+}
@@ -0,0 +1,173 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestService_VerifyAfterMissingParent(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 4)
+
+	require.NoError(t, svc.processPandoraHeader(headerInfos[0]))
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[0]))
+
+	// parent of slot 3 is not verified yet so slot 3 stays pending
+	require.NoError(t, svc.processPandoraHeader(headerInfos[2]))
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[2]))
+	slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+	assert.Equal(t, uint64(1), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	assert.DeepEqual(t, []*types.VerificationGap{{
+		LatestVerifiedSlot: 1,
+		LatestHeaderHash:   headerInfos[0].Header.Hash(),
+		WaitingSlot:        3,
+		ParentHash:         headerInfos[1].Header.Hash(),
+	}}, svc.VerificationGaps())
+
+	// verifying slot 2 fills the gap and slot 3 is verified as its child
+	require.NoError(t, svc.processPandoraHeader(headerInfos[1]))
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[1]))
+	slotInfo, err = svc.verifiedSlotInfoDB.VerifiedSlotInfo(3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, headerInfos[2].Header.Hash(), slotInfo.PandoraHeaderHash)
+	assert.Equal(t, uint64(3), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, 0, len(svc.VerificationGaps()))
+
+	// full payload is stored only for verified slots
+	shardInfo, err := svc.verifiedPayloadDB.VerifiedShardInfo(3, 0)
+	require.NoError(t, err)
+	assert.DeepEqual(t, shardInfos[2], shardInfo)
+	header, err := svc.verifiedPayloadDB.VerifiedHeader(4, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*eth1Types.Header)(nil), header)
+}
+
+func TestService_BrokenParentLink(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 2)
+
+	require.NoError(t, svc.processPandoraHeader(headerInfos[0]))
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[0]))
+
+	// header of slot 2 does not extend the verified header of slot 1
+	header := testutil.NewEth1HeaderWithParent(2, eth1Types.EmptyUncleHash)
+	require.NoError(t, svc.processPandoraHeader(&types.PandoraHeaderInfo{Slot: 2, Header: header}))
+	require.NoError(t, svc.processVanguardShardInfo(testutil.NewVanguardShardInfo(2, header)))
+
+	slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+	assert.Equal(t, uint64(1), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, headerInfos[0].Header.Hash(), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedHeaderHash(0))
+}
+
+func TestService_VerifyShardsIndependently(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 3)
+
+	// shard 0 verifies slot 1 and 2 while shard 1 only receives slot 2
+	for i := range headerInfos {
+		require.NoError(t, svc.processPandoraHeader(headerInfos[i]))
+		require.NoError(t, svc.processVanguardShardInfo(shardInfos[i]))
+	}
+	shard1Header := &types.PandoraHeaderInfo{Slot: 2, ShardIndex: 1, Header: headerInfos[1].Header}
+	shard1Info := testutil.NewVanguardShardInfo(2, headerInfos[1].Header)
+	shard1Info.ShardIndex = 1
+	require.NoError(t, svc.processPandoraHeader(shard1Header))
+	require.NoError(t, svc.processVanguardShardInfo(shard1Info))
+
+	assert.Equal(t, uint64(2), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, uint64(2), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(1))
+	slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(1, 1)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+	slotInfo, err = svc.verifiedSlotInfoDB.VerifiedSlotInfo(2, 1)
+	require.NoError(t, err)
+	assert.Equal(t, headerInfos[1].Header.Hash(), slotInfo.PandoraHeaderHash)
+
+	// an invalid header of shard 1 does not affect the verified slot of shard 0
+	header := testutil.NewEth1HeaderWithParent(3, headerInfos[1].Header.Hash())
+	require.NoError(t, svc.processPandoraHeader(&types.PandoraHeaderInfo{Slot: 3, ShardIndex: 1, Header: header}))
+	invalidShardInfo := testutil.NewVanguardShardInfo(3, testutil.NewEth1HeaderWithParent(3, header.Hash()))
+	invalidShardInfo.ShardIndex = 1
+	require.NoError(t, svc.processVanguardShardInfo(invalidShardInfo))
+
+	invalidSlotInfo, err := svc.invalidSlotInfoDB.InvalidSlotInfo(3, 1)
+	require.NoError(t, err)
+	assert.NotNil(t, invalidSlotInfo)
+	invalidSlotInfo, err = svc.invalidSlotInfoDB.InvalidSlotInfo(3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.InvalidSlotInfo)(nil), invalidSlotInfo)
+	assert.Equal(t, uint64(2), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+}
+
+// conflictingVerifiedSlotInfoDB rejects every verified slot commit as if another slot was verified in the meantime
+type conflictingVerifiedSlotInfoDB struct {
+	db.VerifiedSlotInfoDB
+}
+
+func (c *conflictingVerifiedSlotInfoDB) CommitVerifiedSlots(shardIndex uint64, verifiedSlots []*types.VerifiedSlot) error {
+	return &db.VerifiedSlotConflictError{
+		Slot:       verifiedSlots[0].Slot,
+		ShardIndex: shardIndex,
+		ParentHash: verifiedSlots[0].Header.ParentHash,
+	}
+}
+
+// TestService_CommitConflict checks that a slot whose commit conflicts with the verified chain stays pending
+func TestService_CommitConflict(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	svc.verifiedSlotInfoDB = &conflictingVerifiedSlotInfoDB{VerifiedSlotInfoDB: svc.verifiedSlotInfoDB}
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 2)
+
+	require.NoError(t, svc.processPandoraHeader(headerInfos[0]))
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[0]))
+	slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+	assert.Equal(t, uint64(0), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, 1, len(svc.pandoraPendingHeaderCache.Keys()))
+}
+
+func TestService_PendingUntilConsensusInfo(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(33, 35)
+
+	// consensus info of epoch 1 is not stored yet so the slots stay pending instead of being invalid
+	for i := range headerInfos {
+		require.NoError(t, svc.Step(headerInfos[i]))
+		require.NoError(t, svc.Step(shardInfos[i]))
+	}
+	for slot := uint64(33); slot <= 34; slot++ {
+		invalidSlotInfo, err := svc.invalidSlotInfoDB.InvalidSlotInfo(slot, 0)
+		require.NoError(t, err)
+		assert.Equal(t, (*types.InvalidSlotInfo)(nil), invalidSlotInfo)
+		header, _ := svc.pandoraPendingHeaderCache.Get(ctx, slot, 0)
+		assert.NotNil(t, header)
+	}
+
+	// arriving consensus info verifies the pending slots of its epoch
+	saveConsensusInfos(t, svc, 1)
+	require.NoError(t, svc.Step(testutil.NewMinimalConsensusInfo(1)))
+	for slot := uint64(33); slot <= 34; slot++ {
+		slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(slot, 0)
+		require.NoError(t, err)
+		require.NotNil(t, slotInfo)
+		assert.Equal(t, headerInfos[slot-33].Header.Hash(), slotInfo.PandoraHeaderHash)
+	}
+	assert.Equal(t, uint64(34), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+}
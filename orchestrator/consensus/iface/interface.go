@@ -8,3 +8,8 @@ import (
 type VerifiedSlotInfoFeed interface {
 	SubscribeVerifiedSlotInfoEvent(chan<- *types.SlotInfoWithStatus) event.Subscription
 }
+
+// VerificationGapReader tells which shards have pending slots that wait for missing parent slots
+type VerificationGapReader interface {
+	VerificationGaps() []*types.VerificationGap
+}
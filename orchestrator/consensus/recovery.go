@@ -0,0 +1,111 @@
+package consensus
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+var (
+	// number of times a failed event is processed again before the loop is restarted. The restarted loop processes
+	// the failed event first.
+	maxProcessRetries = 3
+	// time to wait before processing a failed event again. It is doubled after every retry.
+	processRetryBackoff = 100 * time.Millisecond
+	// time to wait before restarting the crashed loop. It is doubled after every crash.
+	restartBackoff = time.Second
+	// upper limit of the restart backoff
+	maxRestartBackoff = 30 * time.Second
+)
+
+// supervise runs the processing loop and restarts it with fresh subscriptions whenever it crashes. The error of
+// the crash is reported by Status until an event is processed successfully again.
+func (s *Service) supervise() {
+	backoff := restartBackoff
+	for {
+		startedAt := time.Now()
+		err := s.run()
+		if err == nil || s.ctx.Err() != nil {
+			return
+		}
+		s.storeRunError(err)
+		crashCount := atomic.AddUint64(&s.crashCount, 1)
+
+		// the loop has been healthy for a while, so it is not crashing repeatedly
+		if time.Since(startedAt) > maxRestartBackoff {
+			backoff = restartBackoff
+		}
+		log.WithError(err).WithField("crashCount", crashCount).WithField("backoff", backoff).
+			Error("Consensus service crashed, restarting")
+
+		select {
+		case <-time.After(backoff):
+		case <-s.ctx.Done():
+			return
+		}
+		backoff *= 2
+		if backoff > maxRestartBackoff {
+			backoff = maxRestartBackoff
+		}
+	}
+}
+
+// retry calls fn until it succeeds or the retries are exhausted. It waits with an exponential backoff between
+// the attempts so that a temporary db failure does not stop the verification.
+func (s *Service) retry(fn func() error) error {
+	backoff := processRetryBackoff
+	err := fn()
+	for attempt := 1; err != nil && attempt <= maxProcessRetries; attempt++ {
+		log.WithError(err).WithField("attempt", attempt).WithField("backoff", backoff).
+			Warn("Failed to process event, retrying")
+		select {
+		case <-time.After(backoff):
+		case <-s.ctx.Done():
+			return err
+		}
+		backoff *= 2
+		err = fn()
+	}
+	if err == nil && s.loadRunError() != nil {
+		s.storeRunError(nil)
+	}
+	return err
+}
+
+// process calls step with retries. A step which still fails is kept, so that the restarted loop processes its
+// event again instead of losing it with the subscriptions of the crashed loop.
+func (s *Service) process(step func() error) error {
+	if err := s.retry(step); err != nil {
+		s.failedStep = step
+		return err
+	}
+	return nil
+}
+
+// processFailedStep processes the event of the crashed loop again before the restarted loop receives new events
+func (s *Service) processFailedStep() error {
+	if s.failedStep == nil {
+		return nil
+	}
+	if err := s.process(s.failedStep); err != nil {
+		return err
+	}
+	s.failedStep = nil
+	return nil
+}
+
+// CrashCount returns the number of times the processing loop has crashed and been restarted
+func (s *Service) CrashCount() uint64 {
+	return atomic.LoadUint64(&s.crashCount)
+}
+
+func (s *Service) storeRunError(err error) {
+	s.runErrorLock.Lock()
+	defer s.runErrorLock.Unlock()
+	s.runError = err
+}
+
+func (s *Service) loadRunError() error {
+	s.runErrorLock.RLock()
+	defer s.runErrorLock.RUnlock()
+	return s.runError
+}
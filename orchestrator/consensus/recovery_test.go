@@ -0,0 +1,93 @@
+package consensus
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+var errSaveFailed = errors.New("save failed")
+
+// failingVerifiedSlotInfoDB fails the given number of verified slot commits before it starts to persist them
+type failingVerifiedSlotInfoDB struct {
+	db.VerifiedSlotInfoDB
+	failures int
+}
+
+func (f *failingVerifiedSlotInfoDB) CommitVerifiedSlots(shardIndex uint64, verifiedSlots []*types.VerifiedSlot) error {
+	if f.failures > 0 {
+		f.failures--
+		return errSaveFailed
+	}
+	return f.VerifiedSlotInfoDB.CommitVerifiedSlots(shardIndex, verifiedSlots)
+}
+
+func setRecoveryBackoffs(t *testing.T) {
+	prevProcessRetryBackoff, prevRestartBackoff := processRetryBackoff, restartBackoff
+	processRetryBackoff, restartBackoff = time.Millisecond, 200*time.Millisecond
+	t.Cleanup(func() {
+		processRetryBackoff, restartBackoff = prevProcessRetryBackoff, prevRestartBackoff
+	})
+}
+
+func TestService_RetryFailedPersistence(t *testing.T) {
+	setRecoveryBackoffs(t)
+	ctx := context.Background()
+	svc, mockedFeed := setup(ctx, t)
+	defer svc.Stop()
+	svc.verifiedSlotInfoDB = &failingVerifiedSlotInfoDB{VerifiedSlotInfoDB: svc.verifiedSlotInfoDB, failures: 2}
+
+	slotInfoCh := make(chan *types.SlotInfoWithStatus, 1)
+	sub := svc.SubscribeVerifiedSlotInfoEvent(slotInfoCh)
+	defer sub.Unsubscribe()
+	svc.Start()
+	time.Sleep(100 * time.Millisecond)
+
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 2)
+	mockedFeed.headerInfoFeed.Send(headerInfos[0])
+	mockedFeed.shardInfoFeed.Send(shardInfos[0])
+
+	slotInfo := <-slotInfoCh
+	assert.Equal(t, types.Verified, slotInfo.Status)
+	assert.Equal(t, uint64(0), svc.CrashCount())
+	assert.NoError(t, svc.Status())
+}
+
+func TestService_RestartAfterCrash(t *testing.T) {
+	setRecoveryBackoffs(t)
+	ctx := context.Background()
+	svc, mockedFeed := setup(ctx, t)
+	defer svc.Stop()
+	svc.verifiedSlotInfoDB = &failingVerifiedSlotInfoDB{
+		VerifiedSlotInfoDB: svc.verifiedSlotInfoDB,
+		failures:           maxProcessRetries + 1,
+	}
+
+	slotInfoCh := make(chan *types.SlotInfoWithStatus, 1)
+	sub := svc.SubscribeVerifiedSlotInfoEvent(slotInfoCh)
+	defer sub.Unsubscribe()
+	svc.Start()
+	time.Sleep(100 * time.Millisecond)
+
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 2)
+	mockedFeed.headerInfoFeed.Send(headerInfos[0])
+	mockedFeed.shardInfoFeed.Send(shardInfos[0])
+	time.Sleep(100 * time.Millisecond)
+
+	// the loop has crashed and waits before it restarts
+	assert.Equal(t, uint64(1), svc.CrashCount())
+	require.ErrorContains(t, errSaveFailed.Error(), svc.Status())
+	require.ErrorContains(t, "consensus loop has crashed 1 times", svc.Status())
+
+	// the restarted loop processes the failed shard info again and verifies the slot without receiving it again
+	slotInfo := <-slotInfoCh
+	assert.Equal(t, types.Verified, slotInfo.Status)
+	assert.Equal(t, headerInfos[0].Header.Hash(), slotInfo.PandoraHeaderHash)
+	assert.NoError(t, svc.Status())
+}
@@ -0,0 +1,74 @@
+package consensus
+
+import (
+	"sort"
+
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// revertVerifiedSlots rolls back the verified slots of the shard from the fork slot when a vanguard block or a pandora header
+// with a different hash arrives for an already verified slot, or when the verified slots conflict with the vanguard
+// finalized checkpoint. Reverted slots become pending again and are verified
+// against the new branch when its sharding info and header arrive. Subscribers are notified with Reorged status.
+//
+// Neither vanguard nor pandora resends the payloads of the reverted slots, so they are put back into the pending caches.
+// The conflicting part of the fork slot is left out: its evidence is already stored and the arriving part replaces it,
+// so putting it back would store the same evidence again. The vanguard shard info of the fork slot is left out as well
+// because it commits to the replaced header or belongs to the replaced vanguard block.
+func (s *Service) revertVerifiedSlots(forkSlot uint64, shardIndex uint64, conflict types.EquivocationKind) error {
+	revertedSlots, err := s.verifiedSlotInfoDB.RevertVerifiedSlotInfos(forkSlot, shardIndex)
+	if err != nil {
+		log.WithField("forkSlot", forkSlot).WithField("shardIndex", shardIndex).WithError(err).
+			Error("Failed to revert verified slot infos")
+		return err
+	}
+
+	slots := make([]uint64, 0, len(revertedSlots))
+	for slot := range revertedSlots {
+		slots = append(slots, slot)
+	}
+	sort.Slice(slots, func(i, j int) bool { return slots[i] < slots[j] })
+
+	latestVerifiedSlot := s.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(shardIndex)
+	for _, slot := range slots {
+		revertedSlot := revertedSlots[slot]
+		if err := s.restorePendingSlot(revertedSlot, shardIndex, slot == forkSlot, conflict); err != nil {
+			return err
+		}
+		log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithField("forkSlot", forkSlot).
+			WithField("latestVerifiedSlot", latestVerifiedSlot).Warn("Reverted verified sharding info")
+		s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+			PandoraHeaderHash: revertedSlot.SlotInfo.PandoraHeaderHash,
+			VanguardBlockHash: revertedSlot.SlotInfo.VanguardBlockHash,
+			ShardIndex:        shardIndex,
+			Status:            types.Reorged,
+		})
+	}
+	return nil
+}
+
+// restorePendingSlot puts the stored payload of a reverted slot back into the pending caches. Only the pandora header
+// of the fork slot is restored, when vanguard has switched or finalized another branch. Payloads which are pruned are not restored.
+func (s *Service) restorePendingSlot(
+	revertedSlot *types.VerifiedSlot,
+	shardIndex uint64,
+	isForkSlot bool,
+	conflict types.EquivocationKind,
+) error {
+	slot := revertedSlot.Slot
+	if revertedSlot.Header != nil && !(isForkSlot && conflict == types.PandoraEquivocation) {
+		if err := s.pandoraPendingHeaderCache.Put(s.ctx, slot, shardIndex, revertedSlot.Header); err != nil {
+			log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithError(err).
+				Error("Failed to restore pending pandora header")
+			return err
+		}
+	}
+	if revertedSlot.ShardInfo != nil && !isForkSlot {
+		if err := s.vanguardPendingShardingCache.Put(s.ctx, slot, shardIndex, revertedSlot.ShardInfo); err != nil {
+			log.WithField("slot", slot).WithField("shardIndex", shardIndex).WithError(err).
+				Error("Failed to restore pending vanguard shard info")
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db/kv"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestService_Reorg(t *testing.T) {
+	ctx := context.Background()
+	svc, mockedFeed := setup(ctx, t)
+	defer svc.Stop()
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 4)
+	for i := range headerInfos {
+		require.NoError(t, svc.processPandoraHeader(headerInfos[i]))
+		require.NoError(t, svc.processVanguardShardInfo(shardInfos[i]))
+	}
+	assert.Equal(t, uint64(3), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+
+	slotInfoCh := make(chan *types.SlotInfoWithStatus, 10)
+	sub := svc.SubscribeVerifiedSlotInfoEvent(slotInfoCh)
+	defer sub.Unsubscribe()
+	svc.Start()
+	time.Sleep(100 * time.Millisecond)
+
+	// pandora switches to another branch from slot 2
+	reorgedHeader := testutil.NewEth1HeaderWithParent(2, headerInfos[0].Header.Hash())
+	reorgedHeader.Time++
+	testutil.SignEth1Header(reorgedHeader, types.ExtraData{Slot: 2, ProposerIndex: 2}, testutil.ValidatorKeys[2])
+	mockedFeed.headerInfoFeed.Send(&types.PandoraHeaderInfo{Slot: 2, Header: reorgedHeader})
+
+	for _, slotInfo := range []*types.SlotInfoWithStatus{
+		{PandoraHeaderHash: headerInfos[1].Header.Hash(), Status: types.Reorged},
+		{PandoraHeaderHash: headerInfos[2].Header.Hash(), Status: types.Reorged},
+	} {
+		slotInfoWithStatus := <-slotInfoCh
+		assert.Equal(t, slotInfo.Status, slotInfoWithStatus.Status)
+		assert.Equal(t, slotInfo.PandoraHeaderHash, slotInfoWithStatus.PandoraHeaderHash)
+	}
+	assert.Equal(t, uint64(1), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, headerInfos[0].Header.Hash(), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedHeaderHash(0))
+
+	// vanguard follows the new branch so slot 2 is verified again
+	mockedFeed.shardInfoFeed.Send(testutil.NewVanguardShardInfo(2, reorgedHeader))
+	slotInfoWithStatus := <-slotInfoCh
+	assert.Equal(t, types.Verified, slotInfoWithStatus.Status)
+	assert.Equal(t, reorgedHeader.Hash(), slotInfoWithStatus.PandoraHeaderHash)
+
+	slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(2, 0)
+	require.NoError(t, err)
+	assert.Equal(t, reorgedHeader.Hash(), slotInfo.PandoraHeaderHash)
+	slotInfo, err = svc.verifiedSlotInfoDB.VerifiedSlotInfo(3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+}
+
+func TestService_Reorg_RestoresPendingSlots(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 4)
+	for i := range headerInfos {
+		require.NoError(t, svc.Step(headerInfos[i]))
+		require.NoError(t, svc.Step(shardInfos[i]))
+	}
+	assert.Equal(t, uint64(3), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+
+	// vanguard switches to another block of slot 2 which holds the same pandora header
+	reorgedShardInfo := testutil.NewVanguardShardInfo(2, headerInfos[1].Header)
+	reorgedShardInfo.BlockHash = []byte("0x0b")
+	require.NoError(t, svc.Step(reorgedShardInfo))
+
+	// reverted headers and the shard info of slot 3 are pending again, so both slots are verified without a resend
+	assert.Equal(t, uint64(3), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	for slot := uint64(2); slot <= 3; slot++ {
+		slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(slot, 0)
+		require.NoError(t, err)
+		require.NotNil(t, slotInfo)
+		assert.Equal(t, headerInfos[slot-1].Header.Hash(), slotInfo.PandoraHeaderHash)
+	}
+	shardInfo, err := svc.verifiedPayloadDB.VerifiedShardInfo(2, 0)
+	require.NoError(t, err)
+	assert.DeepEqual(t, reorgedShardInfo.BlockHash, shardInfo.BlockHash)
+
+	// the replaced shard info of the fork slot is not pending again, so its evidence is stored once
+	equivocations, err := svc.equivocationDB.Equivocations(0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(equivocations))
+	assert.Equal(t, types.VanguardEquivocation, equivocations[0].Kind)
+}
+
+func TestService_Reorg_PrunedSlots(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	saveConsensusInfos(t, svc, 2)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 70)
+	for i := range headerInfos {
+		require.NoError(t, svc.Step(headerInfos[i]))
+		require.NoError(t, svc.Step(shardInfos[i]))
+	}
+	// slots before epoch 2 are pruned
+	_, err := svc.verifiedSlotInfoDB.(*kv.Store).Prune(ctx, &kv.PruneConfig{Policy: kv.PruneEpochs, RetainEpochs: 1})
+	require.NoError(t, err)
+
+	slotInfoCh := make(chan *types.SlotInfoWithStatus, 10)
+	sub := svc.SubscribeVerifiedSlotInfoEvent(slotInfoCh)
+	defer sub.Unsubscribe()
+
+	// pandora switches to another branch from the first retained slot, which can not be reverted
+	reorgedHeader := testutil.NewEth1HeaderWithParent(64, headerInfos[62].Header.Hash())
+	reorgedHeader.Time++
+	testutil.SignEth1Header(reorgedHeader, types.ExtraData{Slot: 64, Epoch: 2}, testutil.ValidatorKeys[0])
+	require.NoError(t, svc.Step(&types.PandoraHeaderInfo{Slot: 64, Header: reorgedHeader}))
+
+	slotInfoWithStatus := <-slotInfoCh
+	assert.Equal(t, types.Invalid, slotInfoWithStatus.Status)
+	assert.Equal(t, reorgedHeader.Hash(), slotInfoWithStatus.PandoraHeaderHash)
+	assert.Equal(t, uint64(69), svc.verifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0))
+	slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(64, 0)
+	require.NoError(t, err)
+	require.NotNil(t, slotInfo)
+	assert.Equal(t, headerInfos[63].Header.Hash(), slotInfo.PandoraHeaderHash)
+}
@@ -2,26 +2,59 @@ package consensus
 
 import (
 	"context"
+	"fmt"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/event"
 	"sync"
+	"time"
 
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/cache"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
 	iface2 "github.com/lukso-network/lukso-orchestrator/orchestrator/pandorachain/iface"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/vanguardchain/iface"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
+	"github.com/pkg/errors"
 )
 
 type Config struct {
+	ConsensusInfoDB              db.ROnlyConsensusInfoDB
 	VerifiedSlotInfoDB           db.VerifiedSlotInfoDB
 	InvalidSlotInfoDB            db.InvalidSlotInfoDB
+	SkippedSlotInfoDB            db.SkippedSlotInfoDB
+	TimedOutSlotInfoDB           db.TimedOutSlotInfoDB
+	EquivocationDB               db.EquivocationDB
+	VerdictDB                    db.VerdictDB
+	VerifiedPayloadDB            db.ROnlyVerifiedPayloadDB
 	VanguardPendingShardingCache cache.VanguardShardCache
 	PandoraPendingHeaderCache    cache.PandoraHeaderCache
 
-	VanguardShardFeed iface.VanguardShardInfoFeed
-	PandoraHeaderFeed iface2.PandoraHeaderFeed
+	VanguardShardFeed       iface.VanguardShardInfoFeed
+	PandoraHeaderFeed       iface2.PandoraHeaderFeed
+	FinalizedCheckpointFeed iface.FinalizedCheckpointFeed
+	// ConsensusInfoFeed delivers the consensus info of new epochs. Pending slots of the epoch are verified again.
+	ConsensusInfoFeed iface.ConsensusInfoFeed
+
+	// ShardCount is the number of pandora shards which are finalized by vanguard checkpoints. Zero means one shard.
+	ShardCount uint64
+
+	// VerificationRules are the names of the rules which verify sharding info in order. Default rules are applied
+	// when it is empty.
+	VerificationRules []string
+
+	// SlotTimeTolerance is the number of seconds which a pandora header timestamp may be out of its slot window
+	SlotTimeTolerance uint64
+
+	// PendingSlotTimeout is the number of slots after the start of a slot until which the slot stays pending.
+	// Zero disables the expiry of pending slots.
+	PendingSlotTimeout uint64
+
+	// Clock tells the time at which pending slots are expired. The wall clock is used when it is nil.
+	Clock Clock
+
+	// CatchUpBatchSize is the maximum number of queued events which are verified together while the orchestrator is
+	// catching up. Zero or one disables batching.
+	CatchUpBatchSize uint64
 }
 
 // Service This part could be moved to other place during refactor, might be registered as a service
@@ -31,36 +64,89 @@ type Service struct {
 	ctx            context.Context
 	cancel         context.CancelFunc
 	runError       error
+	runErrorLock   sync.RWMutex
+	crashCount     uint64
+	failedStep     func() error
 
 	scope                        event.SubscriptionScope
+	consensusInfoDB              db.ROnlyConsensusInfoDB
 	verifiedSlotInfoDB           db.VerifiedSlotInfoDB
 	invalidSlotInfoDB            db.InvalidSlotInfoDB
+	skippedSlotInfoDB            db.SkippedSlotInfoDB
+	timedOutSlotInfoDB           db.TimedOutSlotInfoDB
+	equivocationDB               db.EquivocationDB
+	verdictDB                    db.VerdictDB
+	verifiedPayloadDB            db.ROnlyVerifiedPayloadDB
 	vanguardPendingShardingCache cache.VanguardShardCache
 	pandoraPendingHeaderCache    cache.PandoraHeaderCache
 
-	vanguardShardFeed    iface.VanguardShardInfoFeed
-	pandoraHeaderFeed    iface2.PandoraHeaderFeed
-	verifiedSlotInfoFeed event.Feed
+	vanguardShardFeed       iface.VanguardShardInfoFeed
+	pandoraHeaderFeed       iface2.PandoraHeaderFeed
+	finalizedCheckpointFeed iface.FinalizedCheckpointFeed
+	consensusInfoFeed       iface.ConsensusInfoFeed
+	verifiedSlotInfoFeed    event.Feed
+
+	verifiers          VerifierChain
+	pendingSlotTimeout uint64
+	shardCount         uint64
+	clock              Clock
+	catchUpBatchSize   uint64
+	// slot and block root of the latest vanguard finalized checkpoint
+	vanguardFinalizedSlot uint64
+	vanguardFinalizedRoot common.Hash
+	// pending slots which wait for their missing parent by shard
+	verificationGaps map[uint64]*verificationGap
+	gapLock          sync.Mutex
 }
 
 //
-func New(ctx context.Context, cfg *Config) (service *Service) {
+func New(ctx context.Context, cfg *Config) (*Service, error) {
+	// every service builds its own chain so that the tolerance of one service does not change the rules of another
+	verifiers, err := NewVerifierChain(cfg.VerificationRules, cfg.SlotTimeTolerance)
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(ctx)
 	_ = cancel // govet fix for lost cancel. Cancel is handled in service.Stop()
 
-	latestVerifiedSlot := cfg.VerifiedSlotInfoDB.InMemoryLatestVerifiedSlot()
+	shardCount := cfg.ShardCount
+	if shardCount == 0 {
+		shardCount = 1
+	}
+
+	clock := cfg.Clock
+	if clock == nil {
+		clock = systemClock{}
+	}
+
+	latestVerifiedSlot := cfg.VerifiedSlotInfoDB.InMemoryLatestVerifiedSlot(0)
 	log.WithField("latestVerifiedSlot", latestVerifiedSlot).Debug("Initializing consensus service")
 
 	return &Service{
 		ctx:                          ctx,
 		cancel:                       cancel,
+		consensusInfoDB:              cfg.ConsensusInfoDB,
 		verifiedSlotInfoDB:           cfg.VerifiedSlotInfoDB,
 		invalidSlotInfoDB:            cfg.InvalidSlotInfoDB,
+		skippedSlotInfoDB:            cfg.SkippedSlotInfoDB,
+		timedOutSlotInfoDB:           cfg.TimedOutSlotInfoDB,
+		equivocationDB:               cfg.EquivocationDB,
+		verdictDB:                    cfg.VerdictDB,
+		verifiedPayloadDB:            cfg.VerifiedPayloadDB,
 		vanguardPendingShardingCache: cfg.VanguardPendingShardingCache,
 		pandoraPendingHeaderCache:    cfg.PandoraPendingHeaderCache,
 		vanguardShardFeed:            cfg.VanguardShardFeed,
 		pandoraHeaderFeed:            cfg.PandoraHeaderFeed,
-	}
+		finalizedCheckpointFeed:      cfg.FinalizedCheckpointFeed,
+		consensusInfoFeed:            cfg.ConsensusInfoFeed,
+		verifiers:                    verifiers,
+		pendingSlotTimeout:           cfg.PendingSlotTimeout,
+		shardCount:                   shardCount,
+		clock:                        clock,
+		catchUpBatchSize:             cfg.CatchUpBatchSize,
+		verificationGaps:             make(map[uint64]*verificationGap),
+	}, nil
 }
 
 func (s *Service) Start() {
@@ -69,58 +155,292 @@ func (s *Service) Start() {
 		return
 	}
 	s.isRunning = true
-	go func() {
-		log.Info("Starting consensus service")
-		vanShardInfoCh := make(chan *types.VanguardShardInfo)
-		panHeaderInfoCh := make(chan *types.PandoraHeaderInfo)
-
-		vanShardInfoSub := s.vanguardShardFeed.SubscribeShardInfoEvent(vanShardInfoCh)
-		panHeaderInfoSub := s.pandoraHeaderFeed.SubscribeHeaderInfoEvent(panHeaderInfoCh)
-
-		for {
-			select {
-			case newPanHeaderInfo := <-panHeaderInfoCh:
-				if slotInfo, _ := s.verifiedSlotInfoDB.VerifiedSlotInfo(newPanHeaderInfo.Slot); slotInfo != nil {
-					if slotInfo.PandoraHeaderHash == newPanHeaderInfo.Header.Hash() {
-						log.WithField("slot", newPanHeaderInfo.Slot).
-							WithField("headerHash", newPanHeaderInfo.Header.Hash()).
-							Info("Pandora header is already in verified slot info db")
-
-						s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
-							VanguardBlockHash: slotInfo.VanguardBlockHash,
-							PandoraHeaderHash: slotInfo.PandoraHeaderHash,
-							Status:            types.Verified,
-						})
-						continue
-					}
-				}
-				if err := s.processPandoraHeader(newPanHeaderInfo); err != nil {
-					log.WithField("error", err).Error("error found while processing pandora header")
-					return
-				}
-			case newVanShardInfo := <-vanShardInfoCh:
-				if slotInfo, _ := s.verifiedSlotInfoDB.VerifiedSlotInfo(newVanShardInfo.Slot); slotInfo != nil {
-					blockHashHex := common.BytesToHash(newVanShardInfo.BlockHash[:])
-					if slotInfo.VanguardBlockHash == blockHashHex {
-						log.WithField("slot", newVanShardInfo.Slot).
-							WithField("shardInfoHash", hexutil.Encode(newVanShardInfo.ShardInfo.Hash)).
-							Info("Vanguard shard info is already in verified slot info db")
-						//TODO(Atif)- Need to send this verified slot info to vanguard subscriber
-						continue
-					}
+	go s.supervise()
+}
+
+// run subscribes to the vanguard and pandora feeds and processes the incoming events until the context is
+// cancelled. It returns the error of an event which could not be processed even after retrying.
+func (s *Service) run() error {
+	log.Info("Starting consensus service")
+	// events are queued while the previous ones are processed, so that they can be verified in batches
+	vanShardInfoCh := make(chan *types.VanguardShardInfo, s.catchUpBatchSize)
+	panHeaderInfoCh := make(chan *types.PandoraHeaderInfo, s.catchUpBatchSize)
+	finalizedCheckpointCh := make(chan *types.FinalizedCheckpoint)
+
+	vanShardInfoSub := s.vanguardShardFeed.SubscribeShardInfoEvent(vanShardInfoCh)
+	panHeaderInfoSub := s.pandoraHeaderFeed.SubscribeHeaderInfoEvent(panHeaderInfoCh)
+	defer vanShardInfoSub.Unsubscribe()
+	defer panHeaderInfoSub.Unsubscribe()
+	finalizedCheckpointSub := s.finalizedCheckpointFeed.SubscribeFinalizedCheckpointEvent(finalizedCheckpointCh)
+	defer finalizedCheckpointSub.Unsubscribe()
+	consensusInfoCh := make(chan *types.MinimalEpochConsensusInfo)
+	consensusInfoSub := s.consensusInfoFeed.SubscribeMinConsensusInfoEvent(consensusInfoCh)
+	defer consensusInfoSub.Unsubscribe()
+
+	if err := s.processFailedStep(); err != nil {
+		log.WithField("error", err).Error("error found while processing failed event again")
+		return err
+	}
+
+	ticker := time.NewTicker(pendingSlotCheckPeriod)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case newPanHeaderInfo := <-panHeaderInfoCh:
+			if s.isCatchingUp(panHeaderInfoCh, vanShardInfoCh) {
+				events := s.queuedEvents(newPanHeaderInfo, panHeaderInfoCh, vanShardInfoCh)
+				if err := s.process(func() error { return s.StepBatch(events) }); err != nil {
+					log.WithField("error", err).Error("error found while processing catch-up batch")
+					return err
 				}
-				if err := s.processVanguardShardInfo(newVanShardInfo); err != nil {
-					log.WithField("error", err).Error("error found while processing vanguard sharding info")
-					return
+				continue
+			}
+			if err := s.process(func() error {
+				return s.onNewPandoraHeaderInfo(newPanHeaderInfo)
+			}); err != nil {
+				log.WithField("error", err).Error("error found while processing pandora header")
+				return err
+			}
+		case newVanShardInfo := <-vanShardInfoCh:
+			if s.isCatchingUp(panHeaderInfoCh, vanShardInfoCh) {
+				events := s.queuedEvents(newVanShardInfo, panHeaderInfoCh, vanShardInfoCh)
+				if err := s.process(func() error { return s.StepBatch(events) }); err != nil {
+					log.WithField("error", err).Error("error found while processing catch-up batch")
+					return err
 				}
-			case <-s.ctx.Done():
-				vanShardInfoSub.Unsubscribe()
-				panHeaderInfoSub.Unsubscribe()
-				log.Info("Received cancelled context,closing existing consensus service")
-				return
+				continue
+			}
+			if err := s.process(func() error {
+				return s.onNewVanguardShardInfo(newVanShardInfo)
+			}); err != nil {
+				log.WithField("error", err).Error("error found while processing vanguard sharding info")
+				return err
+			}
+		case checkpoint := <-finalizedCheckpointCh:
+			if err := s.process(func() error {
+				return s.onNewFinalizedCheckpoint(checkpoint)
+			}); err != nil {
+				log.WithField("error", err).Error("error found while finalizing verified slots")
+				return err
+			}
+		case consensusInfo := <-consensusInfoCh:
+			if err := s.process(func() error {
+				return s.onNewConsensusInfo(consensusInfo)
+			}); err != nil {
+				log.WithField("error", err).Error("error found while verifying pending slots of new epoch")
+				return err
+			}
+		case <-ticker.C:
+			if err := s.process(s.ExpirePendingSlots); err != nil {
+				log.WithField("error", err).Error("error found while expiring pending slots")
+				return err
+			}
+		case <-s.ctx.Done():
+			log.Info("Received cancelled context,closing existing consensus service")
+			return nil
+		}
+	}
+}
+
+// Step processes a single pandora header info, vanguard shard info, finalized checkpoint or consensus info synchronously in the same
+// way as the running service does. It lets tests drive the service event by event without starting it.
+func (s *Service) Step(ev interface{}) error {
+	switch ev := ev.(type) {
+	case *types.PandoraHeaderInfo:
+		return s.onNewPandoraHeaderInfo(ev)
+	case *types.VanguardShardInfo:
+		return s.onNewVanguardShardInfo(ev)
+	case *types.FinalizedCheckpoint:
+		return s.onNewFinalizedCheckpoint(ev)
+	case *types.MinimalEpochConsensusInfo:
+		return s.onNewConsensusInfo(ev)
+	default:
+		return fmt.Errorf("unsupported consensus event %T", ev)
+	}
+}
+
+// ExpirePendingSlots times out the pending slots whose deadline has passed by the time of the service clock
+func (s *Service) ExpirePendingSlots() error {
+	return s.expirePendingSlots(s.clock.Now())
+}
+
+// onNewPandoraHeaderInfo processes a pandora header which has arrived from pandora chain service
+func (s *Service) onNewPandoraHeaderInfo(newPanHeaderInfo *types.PandoraHeaderInfo) error {
+	if slotInfo, _ := s.verifiedSlotInfoDB.VerifiedSlotInfo(
+		newPanHeaderInfo.Slot, newPanHeaderInfo.ShardIndex); slotInfo != nil {
+		if slotInfo.PandoraHeaderHash == newPanHeaderInfo.Header.Hash() {
+			log.WithField("slot", newPanHeaderInfo.Slot).
+				WithField("shardIndex", newPanHeaderInfo.ShardIndex).
+				WithField("headerHash", newPanHeaderInfo.Header.Hash()).
+				Info("Pandora header is already in verified slot info db")
+
+			s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+				VanguardBlockHash: slotInfo.VanguardBlockHash,
+				PandoraHeaderHash: slotInfo.PandoraHeaderHash,
+				ShardIndex:        newPanHeaderInfo.ShardIndex,
+				Status:            s.verifiedStatus(newPanHeaderInfo.Slot, newPanHeaderInfo.ShardIndex),
+			})
+			return nil
+		}
+		// evidence is taken from the verified payload before a revert removes it
+		if err := s.detectPandoraEquivocation(newPanHeaderInfo); err != nil {
+			return err
+		}
+		if s.isFinalized(newPanHeaderInfo.Slot, newPanHeaderInfo.ShardIndex) {
+			log.WithField("slot", newPanHeaderInfo.Slot).
+				WithField("shardIndex", newPanHeaderInfo.ShardIndex).
+				WithField("headerHash", newPanHeaderInfo.Header.Hash()).
+				WithField("verifiedHeaderHash", slotInfo.PandoraHeaderHash).
+				Error("Pandora header conflicts with finalized slot info, rejecting")
+
+			s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+				PandoraHeaderHash: newPanHeaderInfo.Header.Hash(),
+				ShardIndex:        newPanHeaderInfo.ShardIndex,
+				Status:            types.Invalid,
+			})
+			return nil
+		}
+		log.WithField("slot", newPanHeaderInfo.Slot).
+			WithField("shardIndex", newPanHeaderInfo.ShardIndex).
+			WithField("headerHash", newPanHeaderInfo.Header.Hash()).
+			WithField("verifiedHeaderHash", slotInfo.PandoraHeaderHash).
+			Warn("Pandora header conflicts with verified slot info, reorg detected")
+		if err := s.revertVerifiedSlots(newPanHeaderInfo.Slot, newPanHeaderInfo.ShardIndex, types.PandoraEquivocation); err != nil {
+			var prunedErr *db.PrunedRevertError
+			if errors.As(err, &prunedErr) {
+				// verified chain can not go back past its pruned slots, so the header is rejected like a finalized one
+				s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+					PandoraHeaderHash: newPanHeaderInfo.Header.Hash(),
+					ShardIndex:        newPanHeaderInfo.ShardIndex,
+					Status:            types.Invalid,
+				})
+				return nil
+			}
+			log.WithField("error", err).Error("error found while reverting verified slots")
+			return err
+		}
+	}
+	if slotInfo, _ := s.timedOutSlotInfoDB.TimedOutSlotInfo(
+		newPanHeaderInfo.Slot, newPanHeaderInfo.ShardIndex); slotInfo != nil {
+		log.WithField("slot", newPanHeaderInfo.Slot).
+			WithField("shardIndex", newPanHeaderInfo.ShardIndex).
+			WithField("headerHash", newPanHeaderInfo.Header.Hash()).
+			Info("Pandora header has arrived after the slot is timed out")
+
+		s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+			PandoraHeaderHash: newPanHeaderInfo.Header.Hash(),
+			ShardIndex:        newPanHeaderInfo.ShardIndex,
+			Status:            types.TimedOut,
+		})
+		return nil
+	}
+	return s.processPandoraHeader(newPanHeaderInfo)
+}
+
+// onNewVanguardShardInfo processes a vanguard shard info which has arrived from vanguard chain service
+func (s *Service) onNewVanguardShardInfo(newVanShardInfo *types.VanguardShardInfo) error {
+	if slotInfo, _ := s.verifiedSlotInfoDB.VerifiedSlotInfo(
+		newVanShardInfo.Slot, newVanShardInfo.ShardIndex); slotInfo != nil {
+		blockHashHex := common.BytesToHash(newVanShardInfo.BlockHash[:])
+		if slotInfo.VanguardBlockHash == blockHashHex {
+			log.WithField("slot", newVanShardInfo.Slot).
+				WithField("shardIndex", newVanShardInfo.ShardIndex).
+				WithField("shardInfoHash", hexutil.Encode(newVanShardInfo.ShardInfo.Hash)).
+				Info("Vanguard shard info is already in verified slot info db")
+
+			s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+				VanguardBlockHash: slotInfo.VanguardBlockHash,
+				PandoraHeaderHash: slotInfo.PandoraHeaderHash,
+				ShardIndex:        newVanShardInfo.ShardIndex,
+				Status:            s.verifiedStatus(newVanShardInfo.Slot, newVanShardInfo.ShardIndex),
+			})
+			return nil
+		}
+		// evidence is taken from the verified payload before a revert removes it
+		if err := s.detectVanguardEquivocation(newVanShardInfo); err != nil {
+			return err
+		}
+		if s.isFinalized(newVanShardInfo.Slot, newVanShardInfo.ShardIndex) {
+			log.WithField("slot", newVanShardInfo.Slot).
+				WithField("shardIndex", newVanShardInfo.ShardIndex).
+				WithField("blockHash", blockHashHex).
+				WithField("verifiedBlockHash", slotInfo.VanguardBlockHash).
+				Error("Vanguard shard info conflicts with finalized slot info, rejecting")
+
+			s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+				VanguardBlockHash: blockHashHex,
+				ShardIndex:        newVanShardInfo.ShardIndex,
+				Status:            types.Invalid,
+			})
+			return nil
+		}
+		log.WithField("slot", newVanShardInfo.Slot).
+			WithField("shardIndex", newVanShardInfo.ShardIndex).
+			WithField("blockHash", blockHashHex).
+			WithField("verifiedBlockHash", slotInfo.VanguardBlockHash).
+			Warn("Vanguard shard info conflicts with verified slot info, reorg detected")
+		if err := s.revertVerifiedSlots(newVanShardInfo.Slot, newVanShardInfo.ShardIndex, types.VanguardEquivocation); err != nil {
+			var prunedErr *db.PrunedRevertError
+			if errors.As(err, &prunedErr) {
+				// verified chain can not go back past its pruned slots, so the shard info is rejected like a finalized one
+				s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+					VanguardBlockHash: blockHashHex,
+					ShardIndex:        newVanShardInfo.ShardIndex,
+					Status:            types.Invalid,
+				})
+				return nil
 			}
+			log.WithField("error", err).Error("error found while reverting verified slots")
+			return err
 		}
-	}()
+	}
+	if slotInfo, _ := s.timedOutSlotInfoDB.TimedOutSlotInfo(
+		newVanShardInfo.Slot, newVanShardInfo.ShardIndex); slotInfo != nil {
+		log.WithField("slot", newVanShardInfo.Slot).
+			WithField("shardIndex", newVanShardInfo.ShardIndex).
+			WithField("shardInfoHash", hexutil.Encode(newVanShardInfo.ShardInfo.Hash)).
+			Info("Vanguard shard info has arrived after the slot is timed out")
+
+		s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+			VanguardBlockHash: common.BytesToHash(newVanShardInfo.BlockHash[:]),
+			ShardIndex:        newVanShardInfo.ShardIndex,
+			Status:            types.TimedOut,
+		})
+		return nil
+	}
+	if slotInfo, status := s.replayedVanguardStatus(newVanShardInfo); slotInfo != nil {
+		log.WithField("slot", newVanShardInfo.Slot).
+			WithField("shardIndex", newVanShardInfo.ShardIndex).
+			WithField("status", status).
+			Info("Vanguard shard info is already decided")
+
+		s.verifiedSlotInfoFeed.Send(&types.SlotInfoWithStatus{
+			VanguardBlockHash: slotInfo.VanguardBlockHash,
+			PandoraHeaderHash: slotInfo.PandoraHeaderHash,
+			ShardIndex:        newVanShardInfo.ShardIndex,
+			Status:            status,
+		})
+		return nil
+	}
+	return s.processVanguardShardInfo(newVanShardInfo)
+}
+
+// replayedVanguardStatus returns the stored slot info and the status of a vanguard block which has already been
+// declared invalid or skipped. Vanguard replays its blocks after a restart and must get the same status again. The
+// slot info is nil when the block is not decided yet.
+func (s *Service) replayedVanguardStatus(vanShardInfo *types.VanguardShardInfo) (*types.SlotInfo, types.Status) {
+	blockHash := common.BytesToHash(vanShardInfo.BlockHash[:])
+	slot, shardIndex := vanShardInfo.Slot, vanShardInfo.ShardIndex
+	if slotInfo, _ := s.invalidSlotInfoDB.InvalidSlotInfo(slot, shardIndex); slotInfo != nil &&
+		slotInfo.VanguardBlockHash == blockHash {
+		return &slotInfo.SlotInfo, types.Invalid
+	}
+	if slotInfo, _ := s.skippedSlotInfoDB.SkippedSlotInfo(slot, shardIndex); slotInfo != nil &&
+		slotInfo.VanguardBlockHash == blockHash {
+		return slotInfo, types.Skipped
+	}
+	return nil, types.Pending
 }
 
 func (s *Service) Stop() error {
@@ -135,9 +455,9 @@ func (s *Service) Status() error {
 	if !s.isRunning {
 		return nil
 	}
-	// get error from run function
-	if s.runError != nil {
-		return s.runError
+	// get error from run function with the number of crashes, so that a loop which keeps crashing is noticed
+	if err := s.loadRunError(); err != nil {
+		return errors.Wrapf(err, "consensus loop has crashed %d times", s.CrashCount())
 	}
 	return nil
 }
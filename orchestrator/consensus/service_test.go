@@ -2,6 +2,7 @@ package consensus
 
 import (
 	"context"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
@@ -53,16 +54,16 @@ func TestService(t *testing.T) {
 
 			for i := 0; i < 5; i++ {
 				slot := tt.vanShardInfos[i].Slot
-				svc.vanguardPendingShardingCache.Put(ctx, slot, tt.vanShardInfos[i])
+				svc.vanguardPendingShardingCache.Put(ctx, slot, 0, tt.vanShardInfos[i])
 				mockedFeed.shardInfoFeed.Send(tt.vanShardInfos[i])
 
 				time.Sleep(5 * time.Millisecond)
 
-				svc.pandoraPendingHeaderCache.Put(ctx, slot, tt.panHeaderInfos[i].Header)
+				svc.pandoraPendingHeaderCache.Put(ctx, slot, 0, tt.panHeaderInfos[i].Header)
 				mockedFeed.headerInfoFeed.Send(tt.panHeaderInfos[i])
 
 				time.Sleep(100 * time.Millisecond)
-				slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(slot)
+				slotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(slot, 0)
 				require.NoError(t, err)
 				assert.NotNil(t, slotInfo)
 			}
@@ -73,3 +74,74 @@ func TestService(t *testing.T) {
 		})
 	}
 }
+
+func TestService_SkipUnmatchedSlots(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 6)
+
+	slotInfoCh := make(chan *types.SlotInfoWithStatus, 10)
+	sub := svc.SubscribeVerifiedSlotInfoEvent(slotInfoCh)
+	defer sub.Unsubscribe()
+
+	// slot 1 only has pandora header and slot 2 only has vanguard shard info
+	require.NoError(t, svc.processPandoraHeader(headerInfos[0]))
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[1]))
+	// slot 3 is verified so slot 1 and 2 are skipped
+	require.NoError(t, svc.processPandoraHeader(headerInfos[2]))
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[2]))
+
+	expectedStatuses := []types.Status{types.Skipped, types.Skipped, types.Verified}
+	for _, expectedStatus := range expectedStatuses {
+		slotInfoWithStatus := <-slotInfoCh
+		assert.Equal(t, expectedStatus, slotInfoWithStatus.Status)
+	}
+
+	slotInfo, err := svc.skippedSlotInfoDB.SkippedSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, headerInfos[0].Header.Hash(), slotInfo.PandoraHeaderHash)
+
+	slotInfo, err = svc.skippedSlotInfoDB.SkippedSlotInfo(2, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, slotInfo)
+
+	slotInfo, err = svc.skippedSlotInfoDB.SkippedSlotInfo(3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+}
+
+func TestService_VanguardReplay(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 3)
+	require.NoError(t, svc.processPandoraHeader(headerInfos[0]))
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[0]))
+	invalidSlotInfo := &types.InvalidSlotInfo{
+		SlotInfo: types.SlotInfo{
+			VanguardBlockHash: common.BytesToHash(shardInfos[1].BlockHash),
+			PandoraHeaderHash: headerInfos[1].Header.Hash(),
+		},
+		Reason: types.StateRootMismatch,
+	}
+	require.NoError(t, svc.invalidSlotInfoDB.SaveInvalidSlotInfo(2, 0, invalidSlotInfo))
+
+	slotInfoCh := make(chan *types.SlotInfoWithStatus, 2)
+	sub := svc.SubscribeVerifiedSlotInfoEvent(slotInfoCh)
+	defer sub.Unsubscribe()
+
+	// vanguard restarts and replays its blocks
+	require.NoError(t, svc.onNewVanguardShardInfo(shardInfos[0]))
+	require.NoError(t, svc.onNewVanguardShardInfo(shardInfos[1]))
+
+	assert.DeepEqual(t, &types.SlotInfoWithStatus{
+		VanguardBlockHash: common.BytesToHash(shardInfos[0].BlockHash),
+		PandoraHeaderHash: headerInfos[0].Header.Hash(),
+		Status:            types.Verified,
+	}, <-slotInfoCh)
+	// pandora subscribers get the status of the stored pandora header as well
+	assert.DeepEqual(t, &types.SlotInfoWithStatus{
+		VanguardBlockHash: common.BytesToHash(shardInfos[1].BlockHash),
+		PandoraHeaderHash: headerInfos[1].Header.Hash(),
+		Status:            types.Invalid,
+	}, <-slotInfoCh)
+}
@@ -9,17 +9,19 @@ import (
 	eth2Types "github.com/prysmaticlabs/prysm/proto/eth/v1alpha1"
 )
 
-func CompareShardingInfo(ph *eth1Types.Header, vs *eth2Types.PandoraShard) bool {
+// CompareShardingInfo compares pandora header with the pandora shard of vanguard block. It returns the mismatched
+// field or NoMismatch.
+func CompareShardingInfo(ph *eth1Types.Header, vs *eth2Types.PandoraShard) types.MismatchReason {
 	if ph == nil && vs == nil {
 		// in existing code this will happen. as some part may have no sharding info for testing.
-		return true
+		return types.NoMismatch
 	}
 
 	if vs.BlockNumber != ph.Number.Uint64() {
 		log.WithField("pandora data block number", ph.Number.Uint64()).
 			WithField("vanguard block number", vs.BlockNumber).
 			Error("block number mismatched")
-		return false
+		return types.BlockNumberMismatch
 	}
 
 	// match header hash
@@ -27,7 +29,7 @@ func CompareShardingInfo(ph *eth1Types.Header, vs *eth2Types.PandoraShard) bool
 		log.WithField("pandora header hash", ph.Hash()).
 			WithField("vanguard header hash", hexutil.Encode(vs.GetHash())).
 			Error("header hash mismatched")
-		return false
+		return types.HeaderHashMismatch
 	}
 
 	// match parent hash
@@ -35,7 +37,7 @@ func CompareShardingInfo(ph *eth1Types.Header, vs *eth2Types.PandoraShard) bool
 		log.WithField("pandora data parent hash", ph.ParentHash).
 			WithField("vanguard parent hash", hexutil.Encode(vs.ParentHash)).
 			Error("parent hash mismatched")
-		return false
+		return types.ParentHashMismatch
 	}
 
 	// match state root hash
@@ -43,7 +45,7 @@ func CompareShardingInfo(ph *eth1Types.Header, vs *eth2Types.PandoraShard) bool
 		log.WithField("pandora data root hash", ph.Root).
 			WithField("vanguard state root hash", hexutil.Encode(vs.StateRoot)).
 			Error("state root hash mismatched")
-		return false
+		return types.StateRootMismatch
 	}
 
 	// match TxHash
@@ -51,7 +53,7 @@ func CompareShardingInfo(ph *eth1Types.Header, vs *eth2Types.PandoraShard) bool
 		log.WithField("pandora data tx hash", ph.TxHash).
 			WithField("vanguard tx hash", hexutil.Encode(vs.TxHash)).
 			Error("tx hash mismatched")
-		return false
+		return types.TxHashMismatch
 	}
 
 	// match receiptHash
@@ -59,7 +61,7 @@ func CompareShardingInfo(ph *eth1Types.Header, vs *eth2Types.PandoraShard) bool
 		log.WithField("pandora data receipt hash", ph.ReceiptHash).
 			WithField("vanguard receipt hash", hexutil.Encode(vs.ReceiptHash)).
 			Error("receipt hash mismatched")
-		return false
+		return types.ReceiptHashMismatch
 	}
 
 	// retrieve extra data
@@ -68,7 +70,7 @@ func CompareShardingInfo(ph *eth1Types.Header, vs *eth2Types.PandoraShard) bool
 	if nil != err {
 		log.WithField("error", err).
 			Error("error converting extra data to extraDataWithSig")
-		return false
+		return types.ExtraDataDecodeError
 	}
 
 	// match signature
@@ -76,8 +78,64 @@ func CompareShardingInfo(ph *eth1Types.Header, vs *eth2Types.PandoraShard) bool
 		log.WithField("pandora data signature", hexutil.Encode(pandoraExtraDataWithSig.BlsSignatureBytes.Bytes())).
 			WithField("vanguard signature", hexutil.Encode(vs.GetSignature())).
 			Error("signature mismatched")
-		return false
+		return types.SignatureMismatch
 	}
 
-	return true
+	return types.NoMismatch
+}
+
+// CompareTimestamp checks that the pandora header timestamp falls into the window of the slot. The window starts
+// at the slot start time and ends at the start time of the next slot, both are widened by the tolerance in seconds.
+func CompareTimestamp(
+	slot uint64,
+	timestamp uint64,
+	consensusInfo *types.MinimalEpochConsensusInfo,
+	tolerance uint64,
+) types.MismatchReason {
+	slotStartTime, slotEndTime := consensusInfo.SlotStartTime(slot), consensusInfo.SlotEndTime(slot)
+	if timestamp+tolerance < slotStartTime || timestamp >= slotEndTime+tolerance {
+		log.WithField("pandora header timestamp", timestamp).
+			WithField("slot start time", slotStartTime).
+			WithField("slot end time", slotEndTime).
+			WithField("tolerance", tolerance).
+			Error("timestamp is out of slot time")
+		return types.TimestampMismatch
+	}
+	return types.NoMismatch
+}
+
+// CompareExtraData checks slot, epoch and proposer index of pandora extra data against the slot on which the header
+// is matched and the validator list of the slot's epoch. It returns the mismatched field or NoMismatch.
+func CompareExtraData(
+	slot uint64,
+	extraData *types.ExtraData,
+	consensusInfo *types.MinimalEpochConsensusInfo,
+) types.MismatchReason {
+	if extraData.Slot != slot {
+		log.WithField("pandora extra data slot", extraData.Slot).
+			WithField("matched slot", slot).
+			Error("slot mismatched")
+		return types.SlotMismatch
+	}
+
+	expectedEpoch := slot / types.SlotsPerEpoch
+	if extraData.Epoch != expectedEpoch || consensusInfo.Epoch != expectedEpoch {
+		log.WithField("pandora extra data epoch", extraData.Epoch).
+			WithField("consensus info epoch", consensusInfo.Epoch).
+			WithField("expected epoch", expectedEpoch).
+			Error("epoch mismatched")
+		return types.EpochMismatch
+	}
+
+	// validator list of the epoch assigns one proposer for each slot of the epoch
+	expectedProposerIndex := slot % types.SlotsPerEpoch
+	if extraData.ProposerIndex != expectedProposerIndex ||
+		expectedProposerIndex >= uint64(len(consensusInfo.ValidatorList)) {
+		log.WithField("pandora extra data proposer index", extraData.ProposerIndex).
+			WithField("expected proposer index", expectedProposerIndex).
+			WithField("validator list length", len(consensusInfo.ValidatorList)).
+			Error("proposer index mismatched")
+		return types.ProposerIndexMismatch
+	}
+	return types.NoMismatch
 }
@@ -1 +1,173 @@
-package consensus
\ No newline at end of file
+package consensus
+
+import (
+	"testing"
+
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+	eth2Types "github.com/prysmaticlabs/prysm/proto/eth/v1alpha1"
+)
+
+func TestCompareShardingInfo(t *testing.T) {
+	tests := []struct {
+		name           string
+		modify         func(header *eth1Types.Header, shard *eth2Types.PandoraShard)
+		expectedReason types.MismatchReason
+	}{
+		{
+			name:           "matched sharding info",
+			modify:         func(header *eth1Types.Header, shard *eth2Types.PandoraShard) {},
+			expectedReason: types.NoMismatch,
+		},
+		{
+			name:           "block number mismatched",
+			modify:         func(header *eth1Types.Header, shard *eth2Types.PandoraShard) { shard.BlockNumber++ },
+			expectedReason: types.BlockNumberMismatch,
+		},
+		{
+			name:           "header hash mismatched",
+			modify:         func(header *eth1Types.Header, shard *eth2Types.PandoraShard) { shard.Hash = make([]byte, 32) },
+			expectedReason: types.HeaderHashMismatch,
+		},
+		{
+			name:           "parent hash mismatched",
+			modify:         func(header *eth1Types.Header, shard *eth2Types.PandoraShard) { shard.ParentHash = make([]byte, 32) },
+			expectedReason: types.ParentHashMismatch,
+		},
+		{
+			name:           "state root mismatched",
+			modify:         func(header *eth1Types.Header, shard *eth2Types.PandoraShard) { shard.StateRoot = make([]byte, 32) },
+			expectedReason: types.StateRootMismatch,
+		},
+		{
+			name:           "tx hash mismatched",
+			modify:         func(header *eth1Types.Header, shard *eth2Types.PandoraShard) { shard.TxHash = make([]byte, 32) },
+			expectedReason: types.TxHashMismatch,
+		},
+		{
+			name:           "receipt hash mismatched",
+			modify:         func(header *eth1Types.Header, shard *eth2Types.PandoraShard) { shard.ReceiptHash = make([]byte, 32) },
+			expectedReason: types.ReceiptHashMismatch,
+		},
+		{
+			name:           "signature mismatched",
+			modify:         func(header *eth1Types.Header, shard *eth2Types.PandoraShard) { shard.Signature = make([]byte, 96) },
+			expectedReason: types.SignatureMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			header := testutil.NewEth1Header(1)
+			shard := testutil.NewPandoraShard(header)
+			tt.modify(header, shard)
+			assert.Equal(t, tt.expectedReason, CompareShardingInfo(header, shard))
+		})
+	}
+}
+
+func TestCompareExtraData(t *testing.T) {
+	consensusInfo := testutil.NewMinimalConsensusInfo(1)
+	tests := []struct {
+		name           string
+		slot           uint64
+		extraData      *types.ExtraData
+		expectedReason types.MismatchReason
+	}{
+		{
+			name:           "consistent extra data",
+			slot:           33,
+			extraData:      &types.ExtraData{Slot: 33, Epoch: 1, ProposerIndex: 1},
+			expectedReason: types.NoMismatch,
+		},
+		{
+			name:           "slot is not the matched slot",
+			slot:           33,
+			extraData:      &types.ExtraData{Slot: 34, Epoch: 1, ProposerIndex: 1},
+			expectedReason: types.SlotMismatch,
+		},
+		{
+			name:           "epoch is not derived from slot",
+			slot:           33,
+			extraData:      &types.ExtraData{Slot: 33, Epoch: 2, ProposerIndex: 1},
+			expectedReason: types.EpochMismatch,
+		},
+		{
+			name:           "consensus info belongs to another epoch",
+			slot:           65,
+			extraData:      &types.ExtraData{Slot: 65, Epoch: 2, ProposerIndex: 1},
+			expectedReason: types.EpochMismatch,
+		},
+		{
+			name:           "proposer is not assigned to the slot",
+			slot:           33,
+			extraData:      &types.ExtraData{Slot: 33, Epoch: 1, ProposerIndex: 786},
+			expectedReason: types.ProposerIndexMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedReason, CompareExtraData(tt.slot, tt.extraData, consensusInfo))
+		})
+	}
+}
+
+func TestCompareTimestamp(t *testing.T) {
+	consensusInfo := testutil.NewMinimalConsensusInfo(1)
+	slotStartTime := consensusInfo.SlotStartTime(33)
+	slotEndTime := consensusInfo.SlotEndTime(33)
+	tests := []struct {
+		name           string
+		timestamp      uint64
+		tolerance      uint64
+		expectedReason types.MismatchReason
+	}{
+		{
+			name:           "timestamp at slot start",
+			timestamp:      slotStartTime,
+			expectedReason: types.NoMismatch,
+		},
+		{
+			name:           "timestamp at the end of slot",
+			timestamp:      slotEndTime - 1,
+			expectedReason: types.NoMismatch,
+		},
+		{
+			name:           "timestamp in next slot",
+			timestamp:      slotEndTime,
+			expectedReason: types.TimestampMismatch,
+		},
+		{
+			name:           "timestamp before slot start",
+			timestamp:      slotStartTime - 1,
+			expectedReason: types.TimestampMismatch,
+		},
+		{
+			name:           "timestamp before slot start within tolerance",
+			timestamp:      slotStartTime - 2,
+			tolerance:      2,
+			expectedReason: types.NoMismatch,
+		},
+		{
+			name:           "timestamp after slot end within tolerance",
+			timestamp:      slotEndTime + 1,
+			tolerance:      2,
+			expectedReason: types.NoMismatch,
+		},
+		{
+			name:           "timestamp after slot end beyond tolerance",
+			timestamp:      slotEndTime + 2,
+			tolerance:      2,
+			expectedReason: types.TimestampMismatch,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expectedReason, CompareTimestamp(33, tt.timestamp, consensusInfo, tt.tolerance))
+		})
+	}
+}
@@ -0,0 +1,141 @@
+package consensus
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+	"github.com/pkg/errors"
+	eth2Types "github.com/prysmaticlabs/prysm/proto/eth/v1alpha1"
+	"github.com/prysmaticlabs/prysm/shared/bls"
+)
+
+var (
+	errConsensusInfoNotFound = errors.New("consensus info not found for the epoch of the slot")
+	errProposerNotFound      = errors.New("proposer not found in the validator list of the epoch")
+	errInvalidSignature      = errors.New("invalid bls signature of slot proposer")
+)
+
+// consensusInfoBySlot returns the stored consensus info of the slot's epoch
+func (s *Service) consensusInfoBySlot(slot uint64) (*types.MinimalEpochConsensusInfo, error) {
+	epoch := slot / types.SlotsPerEpoch
+	consensusInfo, err := s.consensusInfoDB.ConsensusInfo(s.ctx, epoch)
+	if err != nil {
+		return nil, err
+	}
+	if consensusInfo == nil {
+		return nil, errors.Wrap(errConsensusInfoNotFound, fmt.Sprintf("epoch: %d", epoch))
+	}
+	return consensusInfo, nil
+}
+
+// CompareSealHash checks that the seal hash of the pandora shard is the seal hash of the pandora header, so that
+// the verified signature covers the header which vanguard has received from pandora
+func CompareSealHash(header *eth1Types.Header, shardInfo *eth2Types.PandoraShard) types.MismatchReason {
+	extraDataWithSig := new(types.PanExtraDataWithBLSSig)
+	if err := rlp.DecodeBytes(header.Extra, extraDataWithSig); err != nil {
+		log.WithError(err).Error("Could not decode pandora header extra data")
+		return types.ExtraDataDecodeError
+	}
+	sealHash, err := types.SealHash(header, &extraDataWithSig.ExtraData)
+	if err != nil {
+		log.WithError(err).Error("Could not compute seal hash of pandora header")
+		return types.ExtraDataDecodeError
+	}
+	if sealHash != common.BytesToHash(shardInfo.GetSealHash()) {
+		log.WithField("pandora seal hash", sealHash).
+			WithField("vanguard seal hash", hexutil.Encode(shardInfo.GetSealHash())).
+			Error("seal hash mismatched")
+		return types.SealHashMismatch
+	}
+	return types.NoMismatch
+}
+
+// verifyPandoraSignature verifies the BLS signature of the pandora header against the stored consensus info of
+// the slot's epoch
+func (s *Service) verifyPandoraSignature(slot uint64, header *eth1Types.Header) error {
+	consensusInfo, err := s.consensusInfoBySlot(slot)
+	if err != nil {
+		return err
+	}
+	return VerifyPandoraSignature(slot, header, consensusInfo)
+}
+
+// verifyShardInfoSignature verifies the BLS signature of the pandora shard of a vanguard block against the stored
+// consensus info of the slot's epoch
+func (s *Service) verifyShardInfoSignature(slot uint64, shardInfo *eth2Types.PandoraShard) error {
+	consensusInfo, err := s.consensusInfoBySlot(slot)
+	if err != nil {
+		return err
+	}
+	return VerifyShardInfoSignature(slot, shardInfo, consensusInfo)
+}
+
+// VerifyPandoraSignature verifies the BLS signature of the pandora header over its seal hash. The public key of
+// the slot proposer is taken from the validator list of the slot's epoch.
+func VerifyPandoraSignature(
+	slot uint64,
+	header *eth1Types.Header,
+	consensusInfo *types.MinimalEpochConsensusInfo,
+) error {
+	extraDataWithSig := new(types.PanExtraDataWithBLSSig)
+	if err := rlp.DecodeBytes(header.Extra, extraDataWithSig); err != nil {
+		return errors.Wrap(err, "could not decode extra data")
+	}
+	sealHash, err := types.SealHash(header, &extraDataWithSig.ExtraData)
+	if err != nil {
+		return errors.Wrap(err, "could not compute seal hash")
+	}
+	return verifyProposerSignature(slot, consensusInfo, extraDataWithSig.BlsSignatureBytes.Bytes(), sealHash[:])
+}
+
+// VerifyShardInfoSignature verifies the BLS signature which the pandora shard of a vanguard block carries over its
+// seal hash. The public key of the slot proposer is taken from the validator list of the slot's epoch.
+func VerifyShardInfoSignature(
+	slot uint64,
+	shardInfo *eth2Types.PandoraShard,
+	consensusInfo *types.MinimalEpochConsensusInfo,
+) error {
+	return verifyProposerSignature(slot, consensusInfo, shardInfo.GetSignature(), shardInfo.GetSealHash())
+}
+
+// slotProposer returns the index and the public key of the slot proposer in the validator list of the slot's epoch
+func slotProposer(slot uint64, consensusInfo *types.MinimalEpochConsensusInfo) (uint64, string, error) {
+	proposerIndex := slot % types.SlotsPerEpoch
+	if proposerIndex >= uint64(len(consensusInfo.ValidatorList)) {
+		return 0, "", errors.Wrap(errProposerNotFound, fmt.Sprintf("slot: %d", slot))
+	}
+	return proposerIndex, consensusInfo.ValidatorList[proposerIndex], nil
+}
+
+// verifyProposerSignature verifies that the signature over the message belongs to the slot proposer
+func verifyProposerSignature(
+	slot uint64,
+	consensusInfo *types.MinimalEpochConsensusInfo,
+	signatureBytes []byte,
+	message []byte,
+) error {
+	_, proposerPubKey, err := slotProposer(slot, consensusInfo)
+	if err != nil {
+		return err
+	}
+	pubKeyBytes, err := hexutil.Decode(proposerPubKey)
+	if err != nil {
+		return errors.Wrap(err, "could not decode proposer public key")
+	}
+	pubKey, err := bls.PublicKeyFromBytes(pubKeyBytes)
+	if err != nil {
+		return errors.Wrap(err, "could not convert proposer public key")
+	}
+	signature, err := bls.SignatureFromBytes(signatureBytes)
+	if err != nil {
+		return errors.Wrap(err, "could not convert signature")
+	}
+	if !signature.Verify(pubKey, message) {
+		return errInvalidSignature
+	}
+	return nil
+}
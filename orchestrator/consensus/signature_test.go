@@ -0,0 +1,84 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestService_VerifyPandoraSignature(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+
+	header := testutil.NewEth1Header(1)
+	require.NoError(t, svc.verifyPandoraSignature(1, header))
+
+	// header is signed by the proposer of another slot
+	extraDataWithSig := new(types.PanExtraDataWithBLSSig)
+	require.NoError(t, rlp.DecodeBytes(header.Extra, extraDataWithSig))
+	testutil.SignEth1Header(header, extraDataWithSig.ExtraData, testutil.ValidatorKeys[2])
+	require.ErrorContains(t, errInvalidSignature.Error(), svc.verifyPandoraSignature(1, header))
+
+	// consensus info of epoch 1 is not stored yet
+	header = testutil.NewEth1Header(40)
+	require.ErrorContains(t, errConsensusInfoNotFound.Error(), svc.verifyPandoraSignature(40, header))
+}
+
+func TestService_VerifyShardInfoSignature(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+
+	header := testutil.NewEth1Header(1)
+	require.NoError(t, svc.verifyShardInfoSignature(1, testutil.NewPandoraShard(header)))
+
+	// shard is signed by the proposer of another slot
+	testutil.SignEth1Header(header, types.ExtraData{Slot: 1, ProposerIndex: 1}, testutil.ValidatorKeys[2])
+	require.ErrorContains(t, errInvalidSignature.Error(), svc.verifyShardInfoSignature(1, testutil.NewPandoraShard(header)))
+}
+
+func TestService_InvalidSignature(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+
+	headerInfo := &types.PandoraHeaderInfo{Slot: 1, Header: testutil.NewEth1Header(1)}
+	extraDataWithSig := new(types.PanExtraDataWithBLSSig)
+	require.NoError(t, rlp.DecodeBytes(headerInfo.Header.Extra, extraDataWithSig))
+	testutil.SignEth1Header(headerInfo.Header, extraDataWithSig.ExtraData, testutil.ValidatorKeys[2])
+	vanShardInfo := testutil.NewVanguardShardInfo(1, headerInfo.Header)
+
+	require.NoError(t, svc.processPandoraHeader(headerInfo))
+	require.NoError(t, svc.processVanguardShardInfo(vanShardInfo))
+
+	slotInfo, err := svc.invalidSlotInfoDB.InvalidSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, headerInfo.Header.Hash(), slotInfo.PandoraHeaderHash)
+	assert.Equal(t, types.InvalidSignature, slotInfo.Reason)
+	verifiedSlotInfo, err := svc.verifiedSlotInfoDB.VerifiedSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), verifiedSlotInfo)
+}
+
+func TestCompareSealHash(t *testing.T) {
+	header := testutil.NewEth1Header(1)
+	shardInfo := testutil.NewPandoraShard(header)
+	assert.Equal(t, types.NoMismatch, CompareSealHash(header, shardInfo))
+
+	// vanguard shipped the seal hash of another header
+	shardInfo.SealHash = testutil.NewPandoraShard(testutil.NewEth1Header(2)).SealHash
+	assert.Equal(t, types.SealHashMismatch, CompareSealHash(header, shardInfo))
+
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	vanShardInfo := testutil.NewVanguardShardInfo(1, header)
+	vanShardInfo.ShardInfo = shardInfo
+	require.NoError(t, svc.processPandoraHeader(&types.PandoraHeaderInfo{Slot: 1, Header: header}))
+	require.NoError(t, svc.processVanguardShardInfo(vanShardInfo))
+	slotInfo, err := svc.invalidSlotInfoDB.InvalidSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, types.SealHashMismatch, slotInfo.Reason)
+}
@@ -2,6 +2,7 @@ package consensus
 
 import (
 	"context"
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/event"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/cache"
 	testDB "github.com/lukso-network/lukso-orchestrator/orchestrator/db/testing"
@@ -11,9 +12,11 @@ import (
 )
 
 type mockFeedService struct {
-	headerInfoFeed event.Feed
-	shardInfoFeed  event.Feed
-	scope          event.SubscriptionScope
+	headerInfoFeed          event.Feed
+	shardInfoFeed           event.Feed
+	finalizedCheckpointFeed event.Feed
+	consensusInfoFeed       event.Feed
+	scope                   event.SubscriptionScope
 }
 
 func (mc *mockFeedService) SubscribeHeaderInfoEvent(ch chan<- *types.PandoraHeaderInfo) event.Subscription {
@@ -24,29 +27,54 @@ func (mc *mockFeedService) SubscribeShardInfoEvent(ch chan<- *types.VanguardShar
 	return mc.scope.Track(mc.shardInfoFeed.Subscribe(ch))
 }
 
-func setup(ctx context.Context, t *testing.T) (*Service, *mockFeedService) {
+func (mc *mockFeedService) SubscribeFinalizedCheckpointEvent(ch chan<- *types.FinalizedCheckpoint) event.Subscription {
+	return mc.scope.Track(mc.finalizedCheckpointFeed.Subscribe(ch))
+}
+
+func (mc *mockFeedService) SubscribeMinConsensusInfoEvent(ch chan<- *types.MinimalEpochConsensusInfo) event.Subscription {
+	return mc.scope.Track(mc.consensusInfoFeed.Subscribe(ch))
+}
+
+func setup(ctx context.Context, t testing.TB) (*Service, *mockFeedService) {
 	testDB := testDB.SetupDB(t)
 	mfs := new(mockFeedService)
+	if err := testDB.SaveConsensusInfo(ctx, testutil.NewMinimalConsensusInfo(0)); err != nil {
+		t.Fatal(err)
+	}
 
 	cfg := &Config{
+		ConsensusInfoDB:              testDB,
 		VerifiedSlotInfoDB:           testDB,
 		InvalidSlotInfoDB:            testDB,
+		SkippedSlotInfoDB:            testDB,
+		TimedOutSlotInfoDB:           testDB,
+		EquivocationDB:               testDB,
+		VerdictDB:                    testDB,
+		VerifiedPayloadDB:            testDB,
 		VanguardPendingShardingCache: cache.NewVanShardInfoCache(1024),
 		PandoraPendingHeaderCache:    cache.NewPanHeaderCache(),
 		VanguardShardFeed:            mfs,
 		PandoraHeaderFeed:            mfs,
+		FinalizedCheckpointFeed:      mfs,
+		ConsensusInfoFeed:            mfs,
 	}
 
-	return New(ctx, cfg), mfs
+	svc, err := New(ctx, cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return svc, mfs
 }
 
 func getHeaderInfosAndShardInfos(fromSlot uint64, num uint64) ([]*types.PandoraHeaderInfo, []*types.VanguardShardInfo) {
 	headerInfos := make([]*types.PandoraHeaderInfo, 0)
 	vanShardInfos := make([]*types.VanguardShardInfo, 0)
 
+	parentHash := eth1Types.EmptyRootHash
 	for i := fromSlot; i < num; i++ {
 		headerInfo := new(types.PandoraHeaderInfo)
-		headerInfo.Header = testutil.NewEth1Header(i)
+		headerInfo.Header = testutil.NewEth1HeaderWithParent(i, parentHash)
+		parentHash = headerInfo.Header.Hash()
 		headerInfo.Slot = i
 		headerInfos = append(headerInfos, headerInfo)
 
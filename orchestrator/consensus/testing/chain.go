@@ -0,0 +1,70 @@
+package testing
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// Chain holds linked pandora headers of shard 0 and the vanguard shard infos which propose them, keyed by slot
+type Chain struct {
+	headerInfos map[uint64]*types.PandoraHeaderInfo
+	shardInfos  map[uint64]*types.VanguardShardInfo
+}
+
+// NewChain builds a chain of the given slots on top of the empty root
+func NewChain(slots ...uint64) *Chain {
+	return NewBranch(eth1Types.EmptyRootHash, 0, slots...)
+}
+
+// NewBranch builds a chain of the given slots on top of the parent header hash. Headers of different branches have
+// different hashes for the same slot, so a branch with the parent of an existing chain is a fork of it.
+func NewBranch(parentHash common.Hash, branch uint64, slots ...uint64) *Chain {
+	chain := &Chain{
+		headerInfos: make(map[uint64]*types.PandoraHeaderInfo),
+		shardInfos:  make(map[uint64]*types.VanguardShardInfo),
+	}
+	for _, slot := range slots {
+		header := testutil.NewEth1HeaderWithParent(slot, parentHash)
+		if branch > 0 {
+			header.Coinbase = common.BigToAddress(new(big.Int).SetUint64(branch))
+			testutil.SignEth1Header(header, types.ExtraData{
+				Slot:          slot,
+				Epoch:         slot / types.SlotsPerEpoch,
+				ProposerIndex: slot % types.SlotsPerEpoch,
+			}, testutil.ValidatorKeys[slot%types.SlotsPerEpoch])
+		}
+		parentHash = header.Hash()
+
+		shardInfo := testutil.NewVanguardShardInfo(slot, header)
+		// every vanguard block has its own hash
+		shardInfo.BlockHash = crypto.Keccak256(header.Hash().Bytes())
+		chain.headerInfos[slot] = &types.PandoraHeaderInfo{Slot: slot, Header: header}
+		chain.shardInfos[slot] = shardInfo
+	}
+	return chain
+}
+
+// Header returns the pandora header info of the slot
+func (c *Chain) Header(slot uint64) *types.PandoraHeaderInfo {
+	return c.headerInfos[slot]
+}
+
+// ShardInfo returns the vanguard shard info of the slot
+func (c *Chain) ShardInfo(slot uint64) *types.VanguardShardInfo {
+	return c.shardInfos[slot]
+}
+
+// HeaderHash returns the hash of the pandora header of the slot
+func (c *Chain) HeaderHash(slot uint64) common.Hash {
+	return c.headerInfos[slot].Header.Hash()
+}
+
+// BlockHash returns the hash of the vanguard block of the slot
+func (c *Chain) BlockHash(slot uint64) common.Hash {
+	return common.BytesToHash(c.shardInfos[slot].BlockHash)
+}
@@ -0,0 +1,183 @@
+// Package testing drives the consensus service synchronously for deterministic tests. Events are processed one
+// by one on the calling goroutine, time only moves when the test advances the clock and the results are read back
+// from a temporary database.
+package testing
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/cache"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/consensus"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
+	testDB "github.com/lukso-network/lukso-orchestrator/orchestrator/db/testing"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// number of statuses which may be emitted by a single event
+const statusBufferSize = 1 << 10
+
+// ManualClock is a clock which only moves when it is advanced
+type ManualClock struct {
+	now  time.Time
+	lock sync.Mutex
+}
+
+// NewManualClock returns a clock which is stopped at the given time
+func NewManualClock(now time.Time) *ManualClock {
+	return &ManualClock{now: now}
+}
+
+func (c *ManualClock) Now() time.Time {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	return c.now
+}
+
+// Advance moves the clock forward
+func (c *ManualClock) Advance(d time.Duration) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+	c.now = c.now.Add(d)
+}
+
+// Feeds are the event feeds of the consensus service. They are only used when the service is started.
+type Feeds struct {
+	HeaderInfoFeed          event.Feed
+	ShardInfoFeed           event.Feed
+	FinalizedCheckpointFeed event.Feed
+	ConsensusInfoFeed       event.Feed
+	scope                   event.SubscriptionScope
+}
+
+func (f *Feeds) SubscribeHeaderInfoEvent(ch chan<- *types.PandoraHeaderInfo) event.Subscription {
+	return f.scope.Track(f.HeaderInfoFeed.Subscribe(ch))
+}
+
+func (f *Feeds) SubscribeShardInfoEvent(ch chan<- *types.VanguardShardInfo) event.Subscription {
+	return f.scope.Track(f.ShardInfoFeed.Subscribe(ch))
+}
+
+func (f *Feeds) SubscribeFinalizedCheckpointEvent(ch chan<- *types.FinalizedCheckpoint) event.Subscription {
+	return f.scope.Track(f.FinalizedCheckpointFeed.Subscribe(ch))
+}
+
+func (f *Feeds) SubscribeMinConsensusInfoEvent(ch chan<- *types.MinimalEpochConsensusInfo) event.Subscription {
+	return f.scope.Track(f.ConsensusInfoFeed.Subscribe(ch))
+}
+
+// Harness holds a consensus service with its database, clock and feeds
+type Harness struct {
+	Service *consensus.Service
+	DB      db.Database
+	Clock   *ManualClock
+	Feeds   *Feeds
+
+	statusCh chan *types.SlotInfoWithStatus
+}
+
+// New creates the harness with the consensus info of the first two epochs. The clock starts at the start time of
+// slot 0. Options may change the service config before the service is created.
+func New(t testing.TB, options ...func(cfg *consensus.Config)) *Harness {
+	database := testDB.SetupDB(t)
+	for epoch := uint64(0); epoch < 2; epoch++ {
+		if err := database.SaveConsensusInfo(context.Background(), testutil.NewMinimalConsensusInfo(epoch)); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	clock := NewManualClock(time.Unix(int64(testutil.NewMinimalConsensusInfo(0).SlotStartTime(0)), 0))
+	feeds := new(Feeds)
+	cfg := &consensus.Config{
+		ConsensusInfoDB:              database,
+		VerifiedSlotInfoDB:           database,
+		InvalidSlotInfoDB:            database,
+		SkippedSlotInfoDB:            database,
+		TimedOutSlotInfoDB:           database,
+		EquivocationDB:               database,
+		VerdictDB:                    database,
+		VerifiedPayloadDB:            database,
+		VanguardPendingShardingCache: cache.NewVanShardInfoCache(1 << 10),
+		PandoraPendingHeaderCache:    cache.NewPanHeaderCache(),
+		VanguardShardFeed:            feeds,
+		PandoraHeaderFeed:            feeds,
+		FinalizedCheckpointFeed:      feeds,
+		ConsensusInfoFeed:            feeds,
+		Clock:                        clock,
+	}
+	for _, option := range options {
+		option(cfg)
+	}
+
+	service, err := consensus.New(context.Background(), cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	h := &Harness{
+		Service:  service,
+		DB:       database,
+		Clock:    clock,
+		Feeds:    feeds,
+		statusCh: make(chan *types.SlotInfoWithStatus, statusBufferSize),
+	}
+	sub := h.Service.SubscribeVerifiedSlotInfoEvent(h.statusCh)
+	t.Cleanup(func() {
+		sub.Unsubscribe()
+		feeds.scope.Close()
+		if err := h.Service.Stop(); err != nil {
+			t.Fatal(err)
+		}
+	})
+	return h
+}
+
+// Step processes the event and returns the statuses which are sent to the subscribers while processing it
+func (h *Harness) Step(ev interface{}) ([]*types.SlotInfoWithStatus, error) {
+	err := h.Service.Step(ev)
+	return h.emitted(), err
+}
+
+// Advance moves the clock forward, expires the pending slots and returns the statuses of the timed out slots
+func (h *Harness) Advance(d time.Duration) ([]*types.SlotInfoWithStatus, error) {
+	h.Clock.Advance(d)
+	err := h.Service.ExpirePendingSlots()
+	return h.emitted(), err
+}
+
+// emitted drains the statuses which are sent so far. Feeds deliver synchronously, so every status of a processed
+// event is already buffered.
+func (h *Harness) emitted() []*types.SlotInfoWithStatus {
+	statuses := make([]*types.SlotInfoWithStatus, 0)
+	for {
+		select {
+		case status := <-h.statusCh:
+			statuses = append(statuses, status)
+		default:
+			return statuses
+		}
+	}
+}
+
+// SlotStatus returns the status of the slot of the shard which is stored in the database
+func (h *Harness) SlotStatus(slot uint64, shardIndex uint64) types.Status {
+	if slotInfo, _ := h.DB.VerifiedSlotInfo(slot, shardIndex); slotInfo != nil {
+		if slot <= h.DB.LatestFinalizedSlot(shardIndex) {
+			return types.Finalized
+		}
+		return types.Verified
+	}
+	if slotInfo, _ := h.DB.InvalidSlotInfo(slot, shardIndex); slotInfo != nil {
+		return types.Invalid
+	}
+	if slotInfo, _ := h.DB.SkippedSlotInfo(slot, shardIndex); slotInfo != nil {
+		return types.Skipped
+	}
+	if slotInfo, _ := h.DB.TimedOutSlotInfo(slot, shardIndex); slotInfo != nil {
+		return types.TimedOut
+	}
+	return types.Pending
+}
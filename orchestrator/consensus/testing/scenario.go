@@ -0,0 +1,73 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/consensus"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// Event is a step of a scenario. Exactly one of its fields is set.
+type Event struct {
+	Header     *types.PandoraHeaderInfo
+	ShardInfo  *types.VanguardShardInfo
+	Checkpoint *types.FinalizedCheckpoint
+	// Advance moves the clock forward and expires the pending slots
+	Advance time.Duration
+}
+
+// Scenario is a sequence of events with the expected outcome
+type Scenario struct {
+	Name   string
+	Events []Event
+	// Statuses are the expected statuses of the slots of shard 0 after every event is processed
+	Statuses map[uint64]types.Status
+	// Emitted are the statuses which are expected to be sent to the subscribers in order. It is not checked when nil.
+	Emitted []types.Status
+	// Options change the service config of the scenario
+	Options []func(cfg *consensus.Config)
+}
+
+// Run processes the events of the scenario on a new harness and checks the outcome
+func (sc *Scenario) Run(t *testing.T) {
+	h := New(t, sc.Options...)
+	emitted := make([]types.Status, 0)
+	for i, ev := range sc.Events {
+		var (
+			statuses []*types.SlotInfoWithStatus
+			err      error
+		)
+		switch {
+		case ev.Header != nil:
+			statuses, err = h.Step(ev.Header)
+		case ev.ShardInfo != nil:
+			statuses, err = h.Step(ev.ShardInfo)
+		case ev.Checkpoint != nil:
+			statuses, err = h.Step(ev.Checkpoint)
+		default:
+			statuses, err = h.Advance(ev.Advance)
+		}
+		require.NoError(t, err, "event %d", i)
+		for _, status := range statuses {
+			emitted = append(emitted, status.Status)
+		}
+	}
+
+	for slot, status := range sc.Statuses {
+		assert.Equal(t, status, h.SlotStatus(slot, 0), "slot %d", slot)
+	}
+	if sc.Emitted != nil {
+		assert.DeepEqual(t, sc.Emitted, emitted)
+	}
+}
+
+// RunScenarios runs every scenario as a sub test
+func RunScenarios(t *testing.T, scenarios []Scenario) {
+	for _, sc := range scenarios {
+		sc := sc
+		t.Run(sc.Name, sc.Run)
+	}
+}
@@ -0,0 +1,98 @@
+package testing
+
+import (
+	"testing"
+	"time"
+
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/consensus"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestScenarios(t *testing.T) {
+	main := NewChain(1, 2, 3)
+	fork := NewBranch(main.HeaderHash(1), 1, 2, 3)
+	other := NewBranch(eth1Types.EmptyRootHash, 2, 1)
+
+	RunScenarios(t, []Scenario{
+		{
+			Name: "in order arrival",
+			Events: []Event{
+				{Header: main.Header(1)}, {ShardInfo: main.ShardInfo(1)},
+				{Header: main.Header(2)}, {ShardInfo: main.ShardInfo(2)},
+				{Header: main.Header(3)}, {ShardInfo: main.ShardInfo(3)},
+			},
+			Statuses: map[uint64]types.Status{1: types.Verified, 2: types.Verified, 3: types.Verified},
+			Emitted:  []types.Status{types.Verified, types.Verified, types.Verified},
+		},
+		{
+			Name: "child arrives before its parent",
+			Events: []Event{
+				{ShardInfo: main.ShardInfo(1)}, {Header: main.Header(1)},
+				{Header: main.Header(3)}, {ShardInfo: main.ShardInfo(3)},
+				{ShardInfo: main.ShardInfo(2)}, {Header: main.Header(2)},
+			},
+			Statuses: map[uint64]types.Status{1: types.Verified, 2: types.Verified, 3: types.Verified},
+			Emitted:  []types.Status{types.Verified, types.Verified, types.Verified},
+		},
+		{
+			Name: "duplicates",
+			Events: []Event{
+				{Header: main.Header(1)}, {Header: main.Header(1)},
+				{ShardInfo: main.ShardInfo(1)}, {ShardInfo: main.ShardInfo(1)},
+				{Header: main.Header(1)},
+			},
+			Statuses: map[uint64]types.Status{1: types.Verified},
+			Emitted:  []types.Status{types.Verified, types.Verified, types.Verified},
+		},
+		{
+			Name: "mismatched sharding info",
+			Events: []Event{
+				{Header: main.Header(1)}, {ShardInfo: other.ShardInfo(1)},
+			},
+			Statuses: map[uint64]types.Status{1: types.Invalid},
+			Emitted:  []types.Status{types.Invalid},
+		},
+		{
+			Name: "reorg",
+			Events: []Event{
+				{Header: main.Header(1)}, {ShardInfo: main.ShardInfo(1)},
+				{Header: main.Header(2)}, {ShardInfo: main.ShardInfo(2)},
+				{Header: main.Header(3)}, {ShardInfo: main.ShardInfo(3)},
+				{Header: fork.Header(2)}, {ShardInfo: fork.ShardInfo(2)},
+			},
+			Statuses: map[uint64]types.Status{1: types.Verified, 2: types.Verified, 3: types.Pending},
+			Emitted: []types.Status{
+				types.Verified, types.Verified, types.Verified, types.Reorged, types.Reorged, types.Verified,
+			},
+		},
+		{
+			Name: "pending slot times out",
+			Events: []Event{
+				{Header: main.Header(1)},
+				// deadline is two slots after the start of slot 1
+				{Advance: 17 * time.Second}, {Advance: time.Second},
+				{ShardInfo: main.ShardInfo(1)},
+			},
+			Statuses: map[uint64]types.Status{1: types.TimedOut},
+			Emitted:  []types.Status{types.TimedOut, types.TimedOut},
+			Options: []func(cfg *consensus.Config){func(cfg *consensus.Config) {
+				cfg.PendingSlotTimeout = 2
+			}},
+		},
+		{
+			Name: "finalized checkpoint",
+			Events: []Event{
+				{Header: main.Header(1)}, {ShardInfo: main.ShardInfo(1)},
+				{Header: main.Header(2)}, {ShardInfo: main.ShardInfo(2)},
+				{Header: main.Header(3)}, {ShardInfo: main.ShardInfo(3)},
+				{Checkpoint: &types.FinalizedCheckpoint{Slot: 2, BlockRoot: main.BlockHash(2)}},
+				{Header: fork.Header(2)},
+			},
+			Statuses: map[uint64]types.Status{1: types.Finalized, 2: types.Finalized, 3: types.Verified},
+			Emitted: []types.Status{
+				types.Verified, types.Verified, types.Verified, types.Finalized, types.Finalized, types.Invalid,
+			},
+		},
+	})
+}
@@ -0,0 +1,170 @@
+package consensus
+
+import (
+	"fmt"
+	"sync"
+
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+	"github.com/pkg/errors"
+	eth2Types "github.com/prysmaticlabs/prysm/proto/eth/v1alpha1"
+)
+
+const (
+	ShardingInfoRule = "sharding-info"
+	ExtraDataRule    = "extra-data"
+	SignatureRule    = "signature"
+	TimestampRule    = "timestamp"
+)
+
+var errUnknownVerifier = errors.New("unknown verification rule")
+
+// DefaultVerifierRules are the rules which are applied when no rule is selected
+var DefaultVerifierRules = []string{ShardingInfoRule, ExtraDataRule, SignatureRule, TimestampRule}
+
+// VerificationInput holds everything a verification rule may check for one slot of a shard
+type VerificationInput struct {
+	Slot       uint64
+	ShardIndex uint64
+	Header     *eth1Types.Header
+	ShardInfo  *eth2Types.PandoraShard
+	// ConsensusInfo of the slot's epoch. It is nil when the consensus info is not available yet.
+	ConsensusInfo *types.MinimalEpochConsensusInfo
+}
+
+// Verifier is a verification rule of a pandora header and the pandora shard of the vanguard block. It returns the
+// reason of the failure or NoMismatch. ConsensusInfoMissing is not a failure, it keeps the slot pending until the
+// consensus info of its epoch arrives.
+type Verifier interface {
+	// Name identifies the rule on the command line and in the stored verdicts
+	Name() string
+	Verify(input *VerificationInput) types.MismatchReason
+}
+
+// VerifierFunc adapts a function to a Verifier
+type VerifierFunc struct {
+	RuleName string
+	Fn       func(input *VerificationInput) types.MismatchReason
+}
+
+func (vf *VerifierFunc) Name() string {
+	return vf.RuleName
+}
+
+func (vf *VerifierFunc) Verify(input *VerificationInput) types.MismatchReason {
+	return vf.Fn(input)
+}
+
+var (
+	verifiersLock sync.RWMutex
+	verifiers     = make(map[string]Verifier)
+)
+
+func init() {
+	RegisterVerifier(&VerifierFunc{RuleName: ShardingInfoRule, Fn: verifyShardingInfoRule})
+	RegisterVerifier(&VerifierFunc{RuleName: ExtraDataRule, Fn: verifyExtraDataRule})
+	RegisterVerifier(&VerifierFunc{RuleName: SignatureRule, Fn: verifySignatureRule})
+}
+
+// RegisterVerifier makes a rule selectable by its name. Network specific rules are registered from an init function
+// of their own package. A rule with the same name replaces the registered one. The timestamp rule is not registered,
+// it is built for every chain with the slot time tolerance of its service.
+func RegisterVerifier(verifier Verifier) {
+	verifiersLock.Lock()
+	defer verifiersLock.Unlock()
+	verifiers[verifier.Name()] = verifier
+}
+
+// VerifierChain applies the rules in order
+type VerifierChain []Verifier
+
+// NewVerifierChain builds the chain of the named rules. Default rules are used when no rule is given. The timestamp
+// rule accepts header timestamps which are out of their slot window by the given tolerance in seconds.
+func NewVerifierChain(names []string, slotTimeTolerance uint64) (VerifierChain, error) {
+	if len(names) == 0 {
+		names = DefaultVerifierRules
+	}
+	verifiersLock.RLock()
+	defer verifiersLock.RUnlock()
+
+	chain := make(VerifierChain, 0, len(names))
+	for _, name := range names {
+		if name == TimestampRule {
+			chain = append(chain, NewTimestampVerifier(slotTimeTolerance))
+			continue
+		}
+		verifier, ok := verifiers[name]
+		if !ok {
+			return nil, errors.Wrap(errUnknownVerifier, fmt.Sprintf("rule: %s", name))
+		}
+		chain = append(chain, verifier)
+	}
+	return chain, nil
+}
+
+// Verify applies every rule of the chain and returns the verdict of each rule with the reason of the first failed
+// rule or NoMismatch
+func (vc VerifierChain) Verify(input *VerificationInput) ([]*types.RuleVerdict, types.MismatchReason) {
+	verdicts := make([]*types.RuleVerdict, 0, len(vc))
+	reason := types.NoMismatch
+	for _, verifier := range vc {
+		verdict := &types.RuleVerdict{Rule: verifier.Name(), Reason: verifier.Verify(input)}
+		log.WithField("slot", input.Slot).WithField("shardIndex", input.ShardIndex).
+			WithField("rule", verdict.Rule).WithField("passed", verdict.Passed()).
+			WithField("reason", verdict.Reason).Debug("Applied verification rule")
+		if reason == types.NoMismatch {
+			reason = verdict.Reason
+		}
+		verdicts = append(verdicts, verdict)
+	}
+	return verdicts, reason
+}
+
+// verifyShardingInfoRule compares the fields of the pandora header with the pandora shard of the vanguard block
+func verifyShardingInfoRule(input *VerificationInput) types.MismatchReason {
+	return CompareShardingInfo(input.Header, input.ShardInfo)
+}
+
+// verifyExtraDataRule checks slot, epoch and proposer index of the pandora header extra data
+func verifyExtraDataRule(input *VerificationInput) types.MismatchReason {
+	extraDataWithSig := new(types.PanExtraDataWithBLSSig)
+	if err := rlp.DecodeBytes(input.Header.Extra, extraDataWithSig); err != nil {
+		log.WithField("slot", input.Slot).WithError(err).Error("Could not decode pandora header extra data")
+		return types.ExtraDataDecodeError
+	}
+	if input.ConsensusInfo == nil {
+		return types.ConsensusInfoMissing
+	}
+	return CompareExtraData(input.Slot, &extraDataWithSig.ExtraData, input.ConsensusInfo)
+}
+
+// verifySignatureRule checks the seal hash which vanguard shipped and verifies the BLS signature of the pandora
+// header against the slot proposer
+func verifySignatureRule(input *VerificationInput) types.MismatchReason {
+	if reason := CompareSealHash(input.Header, input.ShardInfo); reason != types.NoMismatch {
+		return reason
+	}
+	if input.ConsensusInfo == nil {
+		return types.ConsensusInfoMissing
+	}
+	if err := VerifyPandoraSignature(input.Slot, input.Header, input.ConsensusInfo); err != nil {
+		log.WithField("slot", input.Slot).WithError(err).Error("Failed to verify pandora header signature")
+		return types.InvalidSignature
+	}
+	return types.NoMismatch
+}
+
+// NewTimestampVerifier returns the rule which checks that the pandora header is produced within its slot. The
+// header timestamp may be off the slot window by the given tolerance in seconds.
+func NewTimestampVerifier(tolerance uint64) Verifier {
+	return &VerifierFunc{
+		RuleName: TimestampRule,
+		Fn: func(input *VerificationInput) types.MismatchReason {
+			if input.ConsensusInfo == nil {
+				return types.ConsensusInfoMissing
+			}
+			return CompareTimestamp(input.Slot, input.Header.Time, input.ConsensusInfo, tolerance)
+		},
+	}
+}
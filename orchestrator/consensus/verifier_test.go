@@ -0,0 +1,100 @@
+package consensus
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestNewVerifierChain(t *testing.T) {
+	chain, err := NewVerifierChain(nil, 0)
+	require.NoError(t, err)
+	require.Equal(t, len(DefaultVerifierRules), len(chain))
+	for i, verifier := range chain {
+		assert.Equal(t, DefaultVerifierRules[i], verifier.Name())
+	}
+
+	chain, err = NewVerifierChain([]string{SignatureRule}, 0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(chain))
+	assert.Equal(t, SignatureRule, chain[0].Name())
+
+	_, err = NewVerifierChain([]string{ShardingInfoRule, "unknown"}, 0)
+	require.ErrorContains(t, errUnknownVerifier.Error(), err)
+}
+
+func TestService_CustomVerifier(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+	gasLimitRule := "test-gas-limit"
+	RegisterVerifier(&VerifierFunc{
+		RuleName: gasLimitRule,
+		Fn: func(input *VerificationInput) types.MismatchReason {
+			if input.Header.GasLimit > 1000 {
+				return "GasLimitExceeded"
+			}
+			return types.NoMismatch
+		},
+	})
+	verifiers, err := NewVerifierChain(append(DefaultVerifierRules, gasLimitRule), 0)
+	require.NoError(t, err)
+	svc.verifiers = verifiers
+
+	headerInfos, shardInfos := getHeaderInfosAndShardInfos(1, 2)
+	require.NoError(t, svc.processPandoraHeader(headerInfos[0]))
+	require.NoError(t, svc.processVanguardShardInfo(shardInfos[0]))
+
+	invalidSlotInfo, err := svc.invalidSlotInfoDB.InvalidSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, types.MismatchReason("GasLimitExceeded"), invalidSlotInfo.Reason)
+
+	verdicts, err := svc.verdictDB.Verdicts(1, 0)
+	require.NoError(t, err)
+	assert.DeepEqual(t, []*types.RuleVerdict{
+		{Rule: ShardingInfoRule, Reason: types.NoMismatch},
+		{Rule: ExtraDataRule, Reason: types.NoMismatch},
+		{Rule: SignatureRule, Reason: types.NoMismatch},
+		{Rule: TimestampRule, Reason: types.NoMismatch},
+		{Rule: gasLimitRule, Reason: "GasLimitExceeded"},
+	}, verdicts)
+}
+
+func TestService_TimestampOutOfSlot(t *testing.T) {
+	ctx := context.Background()
+	svc, _ := setup(ctx, t)
+
+	// header is produced two slots later
+	header := testutil.NewEth1Header(1)
+	header.Time = testutil.NewMinimalConsensusInfo(0).SlotStartTime(3)
+	testutil.SignEth1Header(header, types.ExtraData{Slot: 1, ProposerIndex: 1}, testutil.ValidatorKeys[1])
+	require.NoError(t, svc.processPandoraHeader(&types.PandoraHeaderInfo{Slot: 1, Header: header}))
+	require.NoError(t, svc.processVanguardShardInfo(testutil.NewVanguardShardInfo(1, header)))
+
+	invalidSlotInfo, err := svc.invalidSlotInfoDB.InvalidSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, types.TimestampMismatch, invalidSlotInfo.Reason)
+}
+
+func TestNewVerifierChain_SlotTimeTolerance(t *testing.T) {
+	consensusInfo := testutil.NewMinimalConsensusInfo(0)
+	header := testutil.NewEth1Header(1)
+	// header is produced one second before its slot
+	header.Time = consensusInfo.SlotStartTime(1) - 1
+	input := &VerificationInput{Slot: 1, Header: header, ConsensusInfo: consensusInfo}
+
+	// chains of different services keep their own tolerance
+	strictChain, err := NewVerifierChain([]string{TimestampRule}, 0)
+	require.NoError(t, err)
+	tolerantChain, err := NewVerifierChain([]string{TimestampRule}, 2)
+	require.NoError(t, err)
+	_, reason := strictChain.Verify(input)
+	assert.Equal(t, types.TimestampMismatch, reason)
+	_, reason = tolerantChain.Verify(input)
+	assert.Equal(t, types.NoMismatch, reason)
+	_, reason = strictChain.Verify(input)
+	assert.Equal(t, types.TimestampMismatch, reason)
+}
@@ -10,8 +10,38 @@ type ROnlyVerifiedSlotInfoDB = iface.ReadOnlyVerifiedSlotInfoDatabase
 
 type ROnlyInvalidSlotInfoDB = iface.ReadOnlyInvalidSlotInfoDatabase
 
+type ROnlySkippedSlotInfoDB = iface.ReadOnlySkippedSlotInfoDatabase
+
+type ROnlyTimedOutSlotInfoDB = iface.ReadOnlyTimedOutSlotInfoDatabase
+
+type ROnlyEquivocationDB = iface.ReadOnlyEquivocationDatabase
+
+type ROnlyVerdictDB = iface.ReadOnlyVerdictDatabase
+
+type ROnlyVerifiedPayloadDB = iface.ReadOnlyVerifiedPayloadDatabase
+
+type ROnlyHashIndexDB = iface.ReadOnlyHashIndexDatabase
+
 type VerifiedSlotInfoDB = iface.VerifiedSlotDatabase
 
 type InvalidSlotInfoDB = iface.InvalidSlotDatabase
 
+type SkippedSlotInfoDB = iface.SkippedSlotDatabase
+
+type TimedOutSlotInfoDB = iface.TimedOutSlotDatabase
+
+type EquivocationDB = iface.EquivocationDatabase
+
+type VerdictDB = iface.VerdictDatabase
+
+type PendingHeaderDB = iface.PendingHeaderDatabase
+
+type PendingShardInfoDB = iface.PendingShardInfoDatabase
+
+type BackupDB = iface.BackupDatabase
+
 type Database = iface.Database
+
+type VerifiedSlotConflictError = iface.VerifiedSlotConflictError
+
+type PrunedRevertError = iface.PrunedRevertError
@@ -0,0 +1,39 @@
+package iface
+
+import (
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// VerifiedSlotConflictError is returned when a verified slot is committed which does not extend the latest verified
+// slot of its shard. Nothing is stored, so the slot can be committed again once its parent is verified.
+type VerifiedSlotConflictError struct {
+	Slot               uint64
+	ShardIndex         uint64
+	ParentHash         common.Hash
+	LatestVerifiedSlot uint64
+	LatestHeaderHash   common.Hash
+}
+
+func (e *VerifiedSlotConflictError) Error() string {
+	return fmt.Sprintf(
+		"slot %d of shard %d with parent %s does not extend latest verified slot %d with header %s",
+		e.Slot, e.ShardIndex, e.ParentHash.Hex(), e.LatestVerifiedSlot, e.LatestHeaderHash.Hex(),
+	)
+}
+
+// PrunedRevertError is returned when verified slots are reverted and no verified slot of the shard would remain from
+// its first retained slot on. Nothing is reverted, because the verified chain can not be continued from a pruned slot.
+type PrunedRevertError struct {
+	FromSlot          uint64
+	ShardIndex        uint64
+	FirstRetainedSlot uint64
+}
+
+func (e *PrunedRevertError) Error() string {
+	return fmt.Sprintf(
+		"verified slots of shard %d can not be reverted from slot %d, no verified slot remains from first retained slot %d",
+		e.ShardIndex, e.FromSlot, e.FirstRetainedSlot,
+	)
+}
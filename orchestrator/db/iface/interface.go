@@ -3,6 +3,7 @@ package iface
 import (
 	"context"
 	"github.com/ethereum/go-ethereum/common"
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
 	"io"
 )
@@ -23,31 +24,112 @@ type ConsensusInfoAccessDatabase interface {
 	SaveLatestEpoch(ctx context.Context) error
 }
 
+// ReadOnlyVerifiedSlotInfoDatabase gives read access to verified slot infos. Slot infos are kept per pandora shard.
 type ReadOnlyVerifiedSlotInfoDatabase interface {
-	VerifiedSlotInfo(slot uint64) (*types.SlotInfo, error)
-	VerifiedSlotInfos(fromSlot uint64) (map[uint64]*types.SlotInfo, error)
-	LatestSavedVerifiedSlot() uint64
-	InMemoryLatestVerifiedSlot() uint64
-	LatestVerifiedHeaderHash() common.Hash
-	InMemoryLatestVerifiedHeaderHash() common.Hash
+	VerifiedSlotInfo(slot uint64, shardIndex uint64) (*types.SlotInfo, error)
+	VerifiedSlotInfos(fromSlot uint64, shardIndex uint64) (map[uint64]*types.SlotInfo, error)
+	LatestSavedVerifiedSlot(shardIndex uint64) uint64
+	InMemoryLatestVerifiedSlot(shardIndex uint64) uint64
+	LatestVerifiedHeaderHash(shardIndex uint64) common.Hash
+	InMemoryLatestVerifiedHeaderHash(shardIndex uint64) common.Hash
+	LatestFinalizedSlot(shardIndex uint64) uint64
 }
 
 type VerifiedSlotDatabase interface {
 	ReadOnlyVerifiedSlotInfoDatabase
 
-	SaveVerifiedSlotInfo(slot uint64, slotInfo *types.SlotInfo) error
-	SaveLatestVerifiedSlot(ctx context.Context) error
-	SaveLatestVerifiedHeaderHash() error
+	SaveVerifiedSlotInfos(shardIndex uint64, slotInfos map[uint64]*types.SlotInfo) error
+	CommitVerifiedSlots(shardIndex uint64, verifiedSlots []*types.VerifiedSlot) error
+	SaveLatestVerifiedSlot(ctx context.Context, shardIndex uint64) error
+	SaveLatestVerifiedHeaderHash(shardIndex uint64) error
+	SaveLatestFinalizedSlot(slot uint64, shardIndex uint64) error
+	RevertVerifiedSlotInfos(fromSlot uint64, shardIndex uint64) (map[uint64]*types.VerifiedSlot, error)
 }
 
 type ReadOnlyInvalidSlotInfoDatabase interface {
-	InvalidSlotInfo(slots uint64) (*types.SlotInfo, error)
+	InvalidSlotInfo(slot uint64, shardIndex uint64) (*types.InvalidSlotInfo, error)
 }
 
 type InvalidSlotDatabase interface {
 	ReadOnlyInvalidSlotInfoDatabase
 
-	SaveInvalidSlotInfo(slot uint64, slotInfo *types.SlotInfo) error
+	SaveInvalidSlotInfo(slot uint64, shardIndex uint64, slotInfo *types.InvalidSlotInfo) error
+}
+
+type ReadOnlySkippedSlotInfoDatabase interface {
+	SkippedSlotInfo(slot uint64, shardIndex uint64) (*types.SlotInfo, error)
+}
+
+type SkippedSlotDatabase interface {
+	ReadOnlySkippedSlotInfoDatabase
+
+	SaveSkippedSlotInfo(slot uint64, shardIndex uint64, slotInfo *types.SlotInfo) error
+}
+
+type ReadOnlyTimedOutSlotInfoDatabase interface {
+	TimedOutSlotInfo(slot uint64, shardIndex uint64) (*types.SlotInfo, error)
+}
+
+type TimedOutSlotDatabase interface {
+	ReadOnlyTimedOutSlotInfoDatabase
+
+	SaveTimedOutSlotInfo(slot uint64, shardIndex uint64, slotInfo *types.SlotInfo) error
+}
+
+// ReadOnlyEquivocationDatabase gives read access to the evidence of double proposals
+type ReadOnlyEquivocationDatabase interface {
+	Equivocations(fromSlot uint64) ([]*types.Equivocation, error)
+}
+
+type EquivocationDatabase interface {
+	ReadOnlyEquivocationDatabase
+
+	SaveEquivocation(equivocation *types.Equivocation) error
+}
+
+// ReadOnlyVerdictDatabase gives read access to the verdicts of the verification rules of a slot
+type ReadOnlyVerdictDatabase interface {
+	Verdicts(slot uint64, shardIndex uint64) ([]*types.RuleVerdict, error)
+}
+
+type VerdictDatabase interface {
+	ReadOnlyVerdictDatabase
+
+	SaveVerdicts(slot uint64, shardIndex uint64, verdicts []*types.RuleVerdict) error
+}
+
+// ReadOnlyVerifiedPayloadDatabase gives read access to the full pandora headers and vanguard shard infos of the
+// verified slots. Payloads are stored together with their slot infos by CommitVerifiedSlots.
+type ReadOnlyVerifiedPayloadDatabase interface {
+	VerifiedHeader(slot uint64, shardIndex uint64) (*eth1Types.Header, error)
+	VerifiedShardInfo(slot uint64, shardIndex uint64) (*types.VanguardShardInfo, error)
+}
+
+// ReadOnlyHashIndexDatabase finds the slot of a verified or invalid slot info by its pandora header hash or its
+// vanguard block hash
+type ReadOnlyHashIndexDatabase interface {
+	SlotByPandoraHeaderHash(hash common.Hash) (*types.ShardSlot, error)
+	SlotByVanguardBlockHash(hash common.Hash) (*types.ShardSlot, error)
+}
+
+// PendingHeaderDatabase keeps the pandora headers of the pending slots across restarts
+type PendingHeaderDatabase interface {
+	PendingHeaders() ([]*types.PandoraHeaderInfo, error)
+	SavePendingHeader(headerInfo *types.PandoraHeaderInfo) error
+	DeletePendingHeader(slot uint64, shardIndex uint64) error
+}
+
+// PendingShardInfoDatabase keeps the vanguard shard infos of the pending slots across restarts
+type PendingShardInfoDatabase interface {
+	PendingShardInfos() ([]*types.VanguardShardInfo, error)
+	SavePendingShardInfo(shardInfo *types.VanguardShardInfo) error
+	DeletePendingShardInfo(slot uint64, shardIndex uint64) error
+}
+
+// BackupDatabase writes snapshots of the database while it is in use
+type BackupDatabase interface {
+	Backup(ctx context.Context, outputDir string) (string, error)
+	OnDemandBackup(ctx context.Context, outputDir string) (string, error)
 }
 
 // Database interface with full access.
@@ -60,6 +142,24 @@ type Database interface {
 
 	InvalidSlotDatabase
 
+	SkippedSlotDatabase
+
+	TimedOutSlotDatabase
+
+	EquivocationDatabase
+
+	VerdictDatabase
+
+	ReadOnlyVerifiedPayloadDatabase
+
+	ReadOnlyHashIndexDatabase
+
+	PendingHeaderDatabase
+
+	PendingShardInfoDatabase
+
+	BackupDatabase
+
 	DatabasePath() string
 	ClearDB() error
 }
@@ -0,0 +1,295 @@
+package kv
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lukso-network/lukso-orchestrator/shared/fileutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/params"
+	"github.com/pkg/errors"
+)
+
+const (
+	// backupsDirectoryName is the default directory of backups inside the database directory
+	backupsDirectoryName = "backups"
+	backupFilePrefix     = "orchestrator_backup_"
+	backupFileSuffix     = ".db"
+	// backupTimeFormat keeps the backup file names in the order of their creation
+	backupTimeFormat = "20060102T150405.000000000"
+	// onDemandBackupsDirectoryName is the directory of on-demand backups inside the backup directory. Periodic
+	// backups rotate only the files of the backup directory itself, so on-demand backups are never removed.
+	onDemandBackupsDirectoryName = "on-demand"
+)
+
+// Backup writes a consistent snapshot of the database into the output directory while the node keeps running. The
+// snapshot is written by a read transaction, so writers are not blocked. Without output directory the snapshot is
+// written into the backups directory of the database. It returns the path of the snapshot.
+func (s *Store) Backup(ctx context.Context, outputDir string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if outputDir == "" {
+		outputDir = path.Join(s.databasePath, backupsDirectoryName)
+	}
+	if err := ensureDir(outputDir); err != nil {
+		return "", err
+	}
+
+	start := time.Now()
+	backupPath := path.Join(outputDir, backupFilePrefix+start.UTC().Format(backupTimeFormat)+backupFileSuffix)
+	// a partly written snapshot never has the name of a backup
+	tmpPath := backupPath + ".tmp"
+	file, err := os.OpenFile(tmpPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, params.OrchestratorIoConfig().ReadWritePermissions)
+	if err != nil {
+		return "", err
+	}
+	var size int64
+	err = s.db.View(func(tx *bolt.Tx) error {
+		size, err = tx.WriteTo(file)
+		return err
+	})
+	if err == nil {
+		err = file.Sync()
+	}
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(tmpPath, backupPath)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return "", errors.Wrap(err, "could not write database backup")
+	}
+
+	log.WithField("path", backupPath).WithField("size", size).
+		WithField("elapsed", time.Since(start)).Info("Wrote database backup")
+	return backupPath, nil
+}
+
+// OnDemandBackup writes a snapshot like Backup into the on-demand directory of the output directory, so that it is
+// not removed by the rotation of periodic backups. It returns the path of the snapshot.
+func (s *Store) OnDemandBackup(ctx context.Context, outputDir string) (string, error) {
+	if outputDir == "" {
+		outputDir = path.Join(s.databasePath, backupsDirectoryName)
+	}
+	return s.Backup(ctx, path.Join(outputDir, onDemandBackupsDirectoryName))
+}
+
+// Backups returns the paths of the backups in the directory from the oldest to the newest
+func Backups(dirPath string) ([]string, error) {
+	matches, err := filepath.Glob(path.Join(dirPath, backupFilePrefix+"*"+backupFileSuffix))
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(matches)
+	return matches, nil
+}
+
+// VerifySnapshot checks that the file is a consistent database which this store can open. Snapshots of older
+// schema versions are accepted, they are migrated when they are opened.
+func VerifySnapshot(snapshotPath string) error {
+	if !fileutil.FileExists(snapshotPath) {
+		return fmt.Errorf("snapshot %s does not exist", snapshotPath)
+	}
+	boltDB, err := bolt.Open(snapshotPath, params.OrchestratorIoConfig().ReadWritePermissions, &bolt.Options{
+		Timeout:  1 * time.Second,
+		ReadOnly: true,
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not open snapshot")
+	}
+	defer boltDB.Close()
+
+	return boltDB.View(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{consensusInfosBucket, verifiedSlotInfosBucket} {
+			if tx.Bucket(bucket) == nil {
+				return fmt.Errorf("snapshot has no %s bucket", bucket)
+			}
+		}
+		if version := schemaVersion(tx); version > SchemaVersion {
+			return fmt.Errorf("snapshot schema version %d is newer than supported version %d", version, SchemaVersion)
+		}
+		// all errors are read, so that the checking goroutine finishes
+		var checkErr error
+		for err := range tx.Check() {
+			if checkErr == nil {
+				checkErr = errors.Wrap(err, "snapshot is corrupted")
+			}
+		}
+		return checkErr
+	})
+}
+
+// RestoreSnapshot verifies the snapshot and swaps it in as the database of the directory. The node must be stopped.
+// The replaced database is kept next to it with the restore time as suffix. It returns the path of the kept
+// database, which is empty when the directory had no database.
+func RestoreSnapshot(snapshotPath string, dirPath string) (string, error) {
+	if err := VerifySnapshot(snapshotPath); err != nil {
+		return "", err
+	}
+	if err := ensureDir(dirPath); err != nil {
+		return "", err
+	}
+
+	datafile := path.Join(dirPath, DatabaseFileName)
+	hasDB := fileutil.FileExists(datafile)
+	if hasDB {
+		// bolt locks the database file while the node uses it
+		boltDB, err := bolt.Open(datafile, params.OrchestratorIoConfig().ReadWritePermissions, &bolt.Options{
+			Timeout: 1 * time.Second,
+		})
+		if err != nil {
+			if errors.Is(err, bolt.ErrTimeout) {
+				return "", errors.New("cannot obtain database lock, database may be in use by another process")
+			}
+			return "", err
+		}
+		if err := boltDB.Close(); err != nil {
+			return "", err
+		}
+	}
+
+	tmpPath := datafile + ".restore"
+	if err := copyFile(snapshotPath, tmpPath); err != nil {
+		_ = os.Remove(tmpPath)
+		return "", errors.Wrap(err, "could not copy snapshot")
+	}
+	keptPath := ""
+	if hasDB {
+		keptPath = datafile + "." + time.Now().UTC().Format(backupTimeFormat)
+		if err := os.Rename(datafile, keptPath); err != nil {
+			_ = os.Remove(tmpPath)
+			return "", err
+		}
+	}
+	if err := os.Rename(tmpPath, datafile); err != nil {
+		return "", err
+	}
+	return keptPath, nil
+}
+
+// ensureDir creates the directory when it does not exist
+func ensureDir(dirPath string) error {
+	hasDir, err := fileutil.HasDir(dirPath)
+	if err != nil || hasDir {
+		return err
+	}
+	return fileutil.MkdirAll(dirPath)
+}
+
+// copyFile copies the file and syncs the copy to disk
+func copyFile(src string, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, params.OrchestratorIoConfig().ReadWritePermissions)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	if err := out.Sync(); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// PeriodicBackup writes a backup of the database with the configured interval and keeps the latest backups
+type PeriodicBackup struct {
+	ctx       context.Context
+	cancel    context.CancelFunc
+	db        *Store
+	outputDir string
+	interval  time.Duration
+	keep      int
+	wg        sync.WaitGroup
+}
+
+// NewPeriodicBackup creates a periodic backup of the store into the output directory. Older backups are removed
+// when more than keep backups exist, keep 0 keeps all backups.
+func NewPeriodicBackup(ctx context.Context, db *Store, outputDir string, interval time.Duration, keep int) (*PeriodicBackup, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid backup interval %s", interval)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &PeriodicBackup{
+		ctx:       ctx,
+		cancel:    cancel,
+		db:        db,
+		outputDir: outputDir,
+		interval:  interval,
+		keep:      keep,
+	}, nil
+}
+
+// Start writes a backup after every interval
+func (b *PeriodicBackup) Start() {
+	b.wg.Add(1)
+	go b.run()
+}
+
+// Stop waits until a running backup is written
+func (b *PeriodicBackup) Stop() error {
+	b.cancel()
+	b.wg.Wait()
+	return nil
+}
+
+// Status always returns nil, a failed backup is retried with the next interval
+func (b *PeriodicBackup) Status() error {
+	return nil
+}
+
+func (b *PeriodicBackup) run() {
+	defer b.wg.Done()
+	ticker := time.NewTicker(b.interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			if _, err := b.db.Backup(b.ctx, b.outputDir); err != nil {
+				log.WithError(err).Error("Could not back up database")
+				continue
+			}
+			if err := b.removeOldBackups(); err != nil {
+				log.WithError(err).Error("Could not remove old database backups")
+			}
+		case <-b.ctx.Done():
+			return
+		}
+	}
+}
+
+// removeOldBackups removes the oldest backups of the output directory until keep backups are left. On-demand
+// backups are not listed since they are in a directory of their own.
+func (b *PeriodicBackup) removeOldBackups() error {
+	if b.keep <= 0 {
+		return nil
+	}
+	backups, err := Backups(b.outputDir)
+	if err != nil {
+		return err
+	}
+	for len(backups) > b.keep {
+		if err := os.Remove(backups[0]); err != nil {
+			return err
+		}
+		backups = backups[1:]
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package kv
+
+import (
+	"context"
+	"io/ioutil"
+	"path"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+)
+
+func TestStore_Backup(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := setupPruneDB(t, 1, map[uint64]uint64{0: 10})
+
+	// writers are not blocked by a running backup
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		parentHash := db.InMemoryLatestVerifiedHeaderHash(0)
+		for slot := uint64(11); slot <= 100; slot++ {
+			verifiedSlots := linkedVerifiedSlots(parentHash, slot)
+			require.NoError(t, db.CommitVerifiedSlots(0, verifiedSlots))
+			parentHash = verifiedSlots[0].SlotInfo.PandoraHeaderHash
+		}
+	}()
+	backupPath, err := db.Backup(ctx, "")
+	require.NoError(t, err)
+	<-done
+	assert.Equal(t, path.Join(db.DatabasePath(), backupsDirectoryName), path.Dir(backupPath))
+	require.NoError(t, VerifySnapshot(backupPath))
+
+	backups, err := Backups(path.Dir(backupPath))
+	require.NoError(t, err)
+	assert.DeepEqual(t, []string{backupPath}, backups)
+
+	// the backup is a database which is opened like the original one
+	restoreDir := t.TempDir()
+	keptPath, err := RestoreSnapshot(backupPath, restoreDir)
+	require.NoError(t, err)
+	assert.Equal(t, "", keptPath)
+	restored, err := NewKVStore(ctx, restoreDir, &Config{})
+	require.NoError(t, err)
+	defer func() {
+		require.NoError(t, restored.Close())
+	}()
+	slotInfo, err := restored.VerifiedSlotInfo(10, 0)
+	require.NoError(t, err)
+	require.NotNil(t, slotInfo)
+	assert.Equal(t, common.BytesToHash(bytesutil.Uint64ToBytesBigEndian(10)), slotInfo.PandoraHeaderHash)
+	consensusInfo, err := restored.ConsensusInfo(ctx, 1)
+	require.NoError(t, err)
+	assert.DeepEqual(t, testutil.NewMinimalConsensusInfo(1), consensusInfo)
+}
+
+func TestRestoreSnapshot(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	db := setupPruneDB(t, 0, map[uint64]uint64{0: 5})
+	backupPath, err := db.Backup(ctx, t.TempDir())
+	require.NoError(t, err)
+
+	// the database of the target directory is in use
+	targetDir := t.TempDir()
+	target, err := NewKVStore(ctx, targetDir, &Config{})
+	require.NoError(t, err)
+	_, err = RestoreSnapshot(backupPath, targetDir)
+	assert.ErrorContains(t, "database may be in use", err)
+	require.NoError(t, target.Close())
+
+	keptPath, err := RestoreSnapshot(backupPath, targetDir)
+	require.NoError(t, err)
+	require.NoError(t, VerifySnapshot(keptPath))
+	restored, err := NewKVStore(ctx, targetDir, &Config{})
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), restored.InMemoryLatestVerifiedSlot(0))
+	require.NoError(t, restored.Close())
+}
+
+func TestVerifySnapshot_Invalid(t *testing.T) {
+	t.Parallel()
+	dirPath := t.TempDir()
+	assert.ErrorContains(t, "does not exist", VerifySnapshot(path.Join(dirPath, "missing.db")))
+
+	garbagePath := path.Join(dirPath, "garbage.db")
+	require.NoError(t, ioutil.WriteFile(garbagePath, []byte("not a database"), 0600))
+	assert.ErrorContains(t, "could not open snapshot", VerifySnapshot(garbagePath))
+	_, err := RestoreSnapshot(garbagePath, t.TempDir())
+	assert.ErrorContains(t, "could not open snapshot", err)
+}
+
+func TestPeriodicBackup_RemoveOldBackups(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	outputDir := t.TempDir()
+	backup, err := NewPeriodicBackup(context.Background(), db, outputDir, time.Hour, 2)
+	require.NoError(t, err)
+
+	for i := 0; i < 4; i++ {
+		_, err := db.Backup(context.Background(), outputDir)
+		require.NoError(t, err)
+	}
+	backups, err := Backups(outputDir)
+	require.NoError(t, err)
+	require.NoError(t, backup.removeOldBackups())
+	remaining, err := Backups(outputDir)
+	require.NoError(t, err)
+	assert.DeepEqual(t, backups[2:], remaining)
+
+	// on-demand backups are kept by the rotation
+	onDemandPath, err := db.OnDemandBackup(context.Background(), outputDir)
+	require.NoError(t, err)
+	assert.Equal(t, path.Join(outputDir, onDemandBackupsDirectoryName), path.Dir(onDemandPath))
+	for i := 0; i < 2; i++ {
+		_, err := db.Backup(context.Background(), outputDir)
+		require.NoError(t, err)
+	}
+	require.NoError(t, backup.removeOldBackups())
+	remaining, err = Backups(outputDir)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(remaining))
+	onDemandBackups, err := Backups(path.Dir(onDemandPath))
+	require.NoError(t, err)
+	assert.DeepEqual(t, []string{onDemandPath}, onDemandBackups)
+
+	_, err = NewPeriodicBackup(context.Background(), db, outputDir, 0, 2)
+	assert.ErrorContains(t, "invalid backup interval", err)
+}
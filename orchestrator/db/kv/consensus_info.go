@@ -44,6 +44,10 @@ func (s *Store) ConsensusInfos(fromEpoch uint64) (
 	consensusInfos := make([]*eventTypes.MinimalEpochConsensusInfo, 0)
 	err := s.db.View(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(consensusInfosBucket)
+		// consensus infos of pruned epochs are skipped
+		if retainedEpoch := firstRetainedEpoch(tx); fromEpoch < retainedEpoch {
+			fromEpoch = retainedEpoch
+		}
 		for epoch := fromEpoch; epoch <= latestEpoch; epoch++ {
 			// fast finding into cache, if the value does not exist in cache, it starts finding into db
 			if v, _ := s.consensusInfoCache.Get(epoch); v != nil {
@@ -3,9 +3,233 @@ package kv
 import (
 	"bytes"
 	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+	eth2Types "github.com/prysmaticlabs/prysm/proto/eth/v1alpha1"
+)
+
+// Records are stored as a type prefix byte followed by the RLP encoding of the record. Records which were stored
+// before start with a JSON character and are still decoded as JSON. Records without binary layout, like
+// equivocations, are stored as JSON.
+const (
+	slotInfoPrefix byte = iota + 1
+	invalidSlotInfoPrefix
+	consensusInfoPrefix
+	verdictsPrefix
+	pandoraHeaderInfoPrefix
+	vanguardShardInfoPrefix
 )
 
+// invalidSlotInfoRecord is the binary layout of types.InvalidSlotInfo
+type invalidSlotInfoRecord struct {
+	VanguardBlockHash common.Hash
+	PandoraHeaderHash common.Hash
+	Reason            string
+}
+
+// consensusInfoRecord is the binary layout of types.MinimalEpochConsensusInfo. Validator public keys are stored as
+// raw bytes instead of hex strings.
+type consensusInfoRecord struct {
+	Epoch            uint64
+	ValidatorList    [][]byte
+	EpochStartTime   uint64
+	SlotTimeDuration uint64
+}
+
+// vanguardShardInfoRecord is the binary layout of types.VanguardShardInfo with the fields of its pandora shard
+type vanguardShardInfoRecord struct {
+	Slot        uint64
+	ShardIndex  uint64
+	BlockHash   []byte
+	BlockNumber uint64
+	Hash        []byte
+	ParentHash  []byte
+	StateRoot   []byte
+	TxHash      []byte
+	ReceiptHash []byte
+	SealHash    []byte
+	Signature   []byte
+}
+
 func encode(v interface{}) ([]byte, error) {
+	prefix, record, ok := binaryRecord(v)
+	if !ok {
+		return encodeJSON(v)
+	}
+	enc, err := rlp.EncodeToBytes(record)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte{prefix}, enc...), nil
+}
+
+func decode(data []byte, v interface{}) error {
+	if len(data) == 0 || data[0] < slotInfoPrefix || data[0] > vanguardShardInfoPrefix {
+		return decodeJSON(data, v)
+	}
+	prefix, enc := data[0], data[1:]
+	switch target := v.(type) {
+	case **types.SlotInfo:
+		if prefix != slotInfoPrefix {
+			break
+		}
+		slotInfo := new(types.SlotInfo)
+		if err := rlp.DecodeBytes(enc, slotInfo); err != nil {
+			return err
+		}
+		*target = slotInfo
+		return nil
+	case **types.InvalidSlotInfo:
+		if prefix != invalidSlotInfoPrefix {
+			break
+		}
+		record := new(invalidSlotInfoRecord)
+		if err := rlp.DecodeBytes(enc, record); err != nil {
+			return err
+		}
+		*target = &types.InvalidSlotInfo{
+			SlotInfo: types.SlotInfo{
+				VanguardBlockHash: record.VanguardBlockHash,
+				PandoraHeaderHash: record.PandoraHeaderHash,
+			},
+			Reason: types.MismatchReason(record.Reason),
+		}
+		return nil
+	case **types.MinimalEpochConsensusInfo:
+		if prefix != consensusInfoPrefix {
+			break
+		}
+		record := new(consensusInfoRecord)
+		if err := rlp.DecodeBytes(enc, record); err != nil {
+			return err
+		}
+		validatorList := make([]string, len(record.ValidatorList))
+		for i, pubKey := range record.ValidatorList {
+			validatorList[i] = hexutil.Encode(pubKey)
+		}
+		*target = &types.MinimalEpochConsensusInfo{
+			Epoch:            record.Epoch,
+			ValidatorList:    validatorList,
+			EpochStartTime:   record.EpochStartTime,
+			SlotTimeDuration: time.Duration(record.SlotTimeDuration),
+		}
+		return nil
+	case *[]*types.RuleVerdict:
+		if prefix != verdictsPrefix {
+			break
+		}
+		return rlp.DecodeBytes(enc, target)
+	case **types.PandoraHeaderInfo:
+		if prefix != pandoraHeaderInfoPrefix {
+			break
+		}
+		headerInfo := new(types.PandoraHeaderInfo)
+		if err := rlp.DecodeBytes(enc, headerInfo); err != nil {
+			return err
+		}
+		*target = headerInfo
+		return nil
+	case **types.VanguardShardInfo:
+		if prefix != vanguardShardInfoPrefix {
+			break
+		}
+		record := new(vanguardShardInfoRecord)
+		if err := rlp.DecodeBytes(enc, record); err != nil {
+			return err
+		}
+		*target = &types.VanguardShardInfo{
+			Slot:       record.Slot,
+			ShardIndex: record.ShardIndex,
+			BlockHash:  record.BlockHash,
+			ShardInfo: &eth2Types.PandoraShard{
+				BlockNumber: record.BlockNumber,
+				Hash:        record.Hash,
+				ParentHash:  record.ParentHash,
+				StateRoot:   record.StateRoot,
+				TxHash:      record.TxHash,
+				ReceiptHash: record.ReceiptHash,
+				SealHash:    record.SealHash,
+				Signature:   record.Signature,
+			},
+		}
+		return nil
+	}
+	return fmt.Errorf("could not decode record with type prefix %d into %T", prefix, v)
+}
+
+// binaryRecord returns the type prefix and the RLP encodable layout of the value. It returns false when the value
+// has no binary layout or can not be stored in it without loss, so that it is stored as JSON.
+func binaryRecord(v interface{}) (byte, interface{}, bool) {
+	switch v := v.(type) {
+	case *types.SlotInfo:
+		return slotInfoPrefix, v, v != nil
+	case *types.InvalidSlotInfo:
+		if v == nil {
+			return 0, nil, false
+		}
+		return invalidSlotInfoPrefix, &invalidSlotInfoRecord{
+			VanguardBlockHash: v.VanguardBlockHash,
+			PandoraHeaderHash: v.PandoraHeaderHash,
+			Reason:            string(v.Reason),
+		}, true
+	case *types.MinimalEpochConsensusInfo:
+		if v == nil || v.SlotTimeDuration < 0 {
+			return 0, nil, false
+		}
+		validatorList := make([][]byte, len(v.ValidatorList))
+		for i, pubKey := range v.ValidatorList {
+			pubKeyBytes, err := hexutil.Decode(pubKey)
+			// only keys which are restored to the same string are stored as bytes
+			if err != nil || hexutil.Encode(pubKeyBytes) != pubKey {
+				return 0, nil, false
+			}
+			validatorList[i] = pubKeyBytes
+		}
+		return consensusInfoPrefix, &consensusInfoRecord{
+			Epoch:            v.Epoch,
+			ValidatorList:    validatorList,
+			EpochStartTime:   v.EpochStartTime,
+			SlotTimeDuration: uint64(v.SlotTimeDuration),
+		}, true
+	case []*types.RuleVerdict:
+		for _, verdict := range v {
+			if verdict == nil {
+				return 0, nil, false
+			}
+		}
+		return verdictsPrefix, v, true
+	case *types.PandoraHeaderInfo:
+		if v == nil || v.Header == nil {
+			return 0, nil, false
+		}
+		return pandoraHeaderInfoPrefix, v, true
+	case *types.VanguardShardInfo:
+		if v == nil || v.ShardInfo == nil {
+			return 0, nil, false
+		}
+		return vanguardShardInfoPrefix, &vanguardShardInfoRecord{
+			Slot:        v.Slot,
+			ShardIndex:  v.ShardIndex,
+			BlockHash:   v.BlockHash,
+			BlockNumber: v.ShardInfo.BlockNumber,
+			Hash:        v.ShardInfo.Hash,
+			ParentHash:  v.ShardInfo.ParentHash,
+			StateRoot:   v.ShardInfo.StateRoot,
+			TxHash:      v.ShardInfo.TxHash,
+			ReceiptHash: v.ShardInfo.ReceiptHash,
+			SealHash:    v.ShardInfo.SealHash,
+			Signature:   v.ShardInfo.Signature,
+		}, true
+	}
+	return 0, nil, false
+}
+
+func encodeJSON(v interface{}) ([]byte, error) {
 	buffer := new(bytes.Buffer)
 	if err := json.NewEncoder(buffer).Encode(v); err != nil {
 		return nil, err
@@ -13,7 +237,7 @@ func encode(v interface{}) ([]byte, error) {
 	return buffer.Bytes(), nil
 }
 
-func decode(data []byte, v interface{}) error {
+func decodeJSON(data []byte, v interface{}) error {
 	var buf bytes.Buffer
 	if _, err := buf.Write(data); err != nil {
 		return err
@@ -1,6 +1,10 @@
 package kv
 
 import (
+	"github.com/boltdb/bolt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
@@ -18,3 +22,202 @@ func Test_EncodingDecoding_Success(t *testing.T) {
 	require.NoError(t, decode(consensusInfoEncoded0, &consensusInfoDecoded0))
 	assert.DeepEqual(t, consensusInfo0, consensusInfoDecoded0)
 }
+
+func TestEncoding_BinaryRecords(t *testing.T) {
+	header := testutil.NewEth1Header(10)
+	shardInfo := testutil.NewVanguardShardInfo(10, header)
+	shardInfo.ShardIndex = 2
+	slotInfo := &types.SlotInfo{
+		VanguardBlockHash: common.BytesToHash(shardInfo.BlockHash),
+		PandoraHeaderHash: header.Hash(),
+	}
+	tests := []struct {
+		name    string
+		value   interface{}
+		prefix  byte
+		decoded func(enc []byte) (interface{}, error)
+	}{
+		{
+			name:   "slot info",
+			value:  slotInfo,
+			prefix: slotInfoPrefix,
+			decoded: func(enc []byte) (interface{}, error) {
+				var v *types.SlotInfo
+				err := decode(enc, &v)
+				return v, err
+			},
+		},
+		{
+			name:   "invalid slot info",
+			value:  &types.InvalidSlotInfo{SlotInfo: *slotInfo, Reason: types.ParentHashMismatch},
+			prefix: invalidSlotInfoPrefix,
+			decoded: func(enc []byte) (interface{}, error) {
+				var v *types.InvalidSlotInfo
+				err := decode(enc, &v)
+				return v, err
+			},
+		},
+		{
+			name:   "consensus info",
+			value:  testutil.NewMinimalConsensusInfo(3),
+			prefix: consensusInfoPrefix,
+			decoded: func(enc []byte) (interface{}, error) {
+				var v *types.MinimalEpochConsensusInfo
+				err := decode(enc, &v)
+				return v, err
+			},
+		},
+		{
+			name:   "verdicts",
+			value:  []*types.RuleVerdict{{Rule: "sharding-info"}, {Rule: "signature", Reason: types.InvalidSignature}},
+			prefix: verdictsPrefix,
+			decoded: func(enc []byte) (interface{}, error) {
+				var v []*types.RuleVerdict
+				err := decode(enc, &v)
+				return v, err
+			},
+		},
+		{
+			name:   "pandora header info",
+			value:  &types.PandoraHeaderInfo{Slot: 10, ShardIndex: 2, Header: header},
+			prefix: pandoraHeaderInfoPrefix,
+			decoded: func(enc []byte) (interface{}, error) {
+				var v *types.PandoraHeaderInfo
+				err := decode(enc, &v)
+				return v, err
+			},
+		},
+		{
+			name:   "vanguard shard info",
+			value:  shardInfo,
+			prefix: vanguardShardInfoPrefix,
+			decoded: func(enc []byte) (interface{}, error) {
+				var v *types.VanguardShardInfo
+				err := decode(enc, &v)
+				return v, err
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			enc, err := encode(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, tt.prefix, enc[0])
+			jsonEnc, err := encodeJSON(tt.value)
+			require.NoError(t, err)
+			assert.Equal(t, true, len(enc) < len(jsonEnc), "binary record is not smaller than json")
+
+			decoded, err := tt.decoded(enc)
+			require.NoError(t, err)
+			assertSameRecord(t, tt.value, decoded)
+
+			// records which are stored as json before are still readable
+			decoded, err = tt.decoded(jsonEnc)
+			require.NoError(t, err)
+			assertSameRecord(t, tt.value, decoded)
+		})
+	}
+}
+
+// assertSameRecord compares records by their json encoding, so that headers are compared without their cached hash
+func assertSameRecord(t *testing.T, expected, actual interface{}) {
+	expectedJSON, err := encodeJSON(expected)
+	require.NoError(t, err)
+	actualJSON, err := encodeJSON(actual)
+	require.NoError(t, err)
+	assert.Equal(t, string(expectedJSON), string(actualJSON))
+}
+
+func TestEncoding_JSONFallback(t *testing.T) {
+	// validator keys which do not survive the hex round trip are kept as json
+	consensusInfo := testutil.NewMinimalConsensusInfo(3)
+	consensusInfo.ValidatorList[0] = "0xABCD"
+	enc, err := encode(consensusInfo)
+	require.NoError(t, err)
+	assert.Equal(t, byte('{'), enc[0])
+
+	var decoded *types.MinimalEpochConsensusInfo
+	require.NoError(t, decode(enc, &decoded))
+	assert.DeepEqual(t, consensusInfo, decoded)
+
+	// a binary record can not be decoded into another type
+	enc, err = encode(&types.SlotInfo{})
+	require.NoError(t, err)
+	var verdicts []*types.RuleVerdict
+	assert.ErrorContains(t, "could not decode record with type prefix", decode(enc, &verdicts))
+}
+
+// number of slots in the benchmark db, which is about ten days of a shard
+const benchmarkSlots = 100000
+
+// setupBenchmarkDB fills a store with the verified slot infos of the benchmark slots and the consensus infos of their
+// epochs. It returns the number of bytes of the stored slot info and consensus info values.
+func setupBenchmarkDB(b *testing.B, encodeFn func(v interface{}) ([]byte, error)) (*Store, int, int) {
+	db := setupDB(b, true)
+	slotInfoBytes, consensusInfoBytes := 0, 0
+	latestEpoch := uint64(benchmarkSlots/types.SlotsPerEpoch - 1)
+	require.NoError(b, db.db.Update(func(tx *bolt.Tx) error {
+		slotBkt := tx.Bucket(verifiedSlotInfosBucket)
+		for slot := uint64(0); slot < benchmarkSlots; slot++ {
+			enc, err := encodeFn(&types.SlotInfo{
+				VanguardBlockHash: common.BytesToHash(crypto.Keccak256(bytesutil.Uint64ToBytesBigEndian(slot))),
+				PandoraHeaderHash: common.BytesToHash(crypto.Keccak256(bytesutil.Uint64ToBytesBigEndian(slot + 1))),
+			})
+			if err != nil {
+				return err
+			}
+			slotInfoBytes += len(enc)
+			if err := slotBkt.Put(slotKey(slot, 0), enc); err != nil {
+				return err
+			}
+		}
+		if err := slotBkt.Put(latestSavedVerifiedSlotKey, bytesutil.Uint64ToBytesBigEndian(benchmarkSlots-1)); err != nil {
+			return err
+		}
+
+		consensusBkt := tx.Bucket(consensusInfosBucket)
+		for epoch := uint64(0); epoch <= latestEpoch; epoch++ {
+			enc, err := encodeFn(testutil.NewMinimalConsensusInfo(epoch))
+			if err != nil {
+				return err
+			}
+			consensusInfoBytes += len(enc)
+			if err := consensusBkt.Put(bytesutil.Uint64ToBytesBigEndian(epoch), enc); err != nil {
+				return err
+			}
+		}
+		return consensusBkt.Put(lastStoredEpochKey, bytesutil.Uint64ToBytesBigEndian(latestEpoch))
+	}))
+	return db, slotInfoBytes, consensusInfoBytes
+}
+
+func benchmarkEncodings(b *testing.B, fn func(b *testing.B, encodeFn func(v interface{}) ([]byte, error))) {
+	b.Run("json", func(b *testing.B) { fn(b, encodeJSON) })
+	b.Run("binary", func(b *testing.B) { fn(b, encode) })
+}
+
+func BenchmarkStore_VerifiedSlotInfos(b *testing.B) {
+	benchmarkEncodings(b, func(b *testing.B, encodeFn func(v interface{}) ([]byte, error)) {
+		db, slotInfoBytes, _ := setupBenchmarkDB(b, encodeFn)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			slotInfos, err := db.VerifiedSlotInfos(0, 0)
+			require.NoError(b, err)
+			require.Equal(b, benchmarkSlots, len(slotInfos))
+		}
+		b.ReportMetric(float64(slotInfoBytes)/benchmarkSlots, "bytes/slot")
+	})
+}
+
+func BenchmarkStore_ConsensusInfos(b *testing.B) {
+	benchmarkEncodings(b, func(b *testing.B, encodeFn func(v interface{}) ([]byte, error)) {
+		db, _, consensusInfoBytes := setupBenchmarkDB(b, encodeFn)
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			consensusInfos, err := db.ConsensusInfos(0)
+			require.NoError(b, err)
+			require.Equal(b, benchmarkSlots/types.SlotsPerEpoch, len(consensusInfos))
+		}
+		b.ReportMetric(float64(consensusInfoBytes)*types.SlotsPerEpoch/benchmarkSlots, "bytes/epoch")
+	})
+}
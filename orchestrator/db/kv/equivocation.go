@@ -0,0 +1,55 @@
+package kv
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// equivocationKey orders the evidences by slot, then by shard and then by arrival. A slot of a shard may have
+// more than one evidence when a proposer keeps sending new proposals.
+func equivocationKey(slot uint64, shardIndex uint64, sequence uint64) []byte {
+	key := make([]byte, 0, 24)
+	key = append(key, bytesutil.Uint64ToBytesBigEndian(slot)...)
+	key = append(key, bytesutil.Uint64ToBytesBigEndian(shardIndex)...)
+	return append(key, bytesutil.Uint64ToBytesBigEndian(sequence)...)
+}
+
+// Equivocations returns the evidences of double proposals from the given slot in ascending slot order
+func (s *Store) Equivocations(fromSlot uint64) ([]*types.Equivocation, error) {
+	equivocations := make([]*types.Equivocation, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		c := tx.Bucket(equivocationsBucket).Cursor()
+		for k, v := c.Seek(bytesutil.Uint64ToBytesBigEndian(fromSlot)); k != nil; k, v = c.Next() {
+			var equivocation *types.Equivocation
+			if err := decode(v, &equivocation); err != nil {
+				return err
+			}
+			equivocations = append(equivocations, equivocation)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return equivocations, nil
+}
+
+// SaveEquivocation stores the evidence of a double proposal
+func (s *Store) SaveEquivocation(equivocation *types.Equivocation) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(equivocationsBucket)
+		sequence, err := bkt.NextSequence()
+		if err != nil {
+			return err
+		}
+		enc, err := encode(equivocation)
+		if err != nil {
+			return err
+		}
+		return bkt.Put(equivocationKey(equivocation.Slot, equivocation.ShardIndex, sequence), enc)
+	})
+}
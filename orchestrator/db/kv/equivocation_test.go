@@ -0,0 +1,51 @@
+package kv
+
+import (
+	"testing"
+
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestStore_Equivocations(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	firstHeader, secondHeader := testutil.NewEth1Header(5), testutil.NewEth1Header(5)
+	secondHeader.Time++
+	pandoraEquivocation := &types.Equivocation{
+		Kind:           types.PandoraEquivocation,
+		Slot:           5,
+		ShardIndex:     1,
+		ProposerIndex:  5,
+		PandoraHeaders: []*eth1Types.Header{firstHeader, secondHeader},
+	}
+	vanguardEquivocation := &types.Equivocation{
+		Kind:          types.VanguardEquivocation,
+		Slot:          3,
+		ProposerIndex: 3,
+		VanguardShardInfos: []*types.VanguardShardInfo{
+			testutil.NewVanguardShardInfo(3, firstHeader),
+			testutil.NewVanguardShardInfo(3, secondHeader),
+		},
+	}
+	require.NoError(t, db.SaveEquivocation(pandoraEquivocation))
+	require.NoError(t, db.SaveEquivocation(vanguardEquivocation))
+
+	equivocations, err := db.Equivocations(0)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(equivocations))
+	assert.Equal(t, types.VanguardEquivocation, equivocations[0].Kind)
+	assert.DeepEqual(t, vanguardEquivocation.VanguardShardInfos[1].BlockHash, equivocations[0].VanguardShardInfos[1].BlockHash)
+	assert.Equal(t, types.PandoraEquivocation, equivocations[1].Kind)
+	assert.Equal(t, uint64(1), equivocations[1].ShardIndex)
+	assert.Equal(t, firstHeader.Hash(), equivocations[1].PandoraHeaders[0].Hash())
+	assert.Equal(t, secondHeader.Hash(), equivocations[1].PandoraHeaders[1].Hash())
+
+	equivocations, err = db.Equivocations(4)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(equivocations))
+	assert.Equal(t, uint64(5), equivocations[0].Slot)
+}
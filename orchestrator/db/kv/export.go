@@ -0,0 +1,473 @@
+package kv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/ethereum/go-ethereum/common"
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/params"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+	"github.com/pkg/errors"
+)
+
+// ExportFormatVersion is the version of the export file format. An export file is a JSON record per line. It starts
+// with a header record and ends with a checksum record which holds the number of records and the sha256 hash of all
+// lines before it.
+const ExportFormatVersion = 1
+
+// types of the export records
+const (
+	exportHeader        = "header"
+	exportConsensusInfo = "consensusInfo"
+	exportVerifiedSlot  = "verifiedSlot"
+	exportInvalidSlot   = "invalidSlot"
+	exportSkippedSlot   = "skippedSlot"
+	exportTimedOutSlot  = "timedOutSlot"
+	exportVerdicts      = "verdicts"
+	exportEquivocation  = "equivocation"
+	exportShardPointers = "shard"
+	exportChecksum      = "checksum"
+)
+
+const (
+	importSlotBatchSize   = 1000
+	maxExportRecordLength = 1 << 20
+)
+
+var errDatabaseNotEmpty = errors.New("database is not empty")
+
+// exportRecord is a line of the export file. Only the fields of its type are set.
+type exportRecord struct {
+	Type string `json:"type"`
+
+	// header
+	Version            uint64 `json:"version,omitempty"`
+	SchemaVersion      uint64 `json:"schemaVersion,omitempty"`
+	LatestEpoch        uint64 `json:"latestEpoch,omitempty"`
+	FirstRetainedEpoch uint64 `json:"firstRetainedEpoch,omitempty"`
+
+	// slot infos and shard pointers
+	Slot            uint64                           `json:"slot,omitempty"`
+	ShardIndex      uint64                           `json:"shardIndex,omitempty"`
+	ConsensusInfo   *types.MinimalEpochConsensusInfo `json:"consensusInfo,omitempty"`
+	SlotInfo        *types.SlotInfo                  `json:"slotInfo,omitempty"`
+	InvalidSlotInfo *types.InvalidSlotInfo           `json:"invalidSlotInfo,omitempty"`
+	Shard           *exportShard                     `json:"shard,omitempty"`
+
+	// payload of a verified slot, it is left out when the payload is pruned
+	Header    *eth1Types.Header        `json:"header,omitempty"`
+	ShardInfo *types.VanguardShardInfo `json:"shardInfo,omitempty"`
+
+	// verdicts and equivocation evidence
+	Verdicts     []*types.RuleVerdict `json:"verdicts,omitempty"`
+	Equivocation *types.Equivocation  `json:"equivocation,omitempty"`
+
+	// checksum
+	Count    uint64 `json:"count,omitempty"`
+	Checksum string `json:"checksum,omitempty"`
+}
+
+// exportShard holds the latest pointers of a shard
+type exportShard struct {
+	LatestVerifiedSlot  uint64      `json:"latestVerifiedSlot"`
+	LatestHeaderHash    common.Hash `json:"latestHeaderHash"`
+	LatestFinalizedSlot uint64      `json:"latestFinalizedSlot"`
+	FirstRetainedSlot   uint64      `json:"firstRetainedSlot"`
+}
+
+// exportWriter writes records and hashes them for the checksum record
+type exportWriter struct {
+	encoder *json.Encoder
+	count   uint64
+}
+
+func (w *exportWriter) write(record *exportRecord) error {
+	w.count++
+	return w.encoder.Encode(record)
+}
+
+// Export writes consensus infos, verified slot infos with their payloads, invalid, skipped and timed out slot infos,
+// verdicts, equivocation evidence and the latest pointers of every shard into the writer. Pending headers and shard
+// infos are not exported. Everything is read in one transaction, so the export is consistent. It returns the number
+// of written records.
+func (s *Store) Export(ctx context.Context, w io.Writer) (uint64, error) {
+	return export(ctx, s.db, w)
+}
+
+// ExportDatabase writes the export of the database in the directory into the writer. The database is opened read
+// only, so it is neither migrated nor changed. Its schema must be the current one. It returns the number of written
+// records.
+func ExportDatabase(ctx context.Context, dirPath string, w io.Writer) (uint64, error) {
+	datafile := path.Join(dirPath, DatabaseFileName)
+	if _, err := os.Stat(datafile); os.IsNotExist(err) {
+		return 0, fmt.Errorf("database %s does not exist", datafile)
+	}
+	boltDB, err := bolt.Open(datafile, params.OrchestratorIoConfig().ReadWritePermissions, &bolt.Options{
+		Timeout:  1 * time.Second,
+		ReadOnly: true,
+	})
+	if err != nil {
+		return 0, err
+	}
+	defer boltDB.Close()
+
+	if err := boltDB.View(func(tx *bolt.Tx) error {
+		if tx.Bucket(consensusInfosBucket) == nil {
+			return fmt.Errorf("database %s has no %s bucket", datafile, consensusInfosBucket)
+		}
+		if version := schemaVersion(tx); version != SchemaVersion {
+			return fmt.Errorf("database schema version %d is not the supported version %d, "+
+				"start the orchestrator once to migrate it", version, SchemaVersion)
+		}
+		return nil
+	}); err != nil {
+		return 0, err
+	}
+	return export(ctx, boltDB, w)
+}
+
+// export writes the records of the database into the writer in one read transaction
+func export(ctx context.Context, db *bolt.DB, w io.Writer) (uint64, error) {
+	hasher := sha256.New()
+	out := &exportWriter{encoder: json.NewEncoder(io.MultiWriter(w, hasher))}
+
+	err := db.View(func(tx *bolt.Tx) error {
+		if err := out.write(&exportRecord{
+			Type:               exportHeader,
+			Version:            ExportFormatVersion,
+			SchemaVersion:      schemaVersion(tx),
+			LatestEpoch:        bytesutil.BytesToUint64BigEndian(tx.Bucket(consensusInfosBucket).Get(lastStoredEpochKey)),
+			FirstRetainedEpoch: firstRetainedEpoch(tx),
+		}); err != nil {
+			return err
+		}
+
+		c := tx.Bucket(consensusInfosBucket).Cursor()
+		for k, v := c.First(); k != nil; k, v = c.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			// the latest epoch key is no epoch
+			if len(k) != 8 {
+				continue
+			}
+			var consensusInfo *types.MinimalEpochConsensusInfo
+			if err := decode(v, &consensusInfo); err != nil {
+				return err
+			}
+			if err := out.write(&exportRecord{Type: exportConsensusInfo, ConsensusInfo: consensusInfo}); err != nil {
+				return err
+			}
+		}
+
+		for _, export := range []struct {
+			bucket     []byte
+			recordType string
+		}{
+			{verifiedSlotInfosBucket, exportVerifiedSlot},
+			{invalidSlotInfosBucket, exportInvalidSlot},
+			{skippedSlotInfosBucket, exportSkippedSlot},
+			{timedOutSlotInfosBucket, exportTimedOutSlot},
+			{verdictsBucket, exportVerdicts},
+		} {
+			c := tx.Bucket(export.bucket).Cursor()
+			for k, v := c.First(); k != nil; k, v = c.Next() {
+				if err := ctx.Err(); err != nil {
+					return err
+				}
+				slot, shardIndex, ok := parseSlotKey(k)
+				if !ok {
+					continue
+				}
+				record := &exportRecord{Type: export.recordType, Slot: slot, ShardIndex: shardIndex}
+				var err error
+				switch export.recordType {
+				case exportInvalidSlot:
+					err = decode(v, &record.InvalidSlotInfo)
+				case exportVerdicts:
+					err = decode(v, &record.Verdicts)
+				default:
+					err = decode(v, &record.SlotInfo)
+				}
+				if err != nil {
+					return err
+				}
+				if export.recordType == exportVerifiedSlot {
+					verifiedSlot := new(types.VerifiedSlot)
+					if err := readVerifiedPayload(tx, k, verifiedSlot); err != nil {
+						return err
+					}
+					record.Header, record.ShardInfo = verifiedSlot.Header, verifiedSlot.ShardInfo
+				}
+				if err := out.write(record); err != nil {
+					return err
+				}
+			}
+		}
+
+		c = tx.Bucket(equivocationsBucket).Cursor()
+		for _, v := c.First(); v != nil; _, v = c.Next() {
+			if err := ctx.Err(); err != nil {
+				return err
+			}
+			var equivocation *types.Equivocation
+			if err := decode(v, &equivocation); err != nil {
+				return err
+			}
+			if err := out.write(&exportRecord{Type: exportEquivocation, Equivocation: equivocation}); err != nil {
+				return err
+			}
+		}
+
+		for _, shardIndex := range savedShardIndexes(tx) {
+			bkt := tx.Bucket(verifiedSlotInfosBucket)
+			if err := out.write(&exportRecord{
+				Type:       exportShardPointers,
+				ShardIndex: shardIndex,
+				Shard: &exportShard{
+					LatestVerifiedSlot:  bytesutil.BytesToUint64BigEndian(bkt.Get(shardKey(latestSavedVerifiedSlotKey, shardIndex))),
+					LatestHeaderHash:    common.BytesToHash(bkt.Get(shardKey(latestHeaderHashKey, shardIndex))),
+					LatestFinalizedSlot: bytesutil.BytesToUint64BigEndian(bkt.Get(shardKey(latestFinalizedSlotKey, shardIndex))),
+					FirstRetainedSlot:   firstRetainedSlot(tx, shardIndex),
+				},
+			}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	count := out.count
+	// the checksum record is not part of the checksum
+	out.encoder = json.NewEncoder(w)
+	if err := out.write(&exportRecord{
+		Type:     exportChecksum,
+		Count:    count,
+		Checksum: hex.EncodeToString(hasher.Sum(nil)),
+	}); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Import reads an export into the empty database. The checksum of the export is verified before anything is
+// stored, the records themselves are trusted and are not verified again. Verified slot infos are stored in batches
+// with SaveVerifiedSlotInfos together with their payloads, then the latest pointers and retained boundaries of the export replace the ones which
+// are moved by storing the records. It returns the number of imported records.
+func (s *Store) Import(ctx context.Context, r io.ReadSeeker) (uint64, error) {
+	if err := s.checkEmpty(); err != nil {
+		return 0, err
+	}
+	header, err := verifyExport(r)
+	if err != nil {
+		return 0, err
+	}
+	if _, err := r.Seek(0, io.SeekStart); err != nil {
+		return 0, err
+	}
+
+	imported := uint64(0)
+	verifiedSlots := make(map[uint64][]*exportRecord)
+	flushVerifiedSlots := func(shardIndex uint64) error {
+		if err := s.importVerifiedSlots(shardIndex, verifiedSlots[shardIndex]); err != nil {
+			return err
+		}
+		delete(verifiedSlots, shardIndex)
+		return nil
+	}
+	shards := make(map[uint64]*exportShard)
+
+	scanner := newExportScanner(r)
+	for scanner.Scan() {
+		if err := ctx.Err(); err != nil {
+			return imported, err
+		}
+		record := new(exportRecord)
+		if err := json.Unmarshal(scanner.Bytes(), record); err != nil {
+			return imported, errors.Wrap(err, "could not decode export record")
+		}
+
+		switch record.Type {
+		case exportHeader, exportChecksum:
+			continue
+		case exportConsensusInfo:
+			err = s.SaveConsensusInfo(ctx, record.ConsensusInfo)
+		case exportVerifiedSlot:
+			verifiedSlots[record.ShardIndex] = append(verifiedSlots[record.ShardIndex], record)
+			if len(verifiedSlots[record.ShardIndex]) >= importSlotBatchSize {
+				err = flushVerifiedSlots(record.ShardIndex)
+			}
+		case exportInvalidSlot:
+			err = s.SaveInvalidSlotInfo(record.Slot, record.ShardIndex, record.InvalidSlotInfo)
+		case exportSkippedSlot:
+			err = s.SaveSkippedSlotInfo(record.Slot, record.ShardIndex, record.SlotInfo)
+		case exportTimedOutSlot:
+			err = s.SaveTimedOutSlotInfo(record.Slot, record.ShardIndex, record.SlotInfo)
+		case exportVerdicts:
+			err = s.SaveVerdicts(record.Slot, record.ShardIndex, record.Verdicts)
+		case exportEquivocation:
+			err = s.SaveEquivocation(record.Equivocation)
+		case exportShardPointers:
+			shards[record.ShardIndex] = record.Shard
+		default:
+			err = fmt.Errorf("unknown export record type %q", record.Type)
+		}
+		if err != nil {
+			return imported, err
+		}
+		imported++
+	}
+	if err := scanner.Err(); err != nil {
+		return imported, err
+	}
+	for shardIndex := range verifiedSlots {
+		if err := flushVerifiedSlots(shardIndex); err != nil {
+			return imported, err
+		}
+	}
+
+	// the latest pointers of the export replace the ones which are moved by storing the records
+	s.Mutex.Lock()
+	s.latestEpoch = header.LatestEpoch
+	for shardIndex, shard := range shards {
+		s.latestVerifiedSlot[shardIndex] = shard.LatestVerifiedSlot
+		s.latestHeaderHash[shardIndex] = shard.LatestHeaderHash
+	}
+	s.Mutex.Unlock()
+	if err := s.SaveLatestEpoch(ctx); err != nil {
+		return imported, err
+	}
+	firstRetainedSlots := make(map[uint64]uint64, len(shards))
+	for shardIndex, shard := range shards {
+		if err := s.SaveLatestVerifiedSlot(ctx, shardIndex); err != nil {
+			return imported, err
+		}
+		if err := s.SaveLatestVerifiedHeaderHash(shardIndex); err != nil {
+			return imported, err
+		}
+		if err := s.SaveLatestFinalizedSlot(shard.LatestFinalizedSlot, shardIndex); err != nil {
+			return imported, err
+		}
+		firstRetainedSlots[shardIndex] = shard.FirstRetainedSlot
+	}
+	return imported, s.saveRetainedBoundaries(header.FirstRetainedEpoch, firstRetainedSlots)
+}
+
+// importVerifiedSlots stores a batch of exported verified slot infos of the shard and then their payloads
+func (s *Store) importVerifiedSlots(shardIndex uint64, records []*exportRecord) error {
+	slotInfos := make(map[uint64]*types.SlotInfo, len(records))
+	for _, record := range records {
+		slotInfos[record.Slot] = record.SlotInfo
+	}
+	if err := s.SaveVerifiedSlotInfos(shardIndex, slotInfos); err != nil {
+		return err
+	}
+
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+	return s.db.Update(func(tx *bolt.Tx) error {
+		for _, record := range records {
+			if err := putVerifiedPayload(tx, record.Slot, shardIndex, record.Header, record.ShardInfo); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// checkEmpty returns an error when the database has consensus infos or verified slot infos
+func (s *Store) checkEmpty() error {
+	return s.db.View(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{consensusInfosBucket, verifiedSlotInfosBucket} {
+			c := tx.Bucket(bucket).Cursor()
+			for k, _ := c.First(); k != nil; k, _ = c.Next() {
+				// epochs and slots have keys of slot key length, latest pointers have longer keys
+				if _, _, ok := parseSlotKey(k); ok {
+					return errDatabaseNotEmpty
+				}
+			}
+		}
+		return nil
+	})
+}
+
+// verifyExport reads the whole export and checks its version, number of records and checksum. It returns the header.
+func verifyExport(r io.Reader) (*exportRecord, error) {
+	hasher := sha256.New()
+	scanner := newExportScanner(r)
+	var header *exportRecord
+	var line []byte
+	count := uint64(0)
+	for scanner.Scan() {
+		// the previous line is not the checksum record
+		if line != nil {
+			hasher.Write(line)
+			hasher.Write([]byte{'\n'})
+			count++
+		}
+		line = append(line[:0], scanner.Bytes()...)
+		if header == nil {
+			header = new(exportRecord)
+			if err := json.Unmarshal(line, header); err != nil || header.Type != exportHeader {
+				return nil, errors.New("export has no header record")
+			}
+			if header.Version != ExportFormatVersion {
+				return nil, fmt.Errorf("export format version %d is not supported", header.Version)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if header == nil {
+		return nil, errors.New("export is empty")
+	}
+
+	checksum := new(exportRecord)
+	if err := json.Unmarshal(line, checksum); err != nil || checksum.Type != exportChecksum {
+		return nil, errors.New("export has no checksum record, it may be truncated")
+	}
+	if checksum.Count != count {
+		return nil, fmt.Errorf("export has %d records, expected %d", count, checksum.Count)
+	}
+	if sum := hex.EncodeToString(hasher.Sum(nil)); sum != checksum.Checksum {
+		return nil, fmt.Errorf("export checksum %s does not match expected checksum %s", sum, checksum.Checksum)
+	}
+	return header, nil
+}
+
+func newExportScanner(r io.Reader) *bufio.Scanner {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxExportRecordLength)
+	return scanner
+}
+
+// savedShardIndexes returns the shards which have a stored latest verified slot
+func savedShardIndexes(tx *bolt.Tx) []uint64 {
+	shardIndexes := make([]uint64, 0)
+	c := tx.Bucket(verifiedSlotInfosBucket).Cursor()
+	for k, _ := c.Seek(latestSavedVerifiedSlotKey); k != nil && bytes.HasPrefix(k, latestSavedVerifiedSlotKey); k, _ = c.Next() {
+		switch len(k) {
+		case len(latestSavedVerifiedSlotKey):
+			shardIndexes = append(shardIndexes, 0)
+		case len(latestSavedVerifiedSlotKey) + 8:
+			shardIndexes = append(shardIndexes, bytesutil.BytesToUint64BigEndian(k[len(latestSavedVerifiedSlotKey):]))
+		}
+	}
+	return shardIndexes
+}
@@ -0,0 +1,175 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestStore_ExportImport(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	// more verified slots than fit into one import batch
+	src := setupPruneDB(t, 2, map[uint64]uint64{0: 1500, 1: 20})
+	require.NoError(t, src.SaveInvalidSlotInfo(1501, 0, &types.InvalidSlotInfo{
+		SlotInfo: types.SlotInfo{PandoraHeaderHash: common.HexToHash("0x01")},
+		Reason:   types.ParentHashMismatch,
+	}))
+	require.NoError(t, src.SaveSkippedSlotInfo(21, 1, &types.SlotInfo{}))
+	require.NoError(t, src.SaveTimedOutSlotInfo(22, 1, &types.SlotInfo{PandoraHeaderHash: common.HexToHash("0x02")}))
+	verdicts := []*types.RuleVerdict{{Rule: "sharding-info", Reason: types.ParentHashMismatch}}
+	require.NoError(t, src.SaveVerdicts(1501, 0, verdicts))
+	verifiedSlots := linkedVerifiedSlots(src.InMemoryLatestVerifiedHeaderHash(0), 1502)
+	require.NoError(t, src.CommitVerifiedSlots(0, verifiedSlots))
+	equivocation := &types.Equivocation{
+		Kind:               types.VanguardEquivocation,
+		Slot:               1502,
+		VanguardShardInfos: []*types.VanguardShardInfo{verifiedSlots[0].ShardInfo},
+		PandoraHeaders:     []*eth1Types.Header{verifiedSlots[0].Header},
+	}
+	require.NoError(t, src.SaveEquivocation(equivocation))
+	require.NoError(t, src.SaveLatestFinalizedSlot(1400, 0))
+	require.NoError(t, src.SaveLatestEpoch(ctx))
+	_, err := src.Prune(ctx, &PruneConfig{Policy: PruneFinalized})
+	require.NoError(t, err)
+
+	var export bytes.Buffer
+	count, err := src.Export(ctx, &export)
+	require.NoError(t, err)
+	// header, consensus infos of epoch 0 to 2, verified slot 1400 to 1502 of shard 0 without the invalid slot and
+	// 1 to 20 of shard 1, the invalid, skipped and timed out slot, the verdicts, the equivocation and the pointers
+	// of both shards
+	assert.Equal(t, uint64(1+3+102+20+1+1+1+1+1+2), count)
+
+	dst := setupDB(t, true)
+	imported, err := dst.Import(ctx, bytes.NewReader(export.Bytes()))
+	require.NoError(t, err)
+	assert.Equal(t, count-1, imported)
+
+	consensusInfos, err := dst.ConsensusInfos(0)
+	require.NoError(t, err)
+	assert.DeepEqual(t, []*types.MinimalEpochConsensusInfo{
+		testutil.NewMinimalConsensusInfo(0),
+		testutil.NewMinimalConsensusInfo(1),
+		testutil.NewMinimalConsensusInfo(2),
+	}, consensusInfos)
+	for _, shardIndex := range []uint64{0, 1} {
+		want, err := src.VerifiedSlotInfos(0, shardIndex)
+		require.NoError(t, err)
+		got, err := dst.VerifiedSlotInfos(0, shardIndex)
+		require.NoError(t, err)
+		assert.DeepEqual(t, want, got, "shard %d", shardIndex)
+		assert.Equal(t, src.InMemoryLatestVerifiedSlot(shardIndex), dst.InMemoryLatestVerifiedSlot(shardIndex))
+		assert.Equal(t, src.InMemoryLatestVerifiedHeaderHash(shardIndex), dst.InMemoryLatestVerifiedHeaderHash(shardIndex))
+		assert.Equal(t, src.LatestFinalizedSlot(shardIndex), dst.LatestFinalizedSlot(shardIndex))
+	}
+	invalidSlotInfo, err := dst.InvalidSlotInfo(1501, 0)
+	require.NoError(t, err)
+	require.NotNil(t, invalidSlotInfo)
+	assert.Equal(t, types.ParentHashMismatch, invalidSlotInfo.Reason)
+	skippedSlotInfo, err := dst.SkippedSlotInfo(21, 1)
+	require.NoError(t, err)
+	require.NotNil(t, skippedSlotInfo)
+	timedOutSlotInfo, err := dst.TimedOutSlotInfo(22, 1)
+	require.NoError(t, err)
+	require.NotNil(t, timedOutSlotInfo)
+	assert.Equal(t, common.HexToHash("0x02"), timedOutSlotInfo.PandoraHeaderHash)
+	importedVerdicts, err := dst.Verdicts(1501, 0)
+	require.NoError(t, err)
+	assert.DeepEqual(t, verdicts, importedVerdicts)
+	header, err := dst.VerifiedHeader(1502, 0)
+	require.NoError(t, err)
+	require.NotNil(t, header)
+	assert.Equal(t, verifiedSlots[0].Header.Hash(), header.Hash())
+	shardInfo, err := dst.VerifiedShardInfo(1502, 0)
+	require.NoError(t, err)
+	require.NotNil(t, shardInfo)
+	assert.DeepEqual(t, verifiedSlots[0].ShardInfo.BlockHash, shardInfo.BlockHash)
+	equivocations, err := dst.Equivocations(0)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(equivocations))
+	assert.Equal(t, equivocation.Kind, equivocations[0].Kind)
+	assert.Equal(t, verifiedSlots[0].Header.Hash(), equivocations[0].PandoraHeaders[0].Hash())
+	assert.Equal(t, uint64(2), dst.GetLatestEpoch())
+	assert.Equal(t, uint64(2), dst.LatestSavedEpoch())
+
+	// an export is only imported into an empty database
+	_, err = dst.Import(ctx, bytes.NewReader(export.Bytes()))
+	assert.ErrorContains(t, "database is not empty", err)
+}
+
+func TestExportDatabase(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	dbPath := t.TempDir()
+	db, err := NewKVStore(ctx, dbPath, &Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.SaveConsensusInfo(ctx, testutil.NewMinimalConsensusInfo(0)))
+	require.NoError(t, db.CommitVerifiedSlots(0, linkedVerifiedSlots(common.Hash{}, 1, 2, 3)))
+	var storeExport bytes.Buffer
+	_, err = db.Export(ctx, &storeExport)
+	require.NoError(t, err)
+	require.NoError(t, db.Close())
+	stat, err := os.Stat(path.Join(dbPath, DatabaseFileName))
+	require.NoError(t, err)
+
+	// the closed database is exported without being changed
+	var export bytes.Buffer
+	count, err := ExportDatabase(ctx, dbPath, &export)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(1+1+3+1), count)
+	assert.Equal(t, storeExport.String(), export.String())
+	exportedStat, err := os.Stat(path.Join(dbPath, DatabaseFileName))
+	require.NoError(t, err)
+	assert.Equal(t, stat.ModTime(), exportedStat.ModTime())
+
+	_, err = ExportDatabase(ctx, t.TempDir(), &export)
+	assert.ErrorContains(t, "does not exist", err)
+}
+
+func TestStore_Import_Invalid(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	src := setupPruneDB(t, 0, map[uint64]uint64{0: 3})
+	var export bytes.Buffer
+	_, err := src.Export(ctx, &export)
+	require.NoError(t, err)
+	lines := strings.SplitAfter(export.String(), "\n")
+
+	tests := []struct {
+		name   string
+		export string
+		err    string
+	}{
+		{"empty", "", "export is empty"},
+		{"truncated", strings.Join(lines[:len(lines)-2], ""), "no checksum record"},
+		{
+			"changed record",
+			strings.Join(lines[:2], "") + strings.Replace(lines[2], `"slot":1`, `"slot":7`, 1) + strings.Join(lines[3:], ""),
+			"does not match expected checksum",
+		},
+		{"missing record", lines[0] + strings.Join(lines[2:], ""), "export has 5 records, expected 6"},
+		{"unknown version", strings.Replace(export.String(), `"version":1`, `"version":9`, 1), "format version 9 is not supported"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dst := setupDB(t, true)
+			_, err := dst.Import(ctx, strings.NewReader(tt.export))
+			assert.ErrorContains(t, tt.err, err)
+			// nothing is stored from an invalid export
+			slotInfo, err := dst.VerifiedSlotInfo(1, 0)
+			require.NoError(t, err)
+			assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+		})
+	}
+}
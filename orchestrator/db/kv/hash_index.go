@@ -0,0 +1,96 @@
+package kv
+
+import (
+	"bytes"
+
+	"github.com/boltdb/bolt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// SlotByPandoraHeaderHash returns the slot and shard of the verified or invalid slot info which holds the pandora
+// header hash. It returns nil when the hash is unknown or its slot is pruned.
+func (s *Store) SlotByPandoraHeaderHash(hash common.Hash) (*types.ShardSlot, error) {
+	return s.slotByHash(pandoraHeaderHashesBucket, hash)
+}
+
+// SlotByVanguardBlockHash returns the slot and shard of the verified or invalid slot info which holds the vanguard
+// block hash. It returns nil when the hash is unknown or its slot is pruned.
+func (s *Store) SlotByVanguardBlockHash(hash common.Hash) (*types.ShardSlot, error) {
+	return s.slotByHash(vanguardBlockHashesBucket, hash)
+}
+
+func (s *Store) slotByHash(bucket []byte, hash common.Hash) (*types.ShardSlot, error) {
+	var shardSlot *types.ShardSlot
+	err := s.db.View(func(tx *bolt.Tx) error {
+		slot, shardIndex, ok := parseSlotKey(tx.Bucket(bucket).Get(hash.Bytes()))
+		if !ok {
+			return nil
+		}
+		shardSlot = &types.ShardSlot{Slot: slot, ShardIndex: shardIndex}
+		return nil
+	})
+	return shardSlot, err
+}
+
+// indexSlotInfo points the pandora header hash and the vanguard block hash of the slot info to its slot. Empty
+// hashes are not indexed.
+func indexSlotInfo(tx *bolt.Tx, key []byte, slotInfo *types.SlotInfo) error {
+	if slotInfo.PandoraHeaderHash != EmptyHash {
+		if err := tx.Bucket(pandoraHeaderHashesBucket).Put(slotInfo.PandoraHeaderHash.Bytes(), key); err != nil {
+			return err
+		}
+	}
+	if slotInfo.VanguardBlockHash != EmptyHash {
+		return tx.Bucket(vanguardBlockHashesBucket).Put(slotInfo.VanguardBlockHash.Bytes(), key)
+	}
+	return nil
+}
+
+// unindexSlotInfo removes the hashes of the slot info from the indexes. Hashes which point to another slot by now
+// are kept.
+func unindexSlotInfo(tx *bolt.Tx, key []byte, slotInfo *types.SlotInfo) error {
+	for bucket, hash := range map[string]common.Hash{
+		string(pandoraHeaderHashesBucket): slotInfo.PandoraHeaderHash,
+		string(vanguardBlockHashesBucket): slotInfo.VanguardBlockHash,
+	} {
+		bkt := tx.Bucket([]byte(bucket))
+		if !bytes.Equal(bkt.Get(hash.Bytes()), key) {
+			continue
+		}
+		if err := bkt.Delete(hash.Bytes()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// unindexRecord removes the hashes of a stored verified or invalid slot info from the indexes. Records of other
+// buckets are not indexed.
+func unindexRecord(tx *bolt.Tx, bucket []byte, key []byte, value []byte) error {
+	switch {
+	case bytes.Equal(bucket, verifiedSlotInfosBucket):
+		var slotInfo *types.SlotInfo
+		if err := decode(value, &slotInfo); err != nil {
+			return err
+		}
+		return unindexSlotInfo(tx, key, slotInfo)
+	case bytes.Equal(bucket, invalidSlotInfosBucket):
+		var slotInfo *types.InvalidSlotInfo
+		if err := decode(value, &slotInfo); err != nil {
+			return err
+		}
+		return unindexSlotInfo(tx, key, &slotInfo.SlotInfo)
+	}
+	return nil
+}
+
+// unindexOverwritten removes the hashes of the slot info which is about to be overwritten at the key from the
+// indexes, so that the replaced hashes are no longer found
+func unindexOverwritten(tx *bolt.Tx, bucket []byte, key []byte) error {
+	value := tx.Bucket(bucket).Get(key)
+	if value == nil {
+		return nil
+	}
+	return unindexRecord(tx, bucket, key, value)
+}
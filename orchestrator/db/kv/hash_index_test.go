@@ -0,0 +1,136 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestStore_SlotByHash(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	slotInfo := &types.SlotInfo{
+		VanguardBlockHash: common.HexToHash("0x0a"),
+		PandoraHeaderHash: common.HexToHash("0x0b"),
+	}
+	require.NoError(t, db.CommitVerifiedSlots(1, []*types.VerifiedSlot{{Slot: 10, SlotInfo: slotInfo}}))
+	require.NoError(t, db.SaveInvalidSlotInfo(11, 0, &types.InvalidSlotInfo{
+		SlotInfo: types.SlotInfo{PandoraHeaderHash: common.HexToHash("0x0c")},
+		Reason:   types.StateRootMismatch,
+	}))
+
+	shardSlot, err := db.SlotByPandoraHeaderHash(slotInfo.PandoraHeaderHash)
+	require.NoError(t, err)
+	assert.DeepEqual(t, &types.ShardSlot{Slot: 10, ShardIndex: 1}, shardSlot)
+	shardSlot, err = db.SlotByVanguardBlockHash(slotInfo.VanguardBlockHash)
+	require.NoError(t, err)
+	assert.DeepEqual(t, &types.ShardSlot{Slot: 10, ShardIndex: 1}, shardSlot)
+	shardSlot, err = db.SlotByPandoraHeaderHash(common.HexToHash("0x0c"))
+	require.NoError(t, err)
+	assert.DeepEqual(t, &types.ShardSlot{Slot: 11}, shardSlot)
+
+	// empty and unknown hashes are not found
+	shardSlot, err = db.SlotByVanguardBlockHash(EmptyHash)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.ShardSlot)(nil), shardSlot)
+	shardSlot, err = db.SlotByPandoraHeaderHash(common.HexToHash("0x0d"))
+	require.NoError(t, err)
+	assert.Equal(t, (*types.ShardSlot)(nil), shardSlot)
+}
+
+func TestStore_SlotByHash_Revert(t *testing.T) {
+	t.Parallel()
+	db := setupPruneDB(t, 0, map[uint64]uint64{0: 10})
+
+	_, err := db.RevertVerifiedSlotInfos(8, 0)
+	require.NoError(t, err)
+	for slot := uint64(1); slot <= 10; slot++ {
+		shardSlot, err := db.SlotByPandoraHeaderHash(common.BytesToHash(bytesutil.Uint64ToBytesBigEndian(slot)))
+		require.NoError(t, err)
+		if slot < 8 {
+			assert.DeepEqual(t, &types.ShardSlot{Slot: slot}, shardSlot)
+		} else {
+			assert.Equal(t, (*types.ShardSlot)(nil), shardSlot)
+		}
+	}
+
+	// a hash which is indexed by a later invalid slot info is kept when its verified slot is reverted
+	hash := common.BytesToHash(bytesutil.Uint64ToBytesBigEndian(7))
+	require.NoError(t, db.SaveInvalidSlotInfo(12, 0, &types.InvalidSlotInfo{
+		SlotInfo: types.SlotInfo{PandoraHeaderHash: hash},
+		Reason:   types.ParentHashMismatch,
+	}))
+	_, err = db.RevertVerifiedSlotInfos(7, 0)
+	require.NoError(t, err)
+	shardSlot, err := db.SlotByPandoraHeaderHash(hash)
+	require.NoError(t, err)
+	assert.DeepEqual(t, &types.ShardSlot{Slot: 12}, shardSlot)
+}
+
+func TestStore_SlotByHash_Prune(t *testing.T) {
+	t.Parallel()
+	db := setupPruneDB(t, 0, map[uint64]uint64{0: 20})
+	require.NoError(t, db.SaveInvalidSlotInfo(21, 0, &types.InvalidSlotInfo{
+		SlotInfo: types.SlotInfo{PandoraHeaderHash: common.HexToHash("0xff")},
+		Reason:   types.StateRootMismatch,
+	}))
+	require.NoError(t, db.SaveLatestFinalizedSlot(15, 0))
+	_, err := db.Prune(context.Background(), &PruneConfig{Policy: PruneFinalized})
+	require.NoError(t, err)
+
+	for slot := uint64(1); slot <= 20; slot++ {
+		shardSlot, err := db.SlotByPandoraHeaderHash(common.BytesToHash(bytesutil.Uint64ToBytesBigEndian(slot)))
+		require.NoError(t, err)
+		if slot < 15 {
+			assert.Equal(t, (*types.ShardSlot)(nil), shardSlot)
+		} else {
+			assert.DeepEqual(t, &types.ShardSlot{Slot: slot}, shardSlot)
+		}
+	}
+	shardSlot, err := db.SlotByPandoraHeaderHash(common.HexToHash("0xff"))
+	require.NoError(t, err)
+	assert.DeepEqual(t, &types.ShardSlot{Slot: 21}, shardSlot)
+}
+
+func TestStore_SlotByHash_Overwrite(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	oldHash, newHash := common.HexToHash("0x0a"), common.HexToHash("0x0b")
+
+	// hashes of an overwritten slot info are no longer found
+	require.NoError(t, db.SaveVerifiedSlotInfos(0, map[uint64]*types.SlotInfo{1: {PandoraHeaderHash: oldHash}}))
+	require.NoError(t, db.SaveVerifiedSlotInfos(0, map[uint64]*types.SlotInfo{1: {PandoraHeaderHash: newHash}}))
+	require.NoError(t, db.SaveVerifiedSlotInfos(0, map[uint64]*types.SlotInfo{2: {VanguardBlockHash: oldHash}}))
+	require.NoError(t, db.SaveVerifiedSlotInfos(0, map[uint64]*types.SlotInfo{2: {VanguardBlockHash: newHash}}))
+	require.NoError(t, db.SaveInvalidSlotInfo(3, 0, &types.InvalidSlotInfo{
+		SlotInfo: types.SlotInfo{PandoraHeaderHash: common.HexToHash("0x0c")},
+		Reason:   types.StateRootMismatch,
+	}))
+	require.NoError(t, db.SaveInvalidSlotInfo(3, 0, &types.InvalidSlotInfo{
+		SlotInfo: types.SlotInfo{PandoraHeaderHash: common.HexToHash("0x0d")},
+		Reason:   types.StateRootMismatch,
+	}))
+
+	for _, hash := range []common.Hash{oldHash, common.HexToHash("0x0c")} {
+		shardSlot, err := db.SlotByPandoraHeaderHash(hash)
+		require.NoError(t, err)
+		assert.Equal(t, (*types.ShardSlot)(nil), shardSlot)
+	}
+	shardSlot, err := db.SlotByVanguardBlockHash(oldHash)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.ShardSlot)(nil), shardSlot)
+	shardSlot, err = db.SlotByPandoraHeaderHash(newHash)
+	require.NoError(t, err)
+	assert.DeepEqual(t, &types.ShardSlot{Slot: 1}, shardSlot)
+	shardSlot, err = db.SlotByVanguardBlockHash(newHash)
+	require.NoError(t, err)
+	assert.DeepEqual(t, &types.ShardSlot{Slot: 2}, shardSlot)
+	shardSlot, err = db.SlotByPandoraHeaderHash(common.HexToHash("0x0d"))
+	require.NoError(t, err)
+	assert.DeepEqual(t, &types.ShardSlot{Slot: 3}, shardSlot)
+}
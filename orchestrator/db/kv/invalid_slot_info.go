@@ -2,16 +2,15 @@ package kv
 
 import (
 	"github.com/boltdb/bolt"
-	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
 )
 
 // InvalidSlotInfo
-func (s *Store) InvalidSlotInfo(slot uint64) (*types.SlotInfo, error) {
-	var slotInfo *types.SlotInfo
+func (s *Store) InvalidSlotInfo(slot uint64, shardIndex uint64) (*types.InvalidSlotInfo, error) {
+	var slotInfo *types.InvalidSlotInfo
 	err := s.db.View(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(invalidSlotInfosBucket)
-		key := bytesutil.Uint64ToBytesBigEndian(slot)
+		key := slotKey(slot, shardIndex)
 		value := bkt.Get(key[:])
 		if value == nil {
 			return nil
@@ -21,22 +20,25 @@ func (s *Store) InvalidSlotInfo(slot uint64) (*types.SlotInfo, error) {
 	return slotInfo, err
 }
 
-// SaveInvalidSlotInfo
-func (s *Store) SaveInvalidSlotInfo(slot uint64, slotInfo *types.SlotInfo) error {
+// SaveInvalidSlotInfo stores the slot info along with the reason why the slot is invalid
+func (s *Store) SaveInvalidSlotInfo(slot uint64, shardIndex uint64, slotInfo *types.InvalidSlotInfo) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
 	// storing consensus info into cache and db
 	return s.db.Update(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(invalidSlotInfosBucket)
-		slotBytes := bytesutil.Uint64ToBytesBigEndian(slot)
+		slotBytes := slotKey(slot, shardIndex)
 		enc, err := encode(slotInfo)
 		if err != nil {
 			return err
 		}
+		if err := unindexOverwritten(tx, invalidSlotInfosBucket, slotBytes); err != nil {
+			return err
+		}
 		if err := bkt.Put(slotBytes, enc); err != nil {
 			return err
 		}
-		return nil
+		return indexSlotInfo(tx, slotBytes, &slotInfo.SlotInfo)
 	})
 }
@@ -0,0 +1,30 @@
+package kv
+
+import (
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+	"testing"
+)
+
+func TestStore_InvalidSlotInfo(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	slotInfo := &types.InvalidSlotInfo{
+		SlotInfo: types.SlotInfo{
+			VanguardBlockHash: eth1Types.EmptyRootHash,
+			PandoraHeaderHash: eth1Types.EmptyRootHash,
+		},
+		Reason: types.StateRootMismatch,
+	}
+	require.NoError(t, db.SaveInvalidSlotInfo(10, 0, slotInfo))
+
+	retrievedSlotInfo, err := db.InvalidSlotInfo(10, 0)
+	require.NoError(t, err)
+	assert.DeepEqual(t, slotInfo, retrievedSlotInfo)
+
+	retrievedSlotInfo, err = db.InvalidSlotInfo(11, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.InvalidSlotInfo)(nil), retrievedSlotInfo)
+}
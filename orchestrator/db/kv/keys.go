@@ -0,0 +1,23 @@
+package kv
+
+import "github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
+
+// slotKey returns the key of the slot info of a pandora shard. Slot infos of shard 0 keep the plain slot key so that
+// databases which are created before multiple shards are still readable.
+func slotKey(slot uint64, shardIndex uint64) []byte {
+	key := bytesutil.Uint64ToBytesBigEndian(slot)
+	if shardIndex == 0 {
+		return key
+	}
+	return append(key, bytesutil.Uint64ToBytesBigEndian(shardIndex)...)
+}
+
+// shardKey returns the key of the shard specific latest info. Shard 0 keeps the given key.
+func shardKey(key []byte, shardIndex uint64) []byte {
+	if shardIndex == 0 {
+		return key
+	}
+	shardKey := make([]byte, 0, len(key)+8)
+	shardKey = append(shardKey, key...)
+	return append(shardKey, bytesutil.Uint64ToBytesBigEndian(shardIndex)...)
+}
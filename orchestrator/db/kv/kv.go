@@ -38,10 +38,10 @@ type Store struct {
 	consensusInfoCache    *ristretto.Cache
 	verifiedSlotInfoCache *ristretto.Cache
 
-	// Latest information need to be stored into db
+	// Latest information need to be stored into db. Verified slot and header hash are kept per shard index.
 	latestEpoch        uint64
-	latestVerifiedSlot uint64
-	latestHeaderHash   common.Hash
+	latestVerifiedSlot map[uint64]uint64
+	latestHeaderHash   map[uint64]common.Hash
 	latestVanBlockHash []byte
 	// There should be mutex in store
 	sync.Mutex
@@ -99,18 +99,43 @@ func NewKVStore(ctx context.Context, dirPath string, config *Config) (*Store, er
 		databasePath:          dirPath,
 		consensusInfoCache:    consensusInfoCache,
 		verifiedSlotInfoCache: verifiedSlotInfoCache,
+		latestVerifiedSlot:    make(map[uint64]uint64),
+		latestHeaderHash:      make(map[uint64]common.Hash),
 	}
 
 	if err := kv.db.Update(func(tx *bolt.Tx) error {
-		return createBuckets(
+		// a brand new db starts with the current schema and needs no migration
+		isNewDB := tx.Bucket(consensusInfosBucket) == nil
+		if err := createBuckets(
 			tx,
 			consensusInfosBucket,
 			verifiedSlotInfosBucket,
 			invalidSlotInfosBucket,
-		)
+			skippedSlotInfosBucket,
+			timedOutSlotInfosBucket,
+			equivocationsBucket,
+			verdictsBucket,
+			pendingHeadersBucket,
+			pendingShardInfosBucket,
+			metadataBucket,
+			pandoraHeaderHashesBucket,
+			vanguardBlockHashesBucket,
+			verifiedHeadersBucket,
+			verifiedShardInfosBucket,
+		); err != nil {
+			return err
+		}
+		if isNewDB {
+			return saveSchemaVersion(tx, SchemaVersion)
+		}
+		return nil
 	}); err != nil {
 		return nil, err
 	}
+	if err := kv.runMigrations(); err != nil {
+		_ = boltDB.Close()
+		return nil, errors.Wrap(err, "could not migrate database")
+	}
 	// Retrieve initial data from DB
 	kv.initLatestDataFromDB()
 
@@ -135,14 +160,16 @@ func (s *Store) Close() error {
 		return err
 	}
 
-	err = s.SaveLatestVerifiedSlot(s.ctx)
-	if nil != err {
-		return err
-	}
+	for _, shardIndex := range s.shardIndexes() {
+		err = s.SaveLatestVerifiedSlot(s.ctx, shardIndex)
+		if nil != err {
+			return err
+		}
 
-	err = s.SaveLatestVerifiedHeaderHash()
-	if err != nil {
-		return err
+		err = s.SaveLatestVerifiedHeaderHash(shardIndex)
+		if err != nil {
+			return err
+		}
 	}
 	log.Info("Received cancelled context, closing db")
 	return s.db.Close()
@@ -157,11 +184,24 @@ func (s *Store) DatabasePath() string {
 func (s *Store) initLatestDataFromDB() {
 	// Retrieve latest saved epoch number from db
 	s.latestEpoch = s.LatestSavedEpoch()
-	s.latestVerifiedSlot = s.LatestSavedVerifiedSlot()
-	s.latestHeaderHash = s.LatestVerifiedHeaderHash()
+	// Latest info of other shards is retrieved when the shard is accessed for the first time
+	s.latestVerifiedSlot[0] = s.LatestSavedVerifiedSlot(0)
+	s.latestHeaderHash[0] = s.LatestVerifiedHeaderHash(0)
 	log.WithField("latestSavedEpoch", s.latestEpoch).WithField(
-		"latestVerifiedSlot", s.latestVerifiedSlot).WithField(
-		"latestHeaderHash", s.latestHeaderHash).Debug("latest saved info from db")
+		"latestVerifiedSlot", s.latestVerifiedSlot[0]).WithField(
+		"latestHeaderHash", s.latestHeaderHash[0]).Debug("latest saved info from db")
+}
+
+// shardIndexes returns the shard indexes whose latest info is kept in memory
+func (s *Store) shardIndexes() []uint64 {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	shardIndexes := make([]uint64, 0, len(s.latestVerifiedSlot))
+	for shardIndex := range s.latestVerifiedSlot {
+		shardIndexes = append(shardIndexes, shardIndex)
+	}
+	return shardIndexes
 }
 
 // createBuckets
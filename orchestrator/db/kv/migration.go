@@ -0,0 +1,201 @@
+package kv
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/params"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// migration upgrades the stored data of the previous schema version to its own version
+type migration struct {
+	version uint64
+	name    string
+	migrate func(tx *bolt.Tx) error
+}
+
+// migrations are applied in order when an older database is opened. A migration with the next version must be
+// appended whenever the stored format changes. Databases without schema version are at version 0.
+var migrations = []*migration{
+	{version: 1, name: "invalid-slot-reason", migrate: migrateInvalidSlotReason},
+	{version: 2, name: "binary-encoding", migrate: migrateBinaryEncoding},
+	{version: 3, name: "hash-indexes", migrate: migrateHashIndexes},
+}
+
+// SchemaVersion is the version of the stored format which this store reads and writes
+var SchemaVersion = migrations[len(migrations)-1].version
+
+// Migration describes a pending migration of a database
+type Migration struct {
+	Version uint64
+	Name    string
+}
+
+// runMigrations applies the pending migrations one by one. Every migration stores its version in the same
+// transaction, so an interrupted migration is applied again on the next start.
+func (s *Store) runMigrations() error {
+	var version uint64
+	if err := s.db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		return nil
+	}); err != nil {
+		return err
+	}
+	if version > SchemaVersion {
+		return fmt.Errorf("database schema version %d is newer than supported version %d", version, SchemaVersion)
+	}
+
+	for _, m := range pendingMigrations(version) {
+		start := time.Now()
+		if err := s.db.Update(func(tx *bolt.Tx) error {
+			if err := m.migrate(tx); err != nil {
+				return err
+			}
+			return saveSchemaVersion(tx, m.version)
+		}); err != nil {
+			return fmt.Errorf("migration %d (%s) failed: %v", m.version, m.name, err)
+		}
+		log.WithField("version", m.version).WithField("name", m.name).
+			WithField("elapsed", time.Since(start)).Info("Applied database migration")
+	}
+	return nil
+}
+
+// PendingMigrations returns the migrations which would be applied when the database in the directory is opened. The
+// database is opened read only and is not changed.
+func PendingMigrations(dirPath string) ([]*Migration, error) {
+	datafile := path.Join(dirPath, DatabaseFileName)
+	if _, err := os.Stat(datafile); os.IsNotExist(err) {
+		return nil, nil
+	}
+	boltDB, err := bolt.Open(datafile, params.OrchestratorIoConfig().ReadWritePermissions, &bolt.Options{
+		Timeout:  1 * time.Second,
+		ReadOnly: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	defer boltDB.Close()
+
+	var version uint64
+	if err := boltDB.View(func(tx *bolt.Tx) error {
+		// a db without any bucket is created from scratch with the current schema
+		if tx.Bucket(consensusInfosBucket) == nil {
+			version = SchemaVersion
+			return nil
+		}
+		version = schemaVersion(tx)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+	if version > SchemaVersion {
+		return nil, fmt.Errorf("database schema version %d is newer than supported version %d", version, SchemaVersion)
+	}
+
+	pending := make([]*Migration, 0)
+	for _, m := range pendingMigrations(version) {
+		pending = append(pending, &Migration{Version: m.version, Name: m.name})
+	}
+	return pending, nil
+}
+
+// pendingMigrations returns the migrations which are newer than the given schema version in order
+func pendingMigrations(version uint64) []*migration {
+	pending := make([]*migration, 0)
+	for _, m := range migrations {
+		if m.version > version {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
+// schemaVersion returns the stored schema version. It is zero for databases which are created before versioning.
+func schemaVersion(tx *bolt.Tx) uint64 {
+	bkt := tx.Bucket(metadataBucket)
+	if bkt == nil {
+		return 0
+	}
+	versionBytes := bkt.Get(schemaVersionKey)
+	if versionBytes == nil {
+		return 0
+	}
+	return bytesutil.BytesToUint64BigEndian(versionBytes)
+}
+
+func saveSchemaVersion(tx *bolt.Tx, version uint64) error {
+	return tx.Bucket(metadataBucket).Put(schemaVersionKey, bytesutil.Uint64ToBytesBigEndian(version))
+}
+
+// migrateInvalidSlotReason sets UnknownMismatch as the reason of invalid slot infos which were stored as plain slot
+// infos before the reason of the verification failure was recorded
+func migrateInvalidSlotReason(tx *bolt.Tx) error {
+	bkt := tx.Bucket(invalidSlotInfosBucket)
+	migrated := make(map[string][]byte)
+	if err := bkt.ForEach(func(key, value []byte) error {
+		var slotInfo *types.InvalidSlotInfo
+		if err := decode(value, &slotInfo); err != nil {
+			return err
+		}
+		if slotInfo.Reason != types.NoMismatch {
+			return nil
+		}
+		slotInfo.Reason = types.UnknownMismatch
+		enc, err := encode(slotInfo)
+		if err != nil {
+			return err
+		}
+		migrated[string(key)] = enc
+		return nil
+	}); err != nil {
+		return err
+	}
+	// bucket must not be changed while iterating over it
+	for key, enc := range migrated {
+		if err := bkt.Put([]byte(key), enc); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// migrateBinaryEncoding marks the switch from JSON to binary records. Stored JSON records stay readable and are
+// replaced by binary records when they are written again, so nothing is rewritten. Releases before the schema
+// version do not read it and fail on the first binary record, so the database can not be downgraded to them.
+func migrateBinaryEncoding(tx *bolt.Tx) error {
+	return nil
+}
+
+// migrateHashIndexes fills the pandora header hash and vanguard block hash indexes from the stored invalid and
+// verified slot infos. Verified slot infos are indexed last, so they win when both hold the same hash.
+func migrateHashIndexes(tx *bolt.Tx) error {
+	if err := tx.Bucket(invalidSlotInfosBucket).ForEach(func(key, value []byte) error {
+		if _, _, ok := parseSlotKey(key); !ok {
+			return nil
+		}
+		var slotInfo *types.InvalidSlotInfo
+		if err := decode(value, &slotInfo); err != nil {
+			return err
+		}
+		return indexSlotInfo(tx, key, &slotInfo.SlotInfo)
+	}); err != nil {
+		return err
+	}
+	return tx.Bucket(verifiedSlotInfosBucket).ForEach(func(key, value []byte) error {
+		// latest verified slot and header hash pointers are no slot infos
+		if _, _, ok := parseSlotKey(key); !ok {
+			return nil
+		}
+		var slotInfo *types.SlotInfo
+		if err := decode(value, &slotInfo); err != nil {
+			return err
+		}
+		return indexSlotInfo(tx, key, slotInfo)
+	})
+}
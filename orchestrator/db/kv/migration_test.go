@@ -0,0 +1,114 @@
+package kv
+
+import (
+	"context"
+	"io/ioutil"
+	"math/big"
+	"path"
+	"testing"
+
+	"github.com/boltdb/bolt"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// copyFixture copies the database fixture of the given schema version into a temporary directory. The v0 fixture is
+// written by the store before schema versioning. It holds consensus infos of epoch 0 and 1, verified slot infos of
+// slot 1 to 3 and an invalid slot info of slot 4 without reason.
+func copyFixture(t *testing.T, version string) string {
+	data, err := ioutil.ReadFile(path.Join("testdata", "migrations", version, DatabaseFileName))
+	require.NoError(t, err)
+	dirPath := t.TempDir()
+	require.NoError(t, ioutil.WriteFile(path.Join(dirPath, DatabaseFileName), data, 0600))
+	return dirPath
+}
+
+func storedSchemaVersion(t *testing.T, db *Store) uint64 {
+	var version uint64
+	require.NoError(t, db.db.View(func(tx *bolt.Tx) error {
+		version = schemaVersion(tx)
+		return nil
+	}))
+	return version
+}
+
+func TestStore_MigrateFromV0(t *testing.T) {
+	dirPath := copyFixture(t, "v0")
+
+	pending, err := PendingMigrations(dirPath)
+	require.NoError(t, err)
+	assert.DeepEqual(t, []*Migration{
+		{Version: 1, Name: "invalid-slot-reason"},
+		{Version: 2, Name: "binary-encoding"},
+		{Version: 3, Name: "hash-indexes"},
+	}, pending)
+	// dry run does not change the db
+	pending, err = PendingMigrations(dirPath)
+	require.NoError(t, err)
+	assert.Equal(t, 3, len(pending))
+
+	db, err := NewKVStore(context.Background(), dirPath, &Config{})
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, storedSchemaVersion(t, db))
+
+	for epoch := uint64(0); epoch < 2; epoch++ {
+		consensusInfo, err := db.ConsensusInfo(context.Background(), epoch)
+		require.NoError(t, err)
+		require.NotNil(t, consensusInfo)
+		assert.Equal(t, epoch, consensusInfo.Epoch)
+	}
+	for slot := uint64(1); slot <= 3; slot++ {
+		slotInfo, err := db.VerifiedSlotInfo(slot, 0)
+		require.NoError(t, err)
+		require.NotNil(t, slotInfo)
+		assert.Equal(t, common.BigToHash(new(big.Int).SetUint64(100+slot)), slotInfo.PandoraHeaderHash)
+	}
+	assert.Equal(t, uint64(3), db.InMemoryLatestVerifiedSlot(0))
+
+	invalidSlotInfo, err := db.InvalidSlotInfo(4, 0)
+	require.NoError(t, err)
+	require.NotNil(t, invalidSlotInfo)
+	assert.Equal(t, types.UnknownMismatch, invalidSlotInfo.Reason)
+	assert.Equal(t, common.BigToHash(new(big.Int).SetUint64(104)), invalidSlotInfo.PandoraHeaderHash)
+
+	// stored slot infos are found by their hashes
+	for slot := uint64(1); slot <= 4; slot++ {
+		shardSlot, err := db.SlotByPandoraHeaderHash(common.BigToHash(new(big.Int).SetUint64(100 + slot)))
+		require.NoError(t, err)
+		assert.DeepEqual(t, &types.ShardSlot{Slot: slot}, shardSlot)
+	}
+	require.NoError(t, db.Close())
+
+	pending, err = PendingMigrations(dirPath)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(pending))
+}
+
+func TestStore_NewDBSchemaVersion(t *testing.T) {
+	dirPath := t.TempDir()
+	db, err := NewKVStore(context.Background(), dirPath, &Config{})
+	require.NoError(t, err)
+	assert.Equal(t, SchemaVersion, storedSchemaVersion(t, db))
+	require.NoError(t, db.Close())
+
+	pending, err := PendingMigrations(dirPath)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(pending))
+}
+
+func TestStore_NewerSchemaVersion(t *testing.T) {
+	dirPath := t.TempDir()
+	db, err := NewKVStore(context.Background(), dirPath, &Config{})
+	require.NoError(t, err)
+	require.NoError(t, db.db.Update(func(tx *bolt.Tx) error {
+		return saveSchemaVersion(tx, SchemaVersion+1)
+	}))
+	require.NoError(t, db.Close())
+
+	_, err = PendingMigrations(dirPath)
+	assert.ErrorContains(t, "is newer than supported version", err)
+	_, err = NewKVStore(context.Background(), dirPath, &Config{})
+	assert.ErrorContains(t, "is newer than supported version", err)
+}
@@ -0,0 +1,92 @@
+package kv
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// PendingHeaders returns the pandora headers which are waiting for their vanguard shard info
+func (s *Store) PendingHeaders() ([]*types.PandoraHeaderInfo, error) {
+	headerInfos := make([]*types.PandoraHeaderInfo, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(pendingHeadersBucket)
+		return bkt.ForEach(func(key, value []byte) error {
+			var headerInfo *types.PandoraHeaderInfo
+			if err := decode(value, &headerInfo); err != nil {
+				return err
+			}
+			headerInfos = append(headerInfos, headerInfo)
+			return nil
+		})
+	})
+	return headerInfos, err
+}
+
+// SavePendingHeader stores a pandora header until its slot is decided
+func (s *Store) SavePendingHeader(headerInfo *types.PandoraHeaderInfo) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(pendingHeadersBucket)
+		enc, err := encode(headerInfo)
+		if err != nil {
+			return err
+		}
+		return bkt.Put(slotKey(headerInfo.Slot, headerInfo.ShardIndex), enc)
+	})
+}
+
+// DeletePendingHeader removes the pending pandora header of the slot
+func (s *Store) DeletePendingHeader(slot uint64, shardIndex uint64) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(pendingHeadersBucket)
+		return bkt.Delete(slotKey(slot, shardIndex))
+	})
+}
+
+// PendingShardInfos returns the vanguard shard infos which are waiting for their pandora header
+func (s *Store) PendingShardInfos() ([]*types.VanguardShardInfo, error) {
+	shardInfos := make([]*types.VanguardShardInfo, 0)
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(pendingShardInfosBucket)
+		return bkt.ForEach(func(key, value []byte) error {
+			var shardInfo *types.VanguardShardInfo
+			if err := decode(value, &shardInfo); err != nil {
+				return err
+			}
+			shardInfos = append(shardInfos, shardInfo)
+			return nil
+		})
+	})
+	return shardInfos, err
+}
+
+// SavePendingShardInfo stores a vanguard shard info until its slot is decided
+func (s *Store) SavePendingShardInfo(shardInfo *types.VanguardShardInfo) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(pendingShardInfosBucket)
+		enc, err := encode(shardInfo)
+		if err != nil {
+			return err
+		}
+		return bkt.Put(slotKey(shardInfo.Slot, shardInfo.ShardIndex), enc)
+	})
+}
+
+// DeletePendingShardInfo removes the pending vanguard shard info of the slot
+func (s *Store) DeletePendingShardInfo(slot uint64, shardIndex uint64) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(pendingShardInfosBucket)
+		return bkt.Delete(slotKey(slot, shardIndex))
+	})
+}
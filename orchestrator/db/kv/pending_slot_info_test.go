@@ -0,0 +1,51 @@
+package kv
+
+import (
+	"testing"
+
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestStore_PendingHeaders(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	headerInfos := []*types.PandoraHeaderInfo{
+		{Slot: 1, ShardIndex: 0, Header: testutil.NewEth1Header(1)},
+		{Slot: 1, ShardIndex: 1, Header: testutil.NewEth1Header(1)},
+		{Slot: 2, ShardIndex: 0, Header: testutil.NewEth1Header(2)},
+	}
+	for _, headerInfo := range headerInfos {
+		require.NoError(t, db.SavePendingHeader(headerInfo))
+	}
+	require.NoError(t, db.DeletePendingHeader(1, 0))
+
+	retrievedHeaderInfos, err := db.PendingHeaders()
+	require.NoError(t, err)
+	require.Equal(t, 2, len(retrievedHeaderInfos))
+	assert.DeepEqual(t, headerInfos[1].Header.Hash(), retrievedHeaderInfos[0].Header.Hash())
+	assert.Equal(t, uint64(1), retrievedHeaderInfos[0].ShardIndex)
+	assert.DeepEqual(t, headerInfos[2].Header.Hash(), retrievedHeaderInfos[1].Header.Hash())
+}
+
+func TestStore_PendingShardInfos(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	shardInfos := []*types.VanguardShardInfo{
+		testutil.NewVanguardShardInfo(1, testutil.NewEth1Header(1)),
+		testutil.NewVanguardShardInfo(2, testutil.NewEth1Header(2)),
+	}
+	for _, shardInfo := range shardInfos {
+		require.NoError(t, db.SavePendingShardInfo(shardInfo))
+	}
+	require.NoError(t, db.DeletePendingShardInfo(1, 0))
+
+	retrievedShardInfos, err := db.PendingShardInfos()
+	require.NoError(t, err)
+	require.Equal(t, 1, len(retrievedShardInfos))
+	assert.Equal(t, uint64(2), retrievedShardInfos[0].Slot)
+	assert.DeepEqual(t, shardInfos[1].BlockHash, retrievedShardInfos[0].BlockHash)
+	assert.DeepEqual(t, shardInfos[1].ShardInfo.Hash, retrievedShardInfos[0].ShardInfo.Hash)
+}
@@ -0,0 +1,414 @@
+package kv
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/boltdb/bolt"
+	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// PrunePolicy decides which historical data is removed by pruning
+type PrunePolicy string
+
+const (
+	// PruneNone keeps all data
+	PruneNone PrunePolicy = "none"
+	// PruneEpochs keeps the data of the latest epochs
+	PruneEpochs PrunePolicy = "epochs"
+	// PruneFinalized keeps the data from the latest finalized slot on
+	PruneFinalized PrunePolicy = "finalized"
+)
+
+// DefaultPruneBatchSize is the maximum number of records which are removed in one transaction
+const DefaultPruneBatchSize = 1000
+
+var (
+	// slot buckets whose records are pruned. Equivocations are kept as evidence.
+	prunedSlotBuckets = [][]byte{
+		verifiedSlotInfosBucket,
+		invalidSlotInfosBucket,
+		skippedSlotInfosBucket,
+		timedOutSlotInfosBucket,
+		verdictsBucket,
+	}
+	// payload buckets of verified slots, which may be pruned before their slot infos
+	prunedPayloadBuckets = [][]byte{
+		verifiedHeadersBucket,
+		verifiedShardInfosBucket,
+	}
+)
+
+// PruneConfig defines which data is pruned and how often
+type PruneConfig struct {
+	Policy PrunePolicy
+	// RetainEpochs is the number of latest epochs which are kept by the epochs policy
+	RetainEpochs uint64
+	// PayloadRetainEpochs is the number of latest epochs whose verified headers and shard infos are kept. Zero keeps
+	// them as long as their slot infos.
+	PayloadRetainEpochs uint64
+	Interval            time.Duration
+	BatchSize           int
+}
+
+func (cfg *PruneConfig) validate() error {
+	switch cfg.Policy {
+	case PruneNone, PruneFinalized:
+	case PruneEpochs:
+		if cfg.RetainEpochs == 0 {
+			return fmt.Errorf("prune policy %s needs at least one retained epoch", cfg.Policy)
+		}
+	default:
+		return fmt.Errorf("unknown prune policy %q", cfg.Policy)
+	}
+	return nil
+}
+
+func (cfg *PruneConfig) batchSize() int {
+	if cfg.BatchSize <= 0 {
+		return DefaultPruneBatchSize
+	}
+	return cfg.BatchSize
+}
+
+// Prune removes the verified, invalid, skipped and timed out slot infos, verdicts, verified payloads and consensus
+// infos which are older than the policy retains. Verified payloads are also removed when they are older than the
+// payload retention. Latest verified slots are never removed. Records are removed in transactions of at
+// most the batch size, so that verification is not blocked for long. It returns the number of removed records.
+func (s *Store) Prune(ctx context.Context, cfg *PruneConfig) (int, error) {
+	if err := cfg.validate(); err != nil {
+		return 0, err
+	}
+	if cfg.Policy == PruneNone && cfg.PayloadRetainEpochs == 0 {
+		return 0, nil
+	}
+	shardIndexes, err := s.storedShardIndexes()
+	if err != nil {
+		return 0, err
+	}
+
+	slotCutoffs := make(map[uint64]uint64, len(shardIndexes))
+	payloadCutoffs := make(map[uint64]uint64, len(shardIndexes))
+	for _, shardIndex := range shardIndexes {
+		slotCutoffs[shardIndex] = s.slotCutoff(cfg, shardIndex)
+		payloadCutoffs[shardIndex] = s.payloadCutoff(cfg, shardIndex, slotCutoffs[shardIndex])
+	}
+	// consensus infos are kept as long as a shard may still verify slots of their epoch
+	epochCutoff := uint64(0)
+	for i, shardIndex := range shardIndexes {
+		if epoch := slotCutoffs[shardIndex] / types.SlotsPerEpoch; i == 0 || epoch < epochCutoff {
+			epochCutoff = epoch
+		}
+	}
+	// readers skip the pruned ranges from now on, also when pruning is interrupted
+	if err := s.saveRetainedBoundaries(epochCutoff, slotCutoffs); err != nil {
+		return 0, err
+	}
+
+	removed := 0
+	for _, bucket := range prunedSlotBuckets {
+		count, err := s.pruneSlots(ctx, bucket, slotCutoffs, cfg.batchSize())
+		removed += count
+		if err != nil {
+			return removed, err
+		}
+	}
+	for _, bucket := range prunedPayloadBuckets {
+		count, err := s.pruneSlots(ctx, bucket, payloadCutoffs, cfg.batchSize())
+		removed += count
+		if err != nil {
+			return removed, err
+		}
+	}
+	count, err := s.pruneConsensusInfos(ctx, epochCutoff, cfg.batchSize())
+	return removed + count, err
+}
+
+// slotCutoff returns the first slot of the shard which is retained by the policy
+func (s *Store) slotCutoff(cfg *PruneConfig, shardIndex uint64) uint64 {
+	latestVerifiedSlot := s.InMemoryLatestVerifiedSlot(shardIndex)
+	cutoff := uint64(0)
+	switch cfg.Policy {
+	case PruneEpochs:
+		cutoff = s.retainedEpochsStart(cfg.RetainEpochs)
+	case PruneFinalized:
+		cutoff = s.LatestFinalizedSlot(shardIndex)
+	}
+	if cutoff > latestVerifiedSlot {
+		return latestVerifiedSlot
+	}
+	return cutoff
+}
+
+// payloadCutoff returns the first slot of the shard whose verified payload is retained. Payloads are never kept
+// longer than their slot infos.
+func (s *Store) payloadCutoff(cfg *PruneConfig, shardIndex uint64, slotCutoff uint64) uint64 {
+	if cfg.PayloadRetainEpochs == 0 {
+		return slotCutoff
+	}
+	cutoff := s.retainedEpochsStart(cfg.PayloadRetainEpochs)
+	if latestVerifiedSlot := s.InMemoryLatestVerifiedSlot(shardIndex); cutoff > latestVerifiedSlot {
+		cutoff = latestVerifiedSlot
+	}
+	if cutoff < slotCutoff {
+		return slotCutoff
+	}
+	return cutoff
+}
+
+// retainedEpochsStart returns the first slot of the latest epochs. It is zero while there are not more epochs.
+func (s *Store) retainedEpochsStart(epochs uint64) uint64 {
+	if latestEpoch := s.GetLatestEpoch(); latestEpoch >= epochs {
+		return (latestEpoch - epochs + 1) * types.SlotsPerEpoch
+	}
+	return 0
+}
+
+// storedShardIndexes returns the shards which have a stored latest verified slot or are known in memory
+func (s *Store) storedShardIndexes() ([]uint64, error) {
+	known := make(map[uint64]bool)
+	for _, shardIndex := range s.shardIndexes() {
+		known[shardIndex] = true
+	}
+	err := s.db.View(func(tx *bolt.Tx) error {
+		for _, shardIndex := range savedShardIndexes(tx) {
+			known[shardIndex] = true
+		}
+		return nil
+	})
+	shardIndexes := make([]uint64, 0, len(known))
+	for shardIndex := range known {
+		shardIndexes = append(shardIndexes, shardIndex)
+	}
+	return shardIndexes, err
+}
+
+// saveRetainedBoundaries stores the first retained epoch and the first retained slot of every shard. Boundaries
+// only move forward.
+func (s *Store) saveRetainedBoundaries(epochCutoff uint64, slotCutoffs map[uint64]uint64) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(metadataBucket)
+		if epochCutoff > firstRetainedEpoch(tx) {
+			if err := bkt.Put(firstRetainedEpochKey, bytesutil.Uint64ToBytesBigEndian(epochCutoff)); err != nil {
+				return err
+			}
+		}
+		for shardIndex, slotCutoff := range slotCutoffs {
+			if slotCutoff <= firstRetainedSlot(tx, shardIndex) {
+				continue
+			}
+			key := shardKey(firstRetainedSlotKey, shardIndex)
+			if err := bkt.Put(key, bytesutil.Uint64ToBytesBigEndian(slotCutoff)); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// pruneSlots removes the records of the bucket below the cutoff of their shard. Records of unknown shards are kept.
+func (s *Store) pruneSlots(ctx context.Context, bucket []byte, slotCutoffs map[uint64]uint64, batchSize int) (int, error) {
+	maxCutoff := uint64(0)
+	for _, slotCutoff := range slotCutoffs {
+		if slotCutoff > maxCutoff {
+			maxCutoff = slotCutoff
+		}
+	}
+
+	removed := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+		keys := make([][]byte, 0, batchSize)
+		s.Mutex.Lock()
+		err := s.db.Update(func(tx *bolt.Tx) error {
+			bkt := tx.Bucket(bucket)
+			c := bkt.Cursor()
+			// keys start with the big endian slot, so the scan stops at the highest cutoff
+			for k, _ := c.First(); k != nil && len(keys) < batchSize; k, _ = c.Next() {
+				slot, shardIndex, ok := parseSlotKey(k)
+				if !ok {
+					continue
+				}
+				if slot >= maxCutoff {
+					break
+				}
+				if slotCutoff, exists := slotCutoffs[shardIndex]; !exists || slot >= slotCutoff {
+					continue
+				}
+				keys = append(keys, append([]byte{}, k...))
+			}
+			for _, key := range keys {
+				// the hashes of the removed slot infos are no longer found
+				if err := unindexRecord(tx, bucket, key, bkt.Get(key)); err != nil {
+					return err
+				}
+				if err := bkt.Delete(key); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		s.Mutex.Unlock()
+		if err != nil {
+			return removed, err
+		}
+		if bytes.Equal(bucket, verifiedSlotInfosBucket) {
+			for _, key := range keys {
+				s.verifiedSlotInfoCache.Del(key)
+			}
+		}
+		removed += len(keys)
+		if len(keys) < batchSize {
+			return removed, nil
+		}
+	}
+}
+
+// pruneConsensusInfos removes the consensus infos of the epochs before the cutoff
+func (s *Store) pruneConsensusInfos(ctx context.Context, epochCutoff uint64, batchSize int) (int, error) {
+	removed := 0
+	for {
+		if err := ctx.Err(); err != nil {
+			return removed, err
+		}
+		epochs := make([]uint64, 0, batchSize)
+		s.Mutex.Lock()
+		err := s.db.Update(func(tx *bolt.Tx) error {
+			bkt := tx.Bucket(consensusInfosBucket)
+			c := bkt.Cursor()
+			for k, _ := c.First(); k != nil && len(epochs) < batchSize; k, _ = c.Next() {
+				// the latest epoch key is no epoch
+				if len(k) != 8 {
+					continue
+				}
+				epoch := bytesutil.BytesToUint64BigEndian(k)
+				if epoch >= epochCutoff {
+					break
+				}
+				epochs = append(epochs, epoch)
+			}
+			for _, epoch := range epochs {
+				if err := bkt.Delete(bytesutil.Uint64ToBytesBigEndian(epoch)); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		s.Mutex.Unlock()
+		if err != nil {
+			return removed, err
+		}
+		for _, epoch := range epochs {
+			s.consensusInfoCache.Del(epoch)
+		}
+		removed += len(epochs)
+		if len(epochs) < batchSize {
+			return removed, nil
+		}
+	}
+}
+
+// parseSlotKey returns the slot and shard index of a key which is built by slotKey. Other keys, like the latest
+// verified slot, return false.
+func parseSlotKey(key []byte) (uint64, uint64, bool) {
+	switch len(key) {
+	case 8:
+		return bytesutil.BytesToUint64BigEndian(key), 0, true
+	case 16:
+		return bytesutil.BytesToUint64BigEndian(key[:8]), bytesutil.BytesToUint64BigEndian(key[8:]), true
+	}
+	return 0, 0, false
+}
+
+// firstRetainedEpoch returns the first epoch whose consensus info is not pruned
+func firstRetainedEpoch(tx *bolt.Tx) uint64 {
+	epochBytes := tx.Bucket(metadataBucket).Get(firstRetainedEpochKey)
+	if epochBytes == nil {
+		return 0
+	}
+	return bytesutil.BytesToUint64BigEndian(epochBytes)
+}
+
+// firstRetainedSlot returns the first slot of the shard whose records are not pruned
+func firstRetainedSlot(tx *bolt.Tx, shardIndex uint64) uint64 {
+	slotBytes := tx.Bucket(metadataBucket).Get(shardKey(firstRetainedSlotKey, shardIndex))
+	if slotBytes == nil {
+		return 0
+	}
+	return bytesutil.BytesToUint64BigEndian(slotBytes)
+}
+
+// Pruner prunes the database in the background with the configured interval
+type Pruner struct {
+	ctx    context.Context
+	cancel context.CancelFunc
+	db     *Store
+	cfg    *PruneConfig
+	wg     sync.WaitGroup
+}
+
+// NewPruner creates a pruner of the store. It fails when the policy is unknown.
+func NewPruner(ctx context.Context, db *Store, cfg *PruneConfig) (*Pruner, error) {
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Interval <= 0 {
+		return nil, fmt.Errorf("invalid prune interval %s", cfg.Interval)
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	return &Pruner{
+		ctx:    ctx,
+		cancel: cancel,
+		db:     db,
+		cfg:    cfg,
+	}, nil
+}
+
+// Start prunes the database once and then after every interval
+func (p *Pruner) Start() {
+	p.wg.Add(1)
+	go p.run()
+}
+
+// Stop cancels a running prune and waits until it is finished
+func (p *Pruner) Stop() error {
+	p.cancel()
+	p.wg.Wait()
+	return nil
+}
+
+// Status always returns nil, a failed prune is retried with the next interval
+func (p *Pruner) Status() error {
+	return nil
+}
+
+func (p *Pruner) run() {
+	defer p.wg.Done()
+	ticker := time.NewTicker(p.cfg.Interval)
+	defer ticker.Stop()
+	for {
+		start := time.Now()
+		removed, err := p.db.Prune(p.ctx, p.cfg)
+		if err != nil && p.ctx.Err() == nil {
+			log.WithError(err).WithField("removed", removed).Error("Could not prune database")
+		} else if removed > 0 {
+			log.WithField("policy", p.cfg.Policy).WithField("removed", removed).
+				WithField("elapsed", time.Since(start)).Info("Pruned database")
+		}
+
+		select {
+		case <-ticker.C:
+		case <-p.ctx.Done():
+			return
+		}
+	}
+}
@@ -0,0 +1,148 @@
+package kv
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db/iface"
+	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// setupPruneDB stores consensus infos up to the epoch and verified slot infos of slot 1 up to the given slot of
+// every shard
+func setupPruneDB(t *testing.T, toEpoch uint64, toSlots map[uint64]uint64) *Store {
+	ctx := context.Background()
+	db := setupDB(t, true)
+	for epoch := uint64(0); epoch <= toEpoch; epoch++ {
+		require.NoError(t, db.SaveConsensusInfo(ctx, testutil.NewMinimalConsensusInfo(epoch)))
+	}
+	require.NoError(t, db.SaveLatestEpoch(ctx))
+
+	for shardIndex, toSlot := range toSlots {
+		slotInfos := make(map[uint64]*types.SlotInfo)
+		for slot := uint64(1); slot <= toSlot; slot++ {
+			slotInfos[slot] = &types.SlotInfo{
+				VanguardBlockHash: eth1Types.EmptyRootHash,
+				PandoraHeaderHash: common.BytesToHash(bytesutil.Uint64ToBytesBigEndian(slot)),
+			}
+		}
+		require.NoError(t, db.SaveVerifiedSlotInfos(shardIndex, slotInfos))
+	}
+	return db
+}
+
+func TestStore_Prune_Epochs(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	// shard 1 is stalled at slot 40
+	db := setupPruneDB(t, 9, map[uint64]uint64{0: 319, 1: 40})
+	require.NoError(t, db.SaveInvalidSlotInfo(5, 0, &types.InvalidSlotInfo{Reason: types.HeaderHashMismatch}))
+	require.NoError(t, db.SaveVerdicts(300, 0, []*types.RuleVerdict{{Rule: "signature"}}))
+
+	cfg := &PruneConfig{Policy: PruneEpochs, RetainEpochs: 2, BatchSize: 7}
+	removed, err := db.Prune(ctx, cfg)
+	require.NoError(t, err)
+	// slot 1 to 255 of shard 0, slot 1 to 39 of shard 1, the invalid slot and consensus info of epoch 0
+	assert.Equal(t, 255+39+1+1, removed)
+
+	// slots of the two latest epochs are kept
+	slotInfo, err := db.VerifiedSlotInfo(255, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+	slotInfo, err = db.VerifiedSlotInfo(256, 0)
+	require.NoError(t, err)
+	require.NotNil(t, slotInfo)
+	invalidSlotInfo, err := db.InvalidSlotInfo(5, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.InvalidSlotInfo)(nil), invalidSlotInfo)
+	verdicts, err := db.Verdicts(300, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(verdicts))
+
+	// the latest verified slot of the stalled shard and the consensus infos it needs are kept
+	slotInfo, err = db.VerifiedSlotInfo(39, 1)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+	slotInfo, err = db.VerifiedSlotInfo(40, 1)
+	require.NoError(t, err)
+	require.NotNil(t, slotInfo)
+	consensusInfo, err := db.ConsensusInfo(ctx, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.MinimalEpochConsensusInfo)(nil), consensusInfo)
+
+	// range reads start at the first retained record
+	slotInfos, err := db.VerifiedSlotInfos(0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 64, len(slotInfos))
+	consensusInfos, err := db.ConsensusInfos(0)
+	require.NoError(t, err)
+	require.Equal(t, 9, len(consensusInfos))
+	assert.Equal(t, uint64(1), consensusInfos[0].Epoch)
+
+	removed, err = db.Prune(ctx, cfg)
+	require.NoError(t, err)
+	assert.Equal(t, 0, removed)
+}
+
+func TestStore_Prune_Finalized(t *testing.T) {
+	t.Parallel()
+	ctx := context.Background()
+	// nothing of shard 2 is finalized
+	db := setupPruneDB(t, 3, map[uint64]uint64{0: 100, 2: 100})
+	require.NoError(t, db.SaveLatestFinalizedSlot(64, 0))
+	require.NoError(t, db.SaveSkippedSlotInfo(10, 0, &types.SlotInfo{}))
+
+	removed, err := db.Prune(ctx, &PruneConfig{Policy: PruneFinalized})
+	require.NoError(t, err)
+	assert.Equal(t, 63+1, removed)
+
+	slotInfo, err := db.VerifiedSlotInfo(64, 0)
+	require.NoError(t, err)
+	require.NotNil(t, slotInfo)
+	skippedSlotInfo, err := db.SkippedSlotInfo(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), skippedSlotInfo)
+	slotInfo, err = db.VerifiedSlotInfo(1, 2)
+	require.NoError(t, err)
+	require.NotNil(t, slotInfo)
+	consensusInfos, err := db.ConsensusInfos(0)
+	require.NoError(t, err)
+	assert.Equal(t, 4, len(consensusInfos))
+
+	// a reorg past the retained finalized slot is refused, the verified chain can not be continued from a pruned slot
+	_, err = db.RevertVerifiedSlotInfos(64, 0)
+	var prunedErr *iface.PrunedRevertError
+	require.Equal(t, true, errors.As(err, &prunedErr))
+	assert.Equal(t, uint64(64), prunedErr.FirstRetainedSlot)
+	assert.Equal(t, uint64(100), db.InMemoryLatestVerifiedSlot(0))
+	slotInfo, err = db.VerifiedSlotInfo(64, 0)
+	require.NoError(t, err)
+	require.NotNil(t, slotInfo)
+
+	// a reorg back to the finalized slot does not search the pruned slots
+	reverted, err := db.RevertVerifiedSlotInfos(65, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 36, len(reverted))
+	assert.Equal(t, uint64(64), db.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, common.BytesToHash(bytesutil.Uint64ToBytesBigEndian(64)), db.InMemoryLatestVerifiedHeaderHash(0))
+	assert.Equal(t, uint64(64), db.LatestSavedVerifiedSlot(0))
+}
+
+func TestNewPruner_InvalidConfig(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	_, err := NewPruner(context.Background(), db, &PruneConfig{Policy: "oldest", Interval: time.Minute})
+	assert.ErrorContains(t, "unknown prune policy", err)
+	_, err = NewPruner(context.Background(), db, &PruneConfig{Policy: PruneEpochs, Interval: time.Minute})
+	assert.ErrorContains(t, "needs at least one retained epoch", err)
+	_, err = NewPruner(context.Background(), db, &PruneConfig{Policy: PruneFinalized})
+	assert.ErrorContains(t, "invalid prune interval", err)
+}
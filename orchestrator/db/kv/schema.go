@@ -1,12 +1,29 @@
 package kv
 
 var (
-	// 3 buckets for containing orchestrator data
+	// 14 buckets for containing orchestrator data
 	consensusInfosBucket    = []byte("consensus-info")
 	verifiedSlotInfosBucket = []byte("verified-slots")
 	invalidSlotInfosBucket  = []byte("invalid-slots")
+	skippedSlotInfosBucket  = []byte("skipped-slots")
+	timedOutSlotInfosBucket = []byte("timed-out-slots")
+	equivocationsBucket     = []byte("equivocations")
+	verdictsBucket          = []byte("verdicts")
+	pendingHeadersBucket    = []byte("pending-headers")
+	pendingShardInfosBucket = []byte("pending-shard-infos")
+	metadataBucket          = []byte("metadata")
+	// indexes from the hashes of verified and invalid slot infos to their slot key
+	pandoraHeaderHashesBucket = []byte("pandora-header-hashes")
+	vanguardBlockHashesBucket = []byte("vanguard-block-hashes")
+	// full pandora headers and vanguard shard infos of verified slots
+	verifiedHeadersBucket    = []byte("verified-headers")
+	verifiedShardInfosBucket = []byte("verified-shard-infos")
 
 	latestHeaderHashKey        = []byte("latest-header-hash")
 	lastStoredEpochKey         = []byte("last-epoch")
 	latestSavedVerifiedSlotKey = []byte("latest-verified-slot")
+	latestFinalizedSlotKey     = []byte("latest-finalized-slot")
+	schemaVersionKey           = []byte("schema-version")
+	firstRetainedEpochKey      = []byte("first-retained-epoch")
+	firstRetainedSlotKey       = []byte("first-retained-slot")
 )
@@ -0,0 +1,41 @@
+package kv
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// SkippedSlotInfo
+func (s *Store) SkippedSlotInfo(slot uint64, shardIndex uint64) (*types.SlotInfo, error) {
+	var slotInfo *types.SlotInfo
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(skippedSlotInfosBucket)
+		key := slotKey(slot, shardIndex)
+		value := bkt.Get(key[:])
+		if value == nil {
+			return nil
+		}
+		return decode(value, &slotInfo)
+	})
+	return slotInfo, err
+}
+
+// SaveSkippedSlotInfo stores the partial slot info of a slot which will never be verified because
+// a later slot has already been verified.
+func (s *Store) SaveSkippedSlotInfo(slot uint64, shardIndex uint64, slotInfo *types.SlotInfo) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(skippedSlotInfosBucket)
+		slotBytes := slotKey(slot, shardIndex)
+		enc, err := encode(slotInfo)
+		if err != nil {
+			return err
+		}
+		if err := bkt.Put(slotBytes, enc); err != nil {
+			return err
+		}
+		return nil
+	})
+}
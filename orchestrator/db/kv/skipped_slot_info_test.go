@@ -0,0 +1,24 @@
+package kv
+
+import (
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+	"testing"
+)
+
+func TestStore_SkippedSlotInfo(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	slotInfo := &types.SlotInfo{PandoraHeaderHash: eth1Types.EmptyRootHash}
+	require.NoError(t, db.SaveSkippedSlotInfo(10, 0, slotInfo))
+
+	retrievedSlotInfo, err := db.SkippedSlotInfo(10, 0)
+	require.NoError(t, err)
+	assert.DeepEqual(t, slotInfo, retrievedSlotInfo)
+
+	retrievedSlotInfo, err = db.SkippedSlotInfo(11, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), retrievedSlotInfo)
+}
@@ -0,0 +1,40 @@
+package kv
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// TimedOutSlotInfo
+func (s *Store) TimedOutSlotInfo(slot uint64, shardIndex uint64) (*types.SlotInfo, error) {
+	var slotInfo *types.SlotInfo
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(timedOutSlotInfosBucket)
+		key := slotKey(slot, shardIndex)
+		value := bkt.Get(key[:])
+		if value == nil {
+			return nil
+		}
+		return decode(value, &slotInfo)
+	})
+	return slotInfo, err
+}
+
+// SaveTimedOutSlotInfo stores the partial slot info of a pending slot which has not been matched before its deadline.
+func (s *Store) SaveTimedOutSlotInfo(slot uint64, shardIndex uint64, slotInfo *types.SlotInfo) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(timedOutSlotInfosBucket)
+		slotBytes := slotKey(slot, shardIndex)
+		enc, err := encode(slotInfo)
+		if err != nil {
+			return err
+		}
+		if err := bkt.Put(slotBytes, enc); err != nil {
+			return err
+		}
+		return nil
+	})
+}
@@ -0,0 +1,36 @@
+package kv
+
+import (
+	"github.com/boltdb/bolt"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// Verdicts returns the verdicts of the verification rules which have decided the slot
+func (s *Store) Verdicts(slot uint64, shardIndex uint64) ([]*types.RuleVerdict, error) {
+	var verdicts []*types.RuleVerdict
+	err := s.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(verdictsBucket)
+		value := bkt.Get(slotKey(slot, shardIndex))
+		if value == nil {
+			return nil
+		}
+		return decode(value, &verdicts)
+	})
+	return verdicts, err
+}
+
+// SaveVerdicts stores the verdicts of the verification rules of the slot. Verdicts of a later verification replace
+// the stored ones.
+func (s *Store) SaveVerdicts(slot uint64, shardIndex uint64, verdicts []*types.RuleVerdict) error {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(verdictsBucket)
+		enc, err := encode(verdicts)
+		if err != nil {
+			return err
+		}
+		return bkt.Put(slotKey(slot, shardIndex), enc)
+	})
+}
@@ -0,0 +1,27 @@
+package kv
+
+import (
+	"testing"
+
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+func TestStore_Verdicts(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	verdicts := []*types.RuleVerdict{
+		{Rule: "sharding-info", Reason: types.NoMismatch},
+		{Rule: "signature", Reason: types.InvalidSignature},
+	}
+	require.NoError(t, db.SaveVerdicts(10, 1, verdicts))
+
+	retrievedVerdicts, err := db.Verdicts(10, 1)
+	require.NoError(t, err)
+	assert.DeepEqual(t, verdicts, retrievedVerdicts)
+
+	retrievedVerdicts, err = db.Verdicts(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 0, len(retrievedVerdicts))
+}
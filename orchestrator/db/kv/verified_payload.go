@@ -0,0 +1,90 @@
+package kv
+
+import (
+	"github.com/boltdb/bolt"
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// VerifiedHeader returns the full pandora header of a verified slot. It returns nil when the slot is not verified
+// or its payload is pruned.
+func (s *Store) VerifiedHeader(slot uint64, shardIndex uint64) (*eth1Types.Header, error) {
+	var headerInfo *types.PandoraHeaderInfo
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(verifiedHeadersBucket).Get(slotKey(slot, shardIndex))
+		if value == nil {
+			return nil
+		}
+		return decode(value, &headerInfo)
+	})
+	if err != nil || headerInfo == nil {
+		return nil, err
+	}
+	return headerInfo.Header, nil
+}
+
+// VerifiedShardInfo returns the vanguard shard info of a verified slot. It returns nil when the slot is not verified
+// or its payload is pruned.
+func (s *Store) VerifiedShardInfo(slot uint64, shardIndex uint64) (*types.VanguardShardInfo, error) {
+	var shardInfo *types.VanguardShardInfo
+	err := s.db.View(func(tx *bolt.Tx) error {
+		value := tx.Bucket(verifiedShardInfosBucket).Get(slotKey(slot, shardIndex))
+		if value == nil {
+			return nil
+		}
+		return decode(value, &shardInfo)
+	})
+	return shardInfo, err
+}
+
+// putVerifiedPayload stores the header and the shard info of the slot. A missing part is not stored.
+func putVerifiedPayload(
+	tx *bolt.Tx,
+	slot uint64,
+	shardIndex uint64,
+	header *eth1Types.Header,
+	shardInfo *types.VanguardShardInfo,
+) error {
+	key := slotKey(slot, shardIndex)
+	if header != nil {
+		enc, err := encode(&types.PandoraHeaderInfo{Slot: slot, ShardIndex: shardIndex, Header: header})
+		if err != nil {
+			return err
+		}
+		if err := tx.Bucket(verifiedHeadersBucket).Put(key, enc); err != nil {
+			return err
+		}
+	}
+	if shardInfo != nil {
+		enc, err := encode(shardInfo)
+		if err != nil {
+			return err
+		}
+		return tx.Bucket(verifiedShardInfosBucket).Put(key, enc)
+	}
+	return nil
+}
+
+// readVerifiedPayload fills the stored header and shard info of the slot into the verified slot. A missing part is
+// left nil.
+func readVerifiedPayload(tx *bolt.Tx, key []byte, verifiedSlot *types.VerifiedSlot) error {
+	if value := tx.Bucket(verifiedHeadersBucket).Get(key); value != nil {
+		var headerInfo *types.PandoraHeaderInfo
+		if err := decode(value, &headerInfo); err != nil {
+			return err
+		}
+		verifiedSlot.Header = headerInfo.Header
+	}
+	if value := tx.Bucket(verifiedShardInfosBucket).Get(key); value != nil {
+		return decode(value, &verifiedSlot.ShardInfo)
+	}
+	return nil
+}
+
+// deleteVerifiedPayload removes the header and the shard info of a slot which is no longer verified
+func deleteVerifiedPayload(tx *bolt.Tx, key []byte) error {
+	if err := tx.Bucket(verifiedHeadersBucket).Delete(key); err != nil {
+		return err
+	}
+	return tx.Bucket(verifiedShardInfosBucket).Delete(key)
+}
@@ -0,0 +1,102 @@
+package kv
+
+import (
+	"context"
+	"testing"
+
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// setupPayloadDB commits verified slots with their payloads of slot 1 up to the given slot of shard 0
+func setupPayloadDB(t *testing.T, toEpoch uint64, toSlot uint64) (*Store, []*types.VerifiedSlot) {
+	db := setupPruneDB(t, toEpoch, map[uint64]uint64{})
+	slots := make([]uint64, 0, toSlot)
+	for slot := uint64(1); slot <= toSlot; slot++ {
+		slots = append(slots, slot)
+	}
+	verifiedSlots := linkedVerifiedSlots(eth1Types.EmptyRootHash, slots...)
+	require.NoError(t, db.CommitVerifiedSlots(0, verifiedSlots))
+	return db, verifiedSlots
+}
+
+func TestStore_VerifiedPayload(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	header := testutil.NewEth1Header(5)
+	shardInfo := testutil.NewVanguardShardInfo(5, header)
+	shardInfo.ShardIndex = 1
+	require.NoError(t, db.CommitVerifiedSlots(1, []*types.VerifiedSlot{{
+		Slot:      5,
+		SlotInfo:  &types.SlotInfo{PandoraHeaderHash: header.Hash()},
+		Header:    header,
+		ShardInfo: shardInfo,
+	}}))
+
+	retrievedHeader, err := db.VerifiedHeader(5, 1)
+	require.NoError(t, err)
+	require.NotNil(t, retrievedHeader)
+	assert.Equal(t, header.Hash(), retrievedHeader.Hash())
+	retrievedShardInfo, err := db.VerifiedShardInfo(5, 1)
+	require.NoError(t, err)
+	assert.DeepEqual(t, shardInfo, retrievedShardInfo)
+
+	retrievedHeader, err = db.VerifiedHeader(5, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*eth1Types.Header)(nil), retrievedHeader)
+	retrievedShardInfo, err = db.VerifiedShardInfo(6, 1)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.VanguardShardInfo)(nil), retrievedShardInfo)
+}
+
+func TestStore_VerifiedPayload_Revert(t *testing.T) {
+	t.Parallel()
+	db, verifiedSlots := setupPayloadDB(t, 0, 10)
+
+	revertedSlots, err := db.RevertVerifiedSlotInfos(8, 0)
+	require.NoError(t, err)
+	// reverted payloads are returned so that they can be pending again
+	assert.Equal(t, 3, len(revertedSlots))
+	assert.Equal(t, verifiedSlots[8].Header.Hash(), revertedSlots[9].Header.Hash())
+	assert.Equal(t, uint64(9), revertedSlots[9].ShardInfo.Slot)
+	header, err := db.VerifiedHeader(7, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, header)
+	header, err = db.VerifiedHeader(8, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*eth1Types.Header)(nil), header)
+	shardInfo, err := db.VerifiedShardInfo(10, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.VanguardShardInfo)(nil), shardInfo)
+}
+
+func TestStore_Prune_PayloadRetention(t *testing.T) {
+	t.Parallel()
+	db, _ := setupPayloadDB(t, 3, 127)
+
+	// payloads of the latest epoch are kept while all slot infos are kept
+	removed, err := db.Prune(context.Background(), &PruneConfig{Policy: PruneNone, PayloadRetainEpochs: 1})
+	require.NoError(t, err)
+	assert.Equal(t, 2*95, removed)
+	header, err := db.VerifiedHeader(95, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*eth1Types.Header)(nil), header)
+	header, err = db.VerifiedHeader(96, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, header)
+	slotInfo, err := db.VerifiedSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, slotInfo)
+
+	// payloads are not kept longer than their slot infos
+	removed, err = db.Prune(context.Background(), &PruneConfig{Policy: PruneEpochs, RetainEpochs: 1, PayloadRetainEpochs: 2})
+	require.NoError(t, err)
+	// slot 1 to 95 and consensus infos of epoch 0 to 2
+	assert.Equal(t, 95+3, removed)
+	shardInfo, err := db.VerifiedShardInfo(96, 0)
+	require.NoError(t, err)
+	assert.NotNil(t, shardInfo)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/boltdb/bolt"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db/iface"
 	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
 	"github.com/pkg/errors"
@@ -17,14 +18,14 @@ var (
 )
 
 // VerifiedSlotInfo
-func (s *Store) VerifiedSlotInfo(slot uint64) (*types.SlotInfo, error) {
-	if v, ok := s.verifiedSlotInfoCache.Get(slot); v != nil && ok {
+func (s *Store) VerifiedSlotInfo(slot uint64, shardIndex uint64) (*types.SlotInfo, error) {
+	key := slotKey(slot, shardIndex)
+	if v, ok := s.verifiedSlotInfoCache.Get(key); v != nil && ok {
 		return v.(*types.SlotInfo), nil
 	}
 	var slotInfo *types.SlotInfo
 	err := s.db.View(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(verifiedSlotInfosBucket)
-		key := bytesutil.Uint64ToBytesBigEndian(slot)
 		value := bkt.Get(key[:])
 		if value == nil {
 			return nil
@@ -35,8 +36,8 @@ func (s *Store) VerifiedSlotInfo(slot uint64) (*types.SlotInfo, error) {
 }
 
 // ConsensusInfos
-func (s *Store) VerifiedSlotInfos(fromSlot uint64) (map[uint64]*types.SlotInfo, error) {
-	latestVerifiedSlot := s.LatestSavedVerifiedSlot()
+func (s *Store) VerifiedSlotInfos(fromSlot uint64, shardIndex uint64) (map[uint64]*types.SlotInfo, error) {
+	latestVerifiedSlot := s.LatestSavedVerifiedSlot(shardIndex)
 	// when requested epoch is greater than stored latest epoch
 	if fromSlot > latestVerifiedSlot {
 		return nil, errors.Wrap(errInvalidSlot, fmt.Sprintf("fromSlot: %d", fromSlot))
@@ -45,14 +46,18 @@ func (s *Store) VerifiedSlotInfos(fromSlot uint64) (map[uint64]*types.SlotInfo,
 	slotInfos := make(map[uint64]*types.SlotInfo)
 	err := s.db.View(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(verifiedSlotInfosBucket)
+		// slot infos of pruned slots are skipped
+		if retainedSlot := firstRetainedSlot(tx, shardIndex); fromSlot < retainedSlot {
+			fromSlot = retainedSlot
+		}
 		for slot := fromSlot; slot <= latestVerifiedSlot; slot++ {
+			// preparing key bytes for searching into cache and db
+			key := slotKey(slot, shardIndex)
 			// fast finding into cache, if the value does not exist in cache, it starts finding into db
-			if v, _ := s.verifiedSlotInfoCache.Get(slot); v != nil {
+			if v, _ := s.verifiedSlotInfoCache.Get(key); v != nil {
 				slotInfos[slot] = v.(*types.SlotInfo)
 				continue
 			}
-			// preparing key bytes for searching into db
-			key := bytesutil.Uint64ToBytesBigEndian(slot)
 			enc := bkt.Get(key[:])
 			if enc == nil {
 				// no data found for the associated slot. So just find for other slot
@@ -72,43 +77,252 @@ func (s *Store) VerifiedSlotInfos(fromSlot uint64) (map[uint64]*types.SlotInfo,
 	return slotInfos, nil
 }
 
-// SaveVerifiedSlotInfo
-func (s *Store) SaveVerifiedSlotInfo(slot uint64, slotInfo *types.SlotInfo) error {
+// CommitVerifiedSlots stores a linked run of verified slots of the shard with their verdicts, payloads and hash
+// indexes and moves the latest verified slot and header hash to the last slot, all in a single transaction. Slots
+// must be given in ascending order and every header must extend the previous one. It returns a
+// *iface.VerifiedSlotConflictError and stores nothing when a slot does not extend the verified chain.
+func (s *Store) CommitVerifiedSlots(shardIndex uint64, verifiedSlots []*types.VerifiedSlot) error {
+	if len(verifiedSlots) == 0 {
+		return nil
+	}
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	// storing consensus info into cache and db
-	return s.db.Update(func(tx *bolt.Tx) error {
+	latestVerifiedSlot, latestHeaderHash := s.latestVerifiedSlotOf(shardIndex), s.latestHeaderHashOf(shardIndex)
+	for _, verifiedSlot := range verifiedSlots {
+		slot, header := verifiedSlot.Slot, verifiedSlot.Header
+		// the first verified header starts the chain
+		if latestHeaderHash != EmptyHash && (slot <= latestVerifiedSlot || header.ParentHash != latestHeaderHash) {
+			return &iface.VerifiedSlotConflictError{
+				Slot:               slot,
+				ShardIndex:         shardIndex,
+				ParentHash:         header.ParentHash,
+				LatestVerifiedSlot: latestVerifiedSlot,
+				LatestHeaderHash:   latestHeaderHash,
+			}
+		}
+		latestVerifiedSlot, latestHeaderHash = slot, verifiedSlot.SlotInfo.PandoraHeaderHash
+	}
+
+	err := s.db.Update(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(verifiedSlotInfosBucket)
-		slotBytes := bytesutil.Uint64ToBytesBigEndian(slot)
-		enc, err := encode(slotInfo)
-		if err != nil {
+		for _, verifiedSlot := range verifiedSlots {
+			key := slotKey(verifiedSlot.Slot, shardIndex)
+			enc, err := encode(verifiedSlot.SlotInfo)
+			if err != nil {
+				return err
+			}
+			// a slot info which is left above the latest verified slot is replaced
+			if err := unindexOverwritten(tx, verifiedSlotInfosBucket, key); err != nil {
+				return err
+			}
+			if err := bkt.Put(key, enc); err != nil {
+				return err
+			}
+			if err := indexSlotInfo(tx, key, verifiedSlot.SlotInfo); err != nil {
+				return err
+			}
+			if err := putVerifiedPayload(
+				tx, verifiedSlot.Slot, shardIndex, verifiedSlot.Header, verifiedSlot.ShardInfo); err != nil {
+				return err
+			}
+			if verifiedSlot.Verdicts == nil {
+				continue
+			}
+			if enc, err = encode(verifiedSlot.Verdicts); err != nil {
+				return err
+			}
+			if err := tx.Bucket(verdictsBucket).Put(key, enc); err != nil {
+				return err
+			}
+		}
+		slotBytes := bytesutil.Uint64ToBytesBigEndian(latestVerifiedSlot)
+		if err := bkt.Put(shardKey(latestSavedVerifiedSlotKey, shardIndex), slotBytes); err != nil {
 			return err
 		}
-		if status := s.verifiedSlotInfoCache.Set(slot, slotInfo, 0); !status {
-			log.WithField("slot", slot).Warn("could not store verified slot info into cache")
+		return bkt.Put(shardKey(latestHeaderHashKey, shardIndex), latestHeaderHash.Bytes())
+	})
+	if err != nil {
+		return errors.Wrap(err, "could not commit verified slot")
+	}
+
+	for _, verifiedSlot := range verifiedSlots {
+		if status := s.verifiedSlotInfoCache.Set(
+			slotKey(verifiedSlot.Slot, shardIndex), verifiedSlot.SlotInfo, 0); !status {
+			log.WithField("slot", verifiedSlot.Slot).Warn("could not store verified slot info into cache")
+		}
+	}
+	s.latestVerifiedSlot[shardIndex] = latestVerifiedSlot
+	s.latestHeaderHash[shardIndex] = latestHeaderHash
+	return nil
+}
+
+// SaveVerifiedSlotInfos stores a run of verified slot infos of the shard together with the latest verified slot
+// and header hash in a single transaction. The highest slot of the run becomes the latest verified slot.
+func (s *Store) SaveVerifiedSlotInfos(shardIndex uint64, slotInfos map[uint64]*types.SlotInfo) error {
+	if len(slotInfos) == 0 {
+		return nil
+	}
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	latestVerifiedSlot := uint64(0)
+	for slot := range slotInfos {
+		if slot > latestVerifiedSlot {
+			latestVerifiedSlot = slot
+		}
+	}
+	latestHeaderHash := slotInfos[latestVerifiedSlot].PandoraHeaderHash
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(verifiedSlotInfosBucket)
+		for slot, slotInfo := range slotInfos {
+			enc, err := encode(slotInfo)
+			if err != nil {
+				return err
+			}
+			key := slotKey(slot, shardIndex)
+			if err := unindexOverwritten(tx, verifiedSlotInfosBucket, key); err != nil {
+				return err
+			}
+			if err := bkt.Put(key, enc); err != nil {
+				return err
+			}
+			if err := indexSlotInfo(tx, key, slotInfo); err != nil {
+				return err
+			}
 		}
-		if err := bkt.Put(slotBytes, enc); err != nil {
+		slotBytes := bytesutil.Uint64ToBytesBigEndian(latestVerifiedSlot)
+		if err := bkt.Put(shardKey(latestSavedVerifiedSlotKey, shardIndex), slotBytes); err != nil {
 			return err
 		}
-		// store latest verified slot and latest header hash in in-memory
-		s.latestVerifiedSlot = slot
-		s.latestHeaderHash = slotInfo.PandoraHeaderHash
+		return bkt.Put(shardKey(latestHeaderHashKey, shardIndex), latestHeaderHash.Bytes())
+	})
+	if err != nil {
+		return err
+	}
 
-		return nil
+	for slot, slotInfo := range slotInfos {
+		if status := s.verifiedSlotInfoCache.Set(slotKey(slot, shardIndex), slotInfo, 0); !status {
+			log.WithField("slot", slot).Warn("could not store verified slot info into cache")
+		}
+	}
+	s.latestVerifiedSlot[shardIndex] = latestVerifiedSlot
+	s.latestHeaderHash[shardIndex] = latestHeaderHash
+	return nil
+}
+
+// RevertVerifiedSlotInfos removes verified slot infos and their payloads from the given slot up to the latest verified
+// slot. Latest verified slot and header hash are moved back to the highest remaining verified slot. It returns the
+// removed slot infos with their payloads, which are nil when the payload is pruned. It returns a
+// *iface.PrunedRevertError and reverts nothing when no verified slot would remain after the pruned slots.
+func (s *Store) RevertVerifiedSlotInfos(fromSlot uint64, shardIndex uint64) (map[uint64]*types.VerifiedSlot, error) {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	revertedSlots := make(map[uint64]*types.VerifiedSlot)
+	toSlot := s.latestVerifiedSlotOf(shardIndex)
+	latestVerifiedSlot, latestHeaderHash := uint64(0), EmptyHash
+	err := s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(verifiedSlotInfosBucket)
+		// keys start with the big endian slot, so the slots of the shard are visited in order
+		keys := make([][]byte, 0)
+		c := bkt.Cursor()
+		for k, _ := c.Seek(slotKey(fromSlot, 0)); k != nil; k, _ = c.Next() {
+			slot, slotShardIndex, ok := parseSlotKey(k)
+			if !ok {
+				continue
+			}
+			if slot > toSlot {
+				break
+			}
+			if slotShardIndex == shardIndex {
+				keys = append(keys, append([]byte{}, k...))
+			}
+		}
+		if len(keys) == 0 {
+			return nil
+		}
+
+		// finding the highest verified slot which is not reverted. Pruned slots are not searched.
+		retainedSlot := firstRetainedSlot(tx, shardIndex)
+		found := false
+		c = bkt.Cursor()
+		k, v := c.Seek(slotKey(fromSlot, 0))
+		if k == nil {
+			k, v = c.Last()
+		} else {
+			k, v = c.Prev()
+		}
+		for ; k != nil; k, v = c.Prev() {
+			slot, slotShardIndex, ok := parseSlotKey(k)
+			if !ok || slot >= fromSlot || slotShardIndex != shardIndex {
+				continue
+			}
+			if slot < retainedSlot {
+				break
+			}
+			var slotInfo *types.SlotInfo
+			if err := decode(v, &slotInfo); err != nil {
+				return err
+			}
+			latestVerifiedSlot, latestHeaderHash, found = slot, slotInfo.PandoraHeaderHash, true
+			break
+		}
+		// the verified chain only starts from scratch when nothing is pruned
+		if !found && retainedSlot > 0 {
+			return &iface.PrunedRevertError{FromSlot: fromSlot, ShardIndex: shardIndex, FirstRetainedSlot: retainedSlot}
+		}
+
+		for _, key := range keys {
+			slot, _, _ := parseSlotKey(key)
+			revertedSlot := &types.VerifiedSlot{Slot: slot}
+			if err := decode(bkt.Get(key), &revertedSlot.SlotInfo); err != nil {
+				return err
+			}
+			if err := readVerifiedPayload(tx, key, revertedSlot); err != nil {
+				return err
+			}
+			if err := bkt.Delete(key); err != nil {
+				return err
+			}
+			if err := unindexSlotInfo(tx, key, revertedSlot.SlotInfo); err != nil {
+				return err
+			}
+			if err := deleteVerifiedPayload(tx, key); err != nil {
+				return err
+			}
+			revertedSlots[slot] = revertedSlot
+		}
+		slotBytes := bytesutil.Uint64ToBytesBigEndian(latestVerifiedSlot)
+		if err := bkt.Put(shardKey(latestSavedVerifiedSlotKey, shardIndex), slotBytes); err != nil {
+			return err
+		}
+		return bkt.Put(shardKey(latestHeaderHashKey, shardIndex), latestHeaderHash.Bytes())
 	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(revertedSlots) > 0 {
+		for slot := range revertedSlots {
+			s.verifiedSlotInfoCache.Del(slotKey(slot, shardIndex))
+		}
+		s.latestVerifiedSlot[shardIndex] = latestVerifiedSlot
+		s.latestHeaderHash[shardIndex] = latestHeaderHash
+	}
+	return revertedSlots, nil
 }
 
 // SaveLatestEpoch
-func (s *Store) SaveLatestVerifiedSlot(ctx context.Context) error {
+func (s *Store) SaveLatestVerifiedSlot(ctx context.Context, shardIndex uint64) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
 	// storing latest epoch number into db
+	slotBytes := bytesutil.Uint64ToBytesBigEndian(s.latestVerifiedSlotOf(shardIndex))
 	return s.db.Update(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(verifiedSlotInfosBucket)
-		slotBytes := bytesutil.Uint64ToBytesBigEndian(s.latestVerifiedSlot)
-		if err := bkt.Put(latestSavedVerifiedSlotKey, slotBytes); err != nil {
+		if err := bkt.Put(shardKey(latestSavedVerifiedSlotKey, shardIndex), slotBytes); err != nil {
 			return err
 		}
 		return nil
@@ -116,13 +330,13 @@ func (s *Store) SaveLatestVerifiedSlot(ctx context.Context) error {
 }
 
 // LatestSavedEpoch
-func (s *Store) LatestSavedVerifiedSlot() uint64 {
+func (s *Store) LatestSavedVerifiedSlot(shardIndex uint64) uint64 {
 	var latestSavedVerifiedSlot uint64
 	// Db is not prepared yet. Retrieve latest saved epoch number from db
 	if !s.isRunning {
 		s.db.View(func(tx *bolt.Tx) error {
 			bkt := tx.Bucket(verifiedSlotInfosBucket)
-			slotBytes := bkt.Get(latestSavedVerifiedSlotKey[:])
+			slotBytes := bkt.Get(shardKey(latestSavedVerifiedSlotKey, shardIndex))
 			// not found the latest epoch in db. so latest epoch will be zero
 			if slotBytes == nil {
 				latestSavedVerifiedSlot = uint64(0)
@@ -137,23 +351,32 @@ func (s *Store) LatestSavedVerifiedSlot() uint64 {
 	return latestSavedVerifiedSlot
 }
 
-func (s *Store) InMemoryLatestVerifiedSlot() uint64 {
+func (s *Store) InMemoryLatestVerifiedSlot(shardIndex uint64) uint64 {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	return s.latestVerifiedSlot
+	return s.latestVerifiedSlotOf(shardIndex)
+}
+
+// latestVerifiedSlotOf returns the in-memory latest verified slot of the shard. It is loaded from db when the shard
+// is accessed for the first time. Caller must hold the lock.
+func (s *Store) latestVerifiedSlotOf(shardIndex uint64) uint64 {
+	if _, exists := s.latestVerifiedSlot[shardIndex]; !exists {
+		s.latestVerifiedSlot[shardIndex] = s.LatestSavedVerifiedSlot(shardIndex)
+	}
+	return s.latestVerifiedSlot[shardIndex]
 }
 
 // SaveLatestEpoch
-func (s *Store) SaveLatestVerifiedHeaderHash() error {
+func (s *Store) SaveLatestVerifiedHeaderHash(shardIndex uint64) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
 	// storing latest epoch number into db
+	headerHashBytes := s.latestHeaderHashOf(shardIndex).Bytes()
 	return s.db.Update(func(tx *bolt.Tx) error {
 		bkt := tx.Bucket(verifiedSlotInfosBucket)
-		headerHashBytes := s.latestHeaderHash.Bytes()
-		if err := bkt.Put(latestHeaderHashKey, headerHashBytes); err != nil {
+		if err := bkt.Put(shardKey(latestHeaderHashKey, shardIndex), headerHashBytes); err != nil {
 			return err
 		}
 		return nil
@@ -161,13 +384,13 @@ func (s *Store) SaveLatestVerifiedHeaderHash() error {
 }
 
 // LatestSavedEpoch
-func (s *Store) LatestVerifiedHeaderHash() common.Hash {
+func (s *Store) LatestVerifiedHeaderHash(shardIndex uint64) common.Hash {
 	var latestHeaderHash common.Hash
 	// Db is not prepared yet. Retrieve latest saved epoch number from db
 	if !s.isRunning {
 		s.db.View(func(tx *bolt.Tx) error {
 			bkt := tx.Bucket(verifiedSlotInfosBucket)
-			latestHeaderHashBytes := bkt.Get(latestHeaderHashKey[:])
+			latestHeaderHashBytes := bkt.Get(shardKey(latestHeaderHashKey, shardIndex))
 			// not found the latest epoch in db. so latest epoch will be zero
 			if latestHeaderHashBytes == nil {
 				latestHeaderHash = EmptyHash
@@ -182,9 +405,46 @@ func (s *Store) LatestVerifiedHeaderHash() common.Hash {
 	return latestHeaderHash
 }
 
-func (s *Store) InMemoryLatestVerifiedHeaderHash() common.Hash {
+func (s *Store) InMemoryLatestVerifiedHeaderHash(shardIndex uint64) common.Hash {
+	s.Mutex.Lock()
+	defer s.Mutex.Unlock()
+
+	return s.latestHeaderHashOf(shardIndex)
+}
+
+// latestHeaderHashOf returns the in-memory latest verified header hash of the shard. It is loaded from db when the
+// shard is accessed for the first time. Caller must hold the lock.
+func (s *Store) latestHeaderHashOf(shardIndex uint64) common.Hash {
+	if _, exists := s.latestHeaderHash[shardIndex]; !exists {
+		s.latestHeaderHash[shardIndex] = s.LatestVerifiedHeaderHash(shardIndex)
+	}
+	return s.latestHeaderHash[shardIndex]
+}
+
+// SaveLatestFinalizedSlot stores the highest verified slot of the shard which is finalized by vanguard. Verified
+// slots up to this slot are irreversible.
+func (s *Store) SaveLatestFinalizedSlot(slot uint64, shardIndex uint64) error {
 	s.Mutex.Lock()
 	defer s.Mutex.Unlock()
 
-	return s.latestHeaderHash
+	slotBytes := bytesutil.Uint64ToBytesBigEndian(slot)
+	return s.db.Update(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(verifiedSlotInfosBucket)
+		return bkt.Put(shardKey(latestFinalizedSlotKey, shardIndex), slotBytes)
+	})
+}
+
+// LatestFinalizedSlot returns the latest finalized slot of the shard. It is zero when nothing is finalized yet.
+func (s *Store) LatestFinalizedSlot(shardIndex uint64) uint64 {
+	var latestFinalizedSlot uint64
+	s.db.View(func(tx *bolt.Tx) error {
+		bkt := tx.Bucket(verifiedSlotInfosBucket)
+		slotBytes := bkt.Get(shardKey(latestFinalizedSlotKey, shardIndex))
+		if slotBytes == nil {
+			return nil
+		}
+		latestFinalizedSlot = bytesutil.BytesToUint64BigEndian(slotBytes)
+		return nil
+	})
+	return latestFinalizedSlot
 }
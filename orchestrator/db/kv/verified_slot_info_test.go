@@ -1,27 +1,259 @@
 package kv
 
 import (
+	"errors"
+	"github.com/ethereum/go-ethereum/common"
 	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db/iface"
+	"github.com/lukso-network/lukso-orchestrator/shared/bytesutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
 	types "github.com/lukso-network/lukso-orchestrator/shared/types"
 	"testing"
 )
 
+// linkedVerifiedSlots returns verified slots whose headers extend each other, starting from the given parent hash
+func linkedVerifiedSlots(parentHash common.Hash, slots ...uint64) []*types.VerifiedSlot {
+	verifiedSlots := make([]*types.VerifiedSlot, 0, len(slots))
+	for _, slot := range slots {
+		header := testutil.NewEth1HeaderWithParent(slot, parentHash)
+		shardInfo := testutil.NewVanguardShardInfo(slot, header)
+		parentHash = header.Hash()
+		verifiedSlots = append(verifiedSlots, &types.VerifiedSlot{
+			Slot: slot,
+			SlotInfo: &types.SlotInfo{
+				VanguardBlockHash: common.BytesToHash(shardInfo.BlockHash),
+				PandoraHeaderHash: header.Hash(),
+			},
+			Header:    header,
+			ShardInfo: shardInfo,
+		})
+	}
+	return verifiedSlots
+}
+
 func TestStore_VerifiedSlotInfo(t *testing.T) {
 	t.Parallel()
 	db := setupDB(t, true)
-	slotInfos := make([]*types.SlotInfo, 2001)
-	for i := 0; i <= 2000; i++ {
-		slotInfo := new(types.SlotInfo)
-		slotInfo.VanguardBlockHash = eth1Types.EmptyRootHash
-		slotInfo.PandoraHeaderHash = eth1Types.EmptyRootHash
-		slotInfos[i] = slotInfo
+	slots := make([]uint64, 0, 2001)
+	for slot := uint64(0); slot <= 2000; slot++ {
+		slots = append(slots, slot)
+	}
+	verifiedSlots := linkedVerifiedSlots(eth1Types.EmptyRootHash, slots...)
+	require.NoError(t, db.CommitVerifiedSlots(0, verifiedSlots))
 
-		require.NoError(t, db.SaveVerifiedSlotInfo(uint64(i), slotInfo))
+	retrievedSlotInfo, err := db.VerifiedSlotInfo(0, 0)
+	require.NoError(t, err)
+	assert.DeepEqual(t, verifiedSlots[0].SlotInfo, retrievedSlotInfo)
+}
+
+func TestStore_SaveVerifiedSlotInfos(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	slotInfos := make(map[uint64]*types.SlotInfo)
+	for _, slot := range []uint64{3, 4, 6} {
+		slotInfos[slot] = &types.SlotInfo{
+			VanguardBlockHash: eth1Types.EmptyRootHash,
+			PandoraHeaderHash: common.BytesToHash(bytesutil.Uint64ToBytesBigEndian(slot)),
+		}
+	}
+	require.NoError(t, db.SaveVerifiedSlotInfos(1, slotInfos))
+
+	for slot, slotInfo := range slotInfos {
+		retrievedSlotInfo, err := db.VerifiedSlotInfo(slot, 1)
+		require.NoError(t, err)
+		assert.DeepEqual(t, slotInfo, retrievedSlotInfo)
 	}
+	// latest pointers are stored with the slot infos
+	assert.Equal(t, uint64(6), db.InMemoryLatestVerifiedSlot(1))
+	assert.Equal(t, slotInfos[6].PandoraHeaderHash, db.InMemoryLatestVerifiedHeaderHash(1))
+	assert.Equal(t, uint64(6), db.LatestSavedVerifiedSlot(1))
+	assert.Equal(t, slotInfos[6].PandoraHeaderHash, db.LatestVerifiedHeaderHash(1))
+	assert.Equal(t, uint64(0), db.LatestSavedVerifiedSlot(0))
+}
 
-	retrievedSlotInfo, err := db.VerifiedSlotInfo(0)
+func TestStore_CommitVerifiedSlots_OneByOne(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	firstHeader := testutil.NewEth1Header(1)
+	secondHeader := testutil.NewEth1HeaderWithParent(2, firstHeader.Hash())
+	for slot, header := range []*eth1Types.Header{firstHeader, secondHeader} {
+		slotInfo := &types.SlotInfo{PandoraHeaderHash: header.Hash(), VanguardBlockHash: common.HexToHash("0x0a")}
+		shardInfo := testutil.NewVanguardShardInfo(uint64(slot+1), header)
+		require.NoError(t, db.CommitVerifiedSlots(1, []*types.VerifiedSlot{{
+			Slot:      uint64(slot + 1),
+			SlotInfo:  slotInfo,
+			Header:    header,
+			ShardInfo: shardInfo,
+		}}))
+	}
+
+	// slot info, indexes, payload and latest pointers are stored together
+	slotInfo, err := db.VerifiedSlotInfo(2, 1)
+	require.NoError(t, err)
+	require.NotNil(t, slotInfo)
+	assert.Equal(t, secondHeader.Hash(), slotInfo.PandoraHeaderHash)
+	shardSlot, err := db.SlotByPandoraHeaderHash(secondHeader.Hash())
+	require.NoError(t, err)
+	assert.DeepEqual(t, &types.ShardSlot{Slot: 2, ShardIndex: 1}, shardSlot)
+	header, err := db.VerifiedHeader(1, 1)
+	require.NoError(t, err)
+	require.NotNil(t, header)
+	assert.Equal(t, firstHeader.Hash(), header.Hash())
+	assert.Equal(t, uint64(2), db.LatestSavedVerifiedSlot(1))
+	assert.Equal(t, secondHeader.Hash(), db.LatestVerifiedHeaderHash(1))
+	assert.Equal(t, uint64(2), db.InMemoryLatestVerifiedSlot(1))
+
+	// a header which does not extend the latest verified header is not stored
+	forkHeader := testutil.NewEth1HeaderWithParent(3, firstHeader.Hash())
+	err = db.CommitVerifiedSlots(1, []*types.VerifiedSlot{{
+		Slot:     3,
+		SlotInfo: &types.SlotInfo{PandoraHeaderHash: forkHeader.Hash()},
+		Header:   forkHeader,
+	}})
+	var conflictErr *iface.VerifiedSlotConflictError
+	require.Equal(t, true, errors.As(err, &conflictErr))
+	assert.DeepEqual(t, &iface.VerifiedSlotConflictError{
+		Slot:               3,
+		ShardIndex:         1,
+		ParentHash:         firstHeader.Hash(),
+		LatestVerifiedSlot: 2,
+		LatestHeaderHash:   secondHeader.Hash(),
+	}, conflictErr)
+	slotInfo, err = db.VerifiedSlotInfo(3, 1)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+	assert.Equal(t, uint64(2), db.LatestSavedVerifiedSlot(1))
+}
+
+func TestStore_CommitVerifiedSlots(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	verifiedSlots := make([]*types.VerifiedSlot, 0)
+	parentHash := eth1Types.EmptyRootHash
+	for slot := uint64(1); slot <= 3; slot++ {
+		header := testutil.NewEth1HeaderWithParent(slot, parentHash)
+		parentHash = header.Hash()
+		verifiedSlots = append(verifiedSlots, &types.VerifiedSlot{
+			Slot:      slot,
+			SlotInfo:  &types.SlotInfo{PandoraHeaderHash: header.Hash()},
+			Header:    header,
+			ShardInfo: testutil.NewVanguardShardInfo(slot, header),
+			Verdicts:  []*types.RuleVerdict{{Rule: "test", Reason: types.NoMismatch}},
+		})
+	}
+
+	// a run whose second slot does not extend the first one is not stored at all
+	brokenRun := []*types.VerifiedSlot{verifiedSlots[0], verifiedSlots[2]}
+	err := db.CommitVerifiedSlots(0, brokenRun)
+	var conflictErr *iface.VerifiedSlotConflictError
+	require.Equal(t, true, errors.As(err, &conflictErr))
+	assert.Equal(t, uint64(3), conflictErr.Slot)
+	slotInfo, err := db.VerifiedSlotInfo(1, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+	verdicts, err := db.Verdicts(1, 0)
 	require.NoError(t, err)
-	assert.DeepEqual(t, slotInfos[0], retrievedSlotInfo)
+	assert.Equal(t, 0, len(verdicts))
+
+	// slot infos, verdicts, payloads and latest pointers of the run are stored together
+	require.NoError(t, db.CommitVerifiedSlots(0, verifiedSlots))
+	for _, verifiedSlot := range verifiedSlots {
+		slotInfo, err := db.VerifiedSlotInfo(verifiedSlot.Slot, 0)
+		require.NoError(t, err)
+		assert.DeepEqual(t, verifiedSlot.SlotInfo, slotInfo)
+		verdicts, err := db.Verdicts(verifiedSlot.Slot, 0)
+		require.NoError(t, err)
+		assert.DeepEqual(t, verifiedSlot.Verdicts, verdicts)
+		shardInfo, err := db.VerifiedShardInfo(verifiedSlot.Slot, 0)
+		require.NoError(t, err)
+		assert.DeepEqual(t, verifiedSlot.ShardInfo, shardInfo)
+	}
+	assert.Equal(t, uint64(3), db.LatestSavedVerifiedSlot(0))
+	assert.Equal(t, parentHash, db.LatestVerifiedHeaderHash(0))
+}
+
+func TestStore_RevertVerifiedSlotInfos(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	slotInfos := make(map[uint64]*types.SlotInfo)
+	verifiedSlots := linkedVerifiedSlots(eth1Types.EmptyRootHash, 1, 2, 4, 5, 6)
+	for _, verifiedSlot := range verifiedSlots {
+		slotInfos[verifiedSlot.Slot] = verifiedSlot.SlotInfo
+	}
+	require.NoError(t, db.CommitVerifiedSlots(0, verifiedSlots))
+
+	// slot infos of another shard are not reverted
+	shardSlots := linkedVerifiedSlots(eth1Types.EmptyRootHash, 5)
+	require.NoError(t, db.CommitVerifiedSlots(1, shardSlots))
+
+	revertedSlots, err := db.RevertVerifiedSlotInfos(5, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 2, len(revertedSlots))
+	for _, verifiedSlot := range verifiedSlots[3:] {
+		revertedSlot := revertedSlots[verifiedSlot.Slot]
+		require.NotNil(t, revertedSlot)
+		assert.Equal(t, verifiedSlot.Slot, revertedSlot.Slot)
+		assert.DeepEqual(t, verifiedSlot.SlotInfo, revertedSlot.SlotInfo)
+		assert.Equal(t, verifiedSlot.Header.Hash(), revertedSlot.Header.Hash())
+		assert.DeepEqual(t, verifiedSlot.ShardInfo, revertedSlot.ShardInfo)
+	}
+	assert.Equal(t, uint64(4), db.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, slotInfos[4].PandoraHeaderHash, db.InMemoryLatestVerifiedHeaderHash(0))
+	assert.Equal(t, uint64(4), db.LatestSavedVerifiedSlot(0))
+	assert.Equal(t, slotInfos[4].PandoraHeaderHash, db.LatestVerifiedHeaderHash(0))
+
+	slotInfo, err := db.VerifiedSlotInfo(5, 0)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), slotInfo)
+
+	slotInfo, err = db.VerifiedSlotInfo(5, 1)
+	require.NoError(t, err)
+	assert.DeepEqual(t, shardSlots[0].SlotInfo, slotInfo)
+
+	// slot 3 is not verified so the latest verified slot goes back to slot 2
+	revertedSlots, err = db.RevertVerifiedSlotInfos(3, 0)
+	require.NoError(t, err)
+	assert.Equal(t, 1, len(revertedSlots))
+	assert.Equal(t, uint64(2), db.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, slotInfos[2].PandoraHeaderHash, db.InMemoryLatestVerifiedHeaderHash(0))
+}
+
+func TestStore_VerifiedSlotInfo_Shards(t *testing.T) {
+	db := setupDB(t, false)
+	defer db.ClearDB()
+
+	verifiedSlots := linkedVerifiedSlots(eth1Types.EmptyRootHash, 10)
+	shardSlots := linkedVerifiedSlots(eth1Types.EmptyUncleHash, 12)
+	require.NoError(t, db.CommitVerifiedSlots(0, verifiedSlots))
+	require.NoError(t, db.CommitVerifiedSlots(1, shardSlots))
+	shardSlotInfo := shardSlots[0].SlotInfo
+
+	retrievedSlotInfo, err := db.VerifiedSlotInfo(10, 1)
+	require.NoError(t, err)
+	assert.Equal(t, (*types.SlotInfo)(nil), retrievedSlotInfo)
+	retrievedSlotInfo, err = db.VerifiedSlotInfo(12, 1)
+	require.NoError(t, err)
+	assert.DeepEqual(t, shardSlotInfo, retrievedSlotInfo)
+
+	// latest verified info of every shard is stored when db is closed
+	require.NoError(t, db.Close())
+	db = setupDB(t, false)
+	assert.Equal(t, uint64(10), db.InMemoryLatestVerifiedSlot(0))
+	assert.Equal(t, uint64(12), db.InMemoryLatestVerifiedSlot(1))
+	assert.Equal(t, shardSlotInfo.PandoraHeaderHash, db.InMemoryLatestVerifiedHeaderHash(1))
+	assert.Equal(t, uint64(0), db.InMemoryLatestVerifiedSlot(2))
+	require.NoError(t, db.Close())
+}
+
+func TestStore_LatestFinalizedSlot(t *testing.T) {
+	t.Parallel()
+	db := setupDB(t, true)
+	assert.Equal(t, uint64(0), db.LatestFinalizedSlot(0))
+
+	require.NoError(t, db.SaveLatestFinalizedSlot(64, 0))
+	require.NoError(t, db.SaveLatestFinalizedSlot(32, 1))
+	assert.Equal(t, uint64(64), db.LatestFinalizedSlot(0))
+	assert.Equal(t, uint64(32), db.LatestFinalizedSlot(1))
 }
@@ -22,6 +22,7 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"strings"
 	"sync"
 	"syscall"
 	"time"
@@ -54,19 +55,21 @@ func New(cliCtx *cli.Context) (*OrchestratorNode, error) {
 	ctx, cancel := context.WithCancel(cliCtx.Context)
 
 	orchestrator := &OrchestratorNode{
-		cliCtx:            cliCtx,
-		ctx:               ctx,
-		cancel:            cancel,
-		services:          registry,
-		stop:              make(chan struct{}),
-		pandoraInfoCache:  cache.NewPanHeaderCache(),
-		vanShardInfoCache: cache.NewVanShardInfoCache(math.MaxInt32),
+		cliCtx:   cliCtx,
+		ctx:      ctx,
+		cancel:   cancel,
+		services: registry,
+		stop:     make(chan struct{}),
 	}
 
 	if err := orchestrator.startDB(orchestrator.cliCtx); err != nil {
 		return nil, err
 	}
 
+	if err := orchestrator.loadPendingCaches(); err != nil {
+		return nil, err
+	}
+
 	if err := orchestrator.registerVanguardChainService(cliCtx); err != nil {
 		return nil, err
 	}
@@ -83,6 +86,14 @@ func New(cliCtx *cli.Context) (*OrchestratorNode, error) {
 		return nil, err
 	}
 
+	if err := orchestrator.registerPruner(cliCtx); err != nil {
+		return nil, err
+	}
+
+	if err := orchestrator.registerPeriodicBackup(cliCtx); err != nil {
+		return nil, err
+	}
+
 	return orchestrator, nil
 }
 
@@ -133,6 +144,25 @@ func (o *OrchestratorNode) startDB(cliCtx *cli.Context) error {
 	return nil
 }
 
+// loadPendingCaches creates the pending caches on top of the db. Pandora headers and vanguard shard infos which
+// were pending when the node stopped are reloaded so that verification resumes where it left off.
+func (o *OrchestratorNode) loadPendingCaches() error {
+	pandoraInfoCache, err := cache.NewPersistentPanHeaderCache(o.db)
+	if err != nil {
+		return errors.Wrap(err, "could not load pending pandora headers")
+	}
+	vanShardInfoCache, err := cache.NewPersistentVanShardInfoCache(math.MaxInt32, o.db)
+	if err != nil {
+		return errors.Wrap(err, "could not load pending vanguard shard infos")
+	}
+	o.pandoraInfoCache = pandoraInfoCache
+	o.vanShardInfoCache = vanShardInfoCache
+
+	log.WithField("pendingHeaders", len(pandoraInfoCache.Keys())).
+		WithField("pendingShardInfos", len(vanShardInfoCache.Keys())).Info("Loaded pending caches")
+	return nil
+}
+
 // registerVanguardChainService
 func (o *OrchestratorNode) registerVanguardChainService(cliCtx *cli.Context) error {
 	vanguardGRPCUrl := cliCtx.String(cmd.VanguardGRPCEndpoint.Name)
@@ -156,6 +186,11 @@ func (o *OrchestratorNode) registerVanguardChainService(cliCtx *cli.Context) err
 // registerPandoraChainService
 func (o *OrchestratorNode) registerPandoraChainService(cliCtx *cli.Context) error {
 	pandoraRPCUrl := cliCtx.String(cmd.PandoraRPCEndpoint.Name)
+	// every comma separated endpoint serves one shard, the position of the endpoint is the shard index
+	pandoraRPCUrls := strings.Split(pandoraRPCUrl, ",")
+	for i := range pandoraRPCUrls {
+		pandoraRPCUrls[i] = strings.TrimSpace(pandoraRPCUrls[i])
+	}
 	dialRPCClient := func(endpoint string) (*ethRpc.Client, error) {
 		rpcClient, err := ethRpc.Dial(endpoint)
 		if err != nil {
@@ -164,11 +199,12 @@ func (o *OrchestratorNode) registerPandoraChainService(cliCtx *cli.Context) erro
 		return rpcClient, nil
 	}
 	namespace := "eth"
-	svc, err := pandorachain.NewService(o.ctx, pandoraRPCUrl, namespace, o.db, o.pandoraInfoCache, dialRPCClient)
+	svc, err := pandorachain.NewShardedService(o.ctx, pandoraRPCUrls, namespace, o.db, o.pandoraInfoCache, dialRPCClient)
 	if err != nil {
 		return nil
 	}
-	log.WithField("pandoraHttpUrl", pandoraRPCUrl).Info("Registered pandora chain service")
+	log.WithField("pandoraHttpUrl", pandoraRPCUrl).WithField("shards", len(pandoraRPCUrls)).
+		Info("Registered pandora chain service")
 	return o.services.RegisterService(svc)
 }
 
@@ -179,21 +215,43 @@ func (o *OrchestratorNode) registerConsensusService(cliCtx *cli.Context) error {
 		return err
 	}
 
-	var pandoraHeaderFeed *pandorachain.Service
+	var pandoraHeaderFeed *pandorachain.ShardedService
 	if err := o.services.FetchService(&pandoraHeaderFeed); err != nil {
 		return err
 	}
 
-	svc := consensus.New(o.ctx, &consensus.Config{
+	rules := make([]string, 0)
+	for _, rule := range strings.Split(cliCtx.String(cmd.VerificationRulesFlag.Name), ",") {
+		if rule = strings.TrimSpace(rule); rule != "" {
+			rules = append(rules, rule)
+		}
+	}
+	svc, err := consensus.New(o.ctx, &consensus.Config{
+		ConsensusInfoDB:              o.db,
 		VerifiedSlotInfoDB:           o.db,
 		InvalidSlotInfoDB:            o.db,
+		SkippedSlotInfoDB:            o.db,
+		TimedOutSlotInfoDB:           o.db,
+		EquivocationDB:               o.db,
+		VerdictDB:                    o.db,
+		VerifiedPayloadDB:            o.db,
 		VanguardPendingShardingCache: o.vanShardInfoCache,
 		PandoraPendingHeaderCache:    o.pandoraInfoCache,
 		VanguardShardFeed:            vanguardShardFeed,
 		PandoraHeaderFeed:            pandoraHeaderFeed,
+		FinalizedCheckpointFeed:      vanguardShardFeed,
+		ConsensusInfoFeed:            vanguardShardFeed,
+		ShardCount:                   pandoraHeaderFeed.ShardCount(),
+		VerificationRules:            rules,
+		SlotTimeTolerance:            cliCtx.Uint64(cmd.SlotTimeToleranceFlag.Name),
+		PendingSlotTimeout:           cliCtx.Uint64(cmd.PendingSlotTimeoutFlag.Name),
+		CatchUpBatchSize:             cliCtx.Uint64(cmd.CatchUpBatchSizeFlag.Name),
 	})
+	if err != nil {
+		return err
+	}
 
-	log.Info("Registered consensus service")
+	log.WithField("verificationRules", rules).Info("Registered consensus service")
 	return o.services.RegisterService(svc)
 }
 
@@ -243,6 +301,8 @@ func (o *OrchestratorNode) registerRPCService(cliCtx *cli.Context) error {
 		VanguardPendingShardingCache: o.vanShardInfoCache,
 		PandoraPendingHeaderCache:    o.pandoraInfoCache,
 		VerifiedSlotInfoFeed:         verifiedSlotInfoFeed,
+		VerificationGapReader:        verifiedSlotInfoFeed,
+		DBBackupDir:                  cliCtx.String(cmd.DBBackupDirFlag.Name),
 	})
 	if err != nil {
 		return nil
@@ -252,6 +312,59 @@ func (o *OrchestratorNode) registerRPCService(cliCtx *cli.Context) error {
 	return o.services.RegisterService(svc)
 }
 
+// registerPruner prunes old slot infos and consensus infos in the background when a prune policy is set. Verified
+// payloads are also pruned when their retention is set.
+func (o *OrchestratorNode) registerPruner(cliCtx *cli.Context) error {
+	cfg := &kv.PruneConfig{
+		Policy:              kv.PrunePolicy(cliCtx.String(cmd.DBPrunePolicyFlag.Name)),
+		RetainEpochs:        cliCtx.Uint64(cmd.DBPruneRetainEpochsFlag.Name),
+		PayloadRetainEpochs: cliCtx.Uint64(cmd.DBPayloadRetainEpochsFlag.Name),
+		Interval:            cliCtx.Duration(cmd.DBPruneIntervalFlag.Name),
+	}
+	if cfg.Policy == "" {
+		cfg.Policy = kv.PruneNone
+	}
+	// pruning is disabled by default
+	if cfg.Policy == kv.PruneNone && cfg.PayloadRetainEpochs == 0 {
+		return nil
+	}
+	store, ok := o.db.(*kv.Store)
+	if !ok {
+		return errors.New("database does not support pruning")
+	}
+	pruner, err := kv.NewPruner(o.ctx, store, cfg)
+	if err != nil {
+		return err
+	}
+
+	log.WithField("policy", cfg.Policy).WithField("retainEpochs", cfg.RetainEpochs).
+		WithField("payloadRetainEpochs", cfg.PayloadRetainEpochs).WithField("interval", cfg.Interval).
+		Info("Registered database pruner")
+	return o.services.RegisterService(pruner)
+}
+
+// registerPeriodicBackup backs up the database in the background when a backup directory is set
+func (o *OrchestratorNode) registerPeriodicBackup(cliCtx *cli.Context) error {
+	backupDir := cliCtx.String(cmd.DBBackupDirFlag.Name)
+	if backupDir == "" {
+		return nil
+	}
+	store, ok := o.db.(*kv.Store)
+	if !ok {
+		return errors.New("database does not support periodic backups")
+	}
+	interval := cliCtx.Duration(cmd.DBBackupIntervalFlag.Name)
+	keep := cliCtx.Int(cmd.DBBackupKeepFlag.Name)
+	backup, err := kv.NewPeriodicBackup(o.ctx, store, backupDir, interval, keep)
+	if err != nil {
+		return err
+	}
+
+	log.WithField("backupDir", backupDir).WithField("interval", interval).WithField("keep", keep).
+		Info("Registered periodic database backup")
+	return o.services.RegisterService(backup)
+}
+
 // Start the OrchestratorNode and kicks off every registered service.
 func (o *OrchestratorNode) Start() {
 	o.lock.Lock()
@@ -18,13 +18,15 @@ func (s *Service) OnNewPendingHeader(ctx context.Context, header *eth1Types.Head
 	}
 
 	log.WithField("slot", panExtraDataWithSig.Slot).
+		WithField("shardIndex", s.shardIndex).
 		WithField("blockNumber", header.Number.Uint64()).
 		WithField("headerHash", header.Hash()).
 		Info("New pandora header info has arrived")
 
 	s.pandoraHeaderInfoFeed.Send(&types.PandoraHeaderInfo{
-		Header: header,
-		Slot:   panExtraDataWithSig.Slot,
+		Header:     header,
+		Slot:       panExtraDataWithSig.Slot,
+		ShardIndex: s.shardIndex,
 	})
 	return nil
 }
@@ -31,8 +31,9 @@ type Service struct {
 	runError       error
 
 	// pandora chain related attributes
-	connected bool
-	endpoint  string
+	connected  bool
+	endpoint   string
+	shardIndex uint64
 	rpcClient *rpc.Client
 	dialRPCFn DialRPCFn
 	namespace string
@@ -49,10 +50,11 @@ type Service struct {
 	pandoraHeaderInfoFeed event.Feed
 }
 
-// NewService creates new service with pandora ws or ipc endpoint, pandora service namespace and db
+// NewService creates new service with pandora ws or ipc endpoint of a shard, pandora service namespace and db
 func NewService(
 	ctx context.Context,
 	endpoint string,
+	shardIndex uint64,
 	namespace string,
 	db db.Database,
 	cache cache.PandoraHeaderCache,
@@ -65,6 +67,7 @@ func NewService(
 		ctx:             ctx,
 		cancel:          cancel,
 		endpoint:        endpoint,
+		shardIndex:      shardIndex,
 		dialRPCFn:       dialRPCFn,
 		namespace:       namespace,
 		conInfoSubErrCh: make(chan error),
@@ -208,7 +211,7 @@ func (s *Service) retryToConnectAndSubscribe(err error) {
 
 // subscribe subscribes to pandora events
 func (s *Service) subscribe() error {
-	latestSavedHeaderHash := s.db.InMemoryLatestVerifiedHeaderHash()
+	latestSavedHeaderHash := s.db.InMemoryLatestVerifiedHeaderHash(s.shardIndex)
 	filter := &types.PandoraPendingHeaderFilter{
 		FromBlockHash: latestSavedHeaderHash,
 	}
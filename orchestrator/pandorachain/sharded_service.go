@@ -0,0 +1,95 @@
+package pandorachain
+
+import (
+	"context"
+
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/cache"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
+)
+
+// ShardedService
+//   - maintains one pandora chain service per shard. The position of an endpoint is its shard index
+//   - joins the header info feeds of all the shards into one feed for consensus service
+type ShardedService struct {
+	services []*Service
+	scope    event.SubscriptionScope
+}
+
+// NewShardedService creates a pandora chain service for every shard endpoint
+func NewShardedService(
+	ctx context.Context,
+	endpoints []string,
+	namespace string,
+	db db.Database,
+	cache cache.PandoraHeaderCache,
+	dialRPCFn DialRPCFn,
+) (*ShardedService, error) {
+
+	services := make([]*Service, 0, len(endpoints))
+	for shardIndex, endpoint := range endpoints {
+		svc, err := NewService(ctx, endpoint, uint64(shardIndex), namespace, db, cache, dialRPCFn)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, svc)
+	}
+	return &ShardedService{services: services}, nil
+}
+
+// Start starts pandora chain service of every shard
+func (s *ShardedService) Start() {
+	for _, svc := range s.services {
+		svc.Start()
+	}
+}
+
+// Stop stops pandora chain service of every shard
+func (s *ShardedService) Stop() error {
+	s.scope.Close()
+	for _, svc := range s.services {
+		if err := svc.Stop(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Status returns the first error of the shard services
+func (s *ShardedService) Status() error {
+	for _, svc := range s.services {
+		if err := svc.Status(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Shard returns the pandora chain service of the shard or nil if the shard is unknown
+func (s *ShardedService) Shard(shardIndex uint64) *Service {
+	if shardIndex >= uint64(len(s.services)) {
+		return nil
+	}
+	return s.services[shardIndex]
+}
+
+// ShardCount returns the number of shards
+func (s *ShardedService) ShardCount() uint64 {
+	return uint64(len(s.services))
+}
+
+// SubscribeHeaderInfoEvent subscribes the channel to the header info feed of every shard
+func (s *ShardedService) SubscribeHeaderInfoEvent(ch chan<- *types.PandoraHeaderInfo) event.Subscription {
+	subs := make([]event.Subscription, 0, len(s.services))
+	for _, svc := range s.services {
+		subs = append(subs, svc.SubscribeHeaderInfoEvent(ch))
+	}
+	return s.scope.Track(event.NewSubscription(func(quit <-chan struct{}) error {
+		<-quit
+		for _, sub := range subs {
+			sub.Unsubscribe()
+		}
+		return nil
+	}))
+}
@@ -83,6 +83,7 @@ func SetupPandoraSvc(ctx context.Context, t *testing.T, dialRPCFn DialRPCFn) *Se
 	svc, err := NewService(
 		ctx,
 		"ws://127.0.0.1:8546",
+		0,
 		"eth",
 		testDB.SetupDB(t),
 		cache.NewPanHeaderCache(),
@@ -0,0 +1,27 @@
+package admin
+
+import (
+	"context"
+
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
+)
+
+// PrivateAdminAPI offers maintenance methods of the node. It is not public, so it is only served over IPC.
+type PrivateAdminAPI struct {
+	db        db.BackupDB
+	backupDir string
+}
+
+// NewPrivateAdminAPI creates the admin api. Backups are written into the on-demand directory of the backup directory,
+// or of the backups directory of the database when it is empty, so that periodic backups do not rotate them.
+func NewPrivateAdminAPI(db db.BackupDB, backupDir string) *PrivateAdminAPI {
+	return &PrivateAdminAPI{
+		db:        db,
+		backupDir: backupDir,
+	}
+}
+
+// Backup writes a snapshot of the running database and returns the path of the snapshot
+func (api *PrivateAdminAPI) Backup(ctx context.Context) (string, error) {
+	return api.db.OnDemandBackup(ctx, api.backupDir)
+}
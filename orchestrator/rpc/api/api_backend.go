@@ -22,10 +22,18 @@ type Backend struct {
 	ConsensusInfoFeed    iface.ConsensusInfoFeed
 	VerifiedSlotInfoFeed conIface.VerifiedSlotInfoFeed
 
+	// consensus service state
+	VerificationGapReader conIface.VerificationGapReader
+
 	// db reference
 	ConsensusInfoDB    db.ROnlyConsensusInfoDB
 	VerifiedSlotInfoDB db.ROnlyVerifiedSlotInfoDB
 	InvalidSlotInfoDB  db.ROnlyInvalidSlotInfoDB
+	SkippedSlotInfoDB  db.ROnlySkippedSlotInfoDB
+	TimedOutSlotInfoDB db.ROnlyTimedOutSlotInfoDB
+	EquivocationDB     db.ROnlyEquivocationDB
+	HashIndexDB        db.ROnlyHashIndexDB
+	VerifiedPayloadDB  db.ROnlyVerifiedPayloadDB
 
 	// cache reference
 	VanguardPendingShardingCache cache.VanguardShardCache
@@ -48,20 +56,110 @@ func (backend *Backend) ConsensusInfoByEpochRange(fromEpoch uint64) []*types.Min
 	return consensusInfos
 }
 
-func (backend *Backend) VerifiedSlotInfos(fromSlot uint64) map[uint64]*types.SlotInfo {
-	slotInfos, err := backend.VerifiedSlotInfoDB.VerifiedSlotInfos(fromSlot)
+func (backend *Backend) ConsensusInfoByEpoch(ctx context.Context, epoch uint64) *types.MinimalEpochConsensusInfo {
+	consensusInfo, err := backend.ConsensusInfoDB.ConsensusInfo(ctx, epoch)
+	if err != nil {
+		return nil
+	}
+	return consensusInfo
+}
+
+func (backend *Backend) VerifiedSlotInfos(fromSlot uint64, shardIndex uint64) map[uint64]*types.SlotInfo {
+	slotInfos, err := backend.VerifiedSlotInfoDB.VerifiedSlotInfos(fromSlot, shardIndex)
 	if err != nil {
 		return nil
 	}
 	return slotInfos
 }
 
+func (backend *Backend) VerifiedSlotInfo(slot uint64, shardIndex uint64) *types.SlotInfo {
+	slotInfo, err := backend.VerifiedSlotInfoDB.VerifiedSlotInfo(slot, shardIndex)
+	if err != nil {
+		return nil
+	}
+	return slotInfo
+}
+
+func (backend *Backend) VerifiedHeader(slot uint64, shardIndex uint64) *eth1Types.Header {
+	header, err := backend.VerifiedPayloadDB.VerifiedHeader(slot, shardIndex)
+	if err != nil {
+		return nil
+	}
+	return header
+}
+
+func (backend *Backend) VerifiedShardInfo(slot uint64, shardIndex uint64) *types.VanguardShardInfo {
+	shardInfo, err := backend.VerifiedPayloadDB.VerifiedShardInfo(slot, shardIndex)
+	if err != nil {
+		return nil
+	}
+	return shardInfo
+}
+
+// SlotByHash returns the slot of a pandora header hash when requestFrom is true, otherwise of a vanguard block hash.
+// It returns nil when no verified or invalid slot holds the hash.
+func (backend *Backend) SlotByHash(hash common.Hash, requestFrom bool) *types.ShardSlot {
+	var (
+		shardSlot *types.ShardSlot
+		err       error
+	)
+	if requestFrom {
+		shardSlot, err = backend.HashIndexDB.SlotByPandoraHeaderHash(hash)
+	} else {
+		shardSlot, err = backend.HashIndexDB.SlotByVanguardBlockHash(hash)
+	}
+	if err != nil {
+		return nil
+	}
+	return shardSlot
+}
+
+func (backend *Backend) InvalidSlotInfo(slot uint64, shardIndex uint64) *types.InvalidSlotInfo {
+	invalidSlotInfo, err := backend.InvalidSlotInfoDB.InvalidSlotInfo(slot, shardIndex)
+	if err != nil {
+		return nil
+	}
+	return invalidSlotInfo
+}
+
+func (backend *Backend) SkippedSlotInfo(slot uint64, shardIndex uint64) *types.SlotInfo {
+	slotInfo, err := backend.SkippedSlotInfoDB.SkippedSlotInfo(slot, shardIndex)
+	if err != nil {
+		return nil
+	}
+	return slotInfo
+}
+
+func (backend *Backend) TimedOutSlotInfo(slot uint64, shardIndex uint64) *types.SlotInfo {
+	slotInfo, err := backend.TimedOutSlotInfoDB.TimedOutSlotInfo(slot, shardIndex)
+	if err != nil {
+		return nil
+	}
+	return slotInfo
+}
+
+func (backend *Backend) Equivocations(fromSlot uint64) []*types.Equivocation {
+	equivocations, err := backend.EquivocationDB.Equivocations(fromSlot)
+	if err != nil {
+		return nil
+	}
+	return equivocations
+}
+
+func (backend *Backend) VerificationGaps() []*types.VerificationGap {
+	return backend.VerificationGapReader.VerificationGaps()
+}
+
 func (backend *Backend) LatestEpoch() uint64 {
 	return backend.ConsensusInfoDB.LatestSavedEpoch()
 }
 
-func (backend *Backend) LatestVerifiedSlot() uint64 {
-	return backend.VerifiedSlotInfoDB.LatestSavedVerifiedSlot()
+func (backend *Backend) LatestVerifiedSlot(shardIndex uint64) uint64 {
+	return backend.VerifiedSlotInfoDB.LatestSavedVerifiedSlot(shardIndex)
+}
+
+func (backend *Backend) LatestFinalizedSlot(shardIndex uint64) uint64 {
+	return backend.VerifiedSlotInfoDB.LatestFinalizedSlot(shardIndex)
 }
 
 func (backed *Backend) PendingPandoraHeaders() []*eth1Types.Header {
@@ -73,22 +171,23 @@ func (backed *Backend) PendingPandoraHeaders() []*eth1Types.Header {
 }
 
 // GetSlotStatus
-func (backend *Backend) GetSlotStatus(ctx context.Context, slot uint64, hash common.Hash, requestFrom bool) types.Status {
+func (backend *Backend) GetSlotStatus(ctx context.Context, slot uint64, shardIndex uint64, hash common.Hash, requestFrom bool) types.Status {
 	// by default if nothing is found then return skipped
 	status := types.Pending
 
 	//when requested slot is greater than latest verified slot
-	latestVerifiedSlot := backend.VerifiedSlotInfoDB.InMemoryLatestVerifiedSlot()
+	latestVerifiedSlot := backend.VerifiedSlotInfoDB.InMemoryLatestVerifiedSlot(shardIndex)
 	var slotInfo *types.SlotInfo
 
 	logPrinter := func(stat types.Status) {
 		log.WithField("slot", slot).
+			WithField("shardIndex", shardIndex).
 			WithField("latestVerifiedSlot", latestVerifiedSlot).
 			WithField("status", stat).
 			Debug("Verification status")
 	}
 	// finally found in the database so return immediately so that no other db call happens
-	if slotInfo, _ = backend.VerifiedSlotInfoDB.VerifiedSlotInfo(slot); slotInfo != nil {
+	if slotInfo, _ = backend.VerifiedSlotInfoDB.VerifiedSlotInfo(slot, shardIndex); slotInfo != nil {
 		panHeaderHash := slotInfo.PandoraHeaderHash
 		vanHeaderHash := slotInfo.VanguardBlockHash
 
@@ -107,16 +206,34 @@ func (backend *Backend) GetSlotStatus(ctx context.Context, slot uint64, hash com
 		}
 
 		status = types.Verified
-		logPrinter(types.Verified)
+		// verified slot is irreversible once vanguard has finalized it
+		if slot <= backend.VerifiedSlotInfoDB.LatestFinalizedSlot(shardIndex) {
+			status = types.Finalized
+		}
+		logPrinter(status)
 		return status
 	}
 
 	// finally found in the database so return immediately so that no other db call happens
-	if slotInfo, _ = backend.InvalidSlotInfoDB.InvalidSlotInfo(slot); slotInfo != nil {
+	if invalidSlotInfo, _ := backend.InvalidSlotInfoDB.InvalidSlotInfo(slot, shardIndex); invalidSlotInfo != nil {
 		status = types.Invalid
 		logPrinter(types.Invalid)
 		return status
 	}
+
+	// slot is dropped because a later slot has already been verified
+	if slotInfo, _ = backend.SkippedSlotInfoDB.SkippedSlotInfo(slot, shardIndex); slotInfo != nil {
+		status = types.Skipped
+		logPrinter(types.Skipped)
+		return status
+	}
+
+	// slot is not matched before its deadline
+	if slotInfo, _ = backend.TimedOutSlotInfoDB.TimedOutSlotInfo(slot, shardIndex); slotInfo != nil {
+		status = types.TimedOut
+		logPrinter(types.TimedOut)
+		return status
+	}
 	logPrinter(status)
 	return status
 }
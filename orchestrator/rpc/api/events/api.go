@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/rpc"
 	generalTypes "github.com/lukso-network/lukso-orchestrator/shared/types"
 	"github.com/pkg/errors"
+	eth2Types "github.com/prysmaticlabs/prysm/proto/eth/v1alpha1"
 	"time"
 )
 
@@ -16,12 +17,23 @@ var lastSendEpoch uint64
 
 type Backend interface {
 	ConsensusInfoByEpochRange(fromEpoch uint64) []*generalTypes.MinimalEpochConsensusInfo
+	ConsensusInfoByEpoch(ctx context.Context, epoch uint64) *generalTypes.MinimalEpochConsensusInfo
 	SubscribeNewEpochEvent(chan<- *generalTypes.MinimalEpochConsensusInfo) event.Subscription
-	GetSlotStatus(ctx context.Context, slot uint64, hash common.Hash, requestFrom bool) generalTypes.Status
+	GetSlotStatus(ctx context.Context, slot uint64, shardIndex uint64, hash common.Hash, requestFrom bool) generalTypes.Status
 	LatestEpoch() uint64
 	SubscribeNewVerifiedSlotInfoEvent(chan<- *generalTypes.SlotInfoWithStatus) event.Subscription
-	VerifiedSlotInfos(fromSlot uint64) map[uint64]*generalTypes.SlotInfo
-	LatestVerifiedSlot() uint64
+	VerifiedSlotInfo(slot uint64, shardIndex uint64) *generalTypes.SlotInfo
+	VerifiedSlotInfos(fromSlot uint64, shardIndex uint64) map[uint64]*generalTypes.SlotInfo
+	SlotByHash(hash common.Hash, requestFrom bool) *generalTypes.ShardSlot
+	VerifiedHeader(slot uint64, shardIndex uint64) *eth1Types.Header
+	VerifiedShardInfo(slot uint64, shardIndex uint64) *generalTypes.VanguardShardInfo
+	InvalidSlotInfo(slot uint64, shardIndex uint64) *generalTypes.InvalidSlotInfo
+	SkippedSlotInfo(slot uint64, shardIndex uint64) *generalTypes.SlotInfo
+	TimedOutSlotInfo(slot uint64, shardIndex uint64) *generalTypes.SlotInfo
+	Equivocations(fromSlot uint64) []*generalTypes.Equivocation
+	VerificationGaps() []*generalTypes.VerificationGap
+	LatestVerifiedSlot(shardIndex uint64) uint64
+	LatestFinalizedSlot(shardIndex uint64) uint64
 	PendingPandoraHeaders() []*eth1Types.Header
 }
 
@@ -34,8 +46,9 @@ type PublicFilterAPI struct {
 }
 
 type BlockHash struct {
-	Slot uint64      `json:"slot"`
-	Hash common.Hash `json:"hash"`
+	Slot       uint64      `json:"slot"`
+	ShardIndex uint64      `json:"shardIndex"`
+	Hash       common.Hash `json:"hash"`
 }
 
 type BlockStatus struct {
@@ -43,6 +56,66 @@ type BlockStatus struct {
 	Status generalTypes.Status
 }
 
+type InvalidSlotInfo struct {
+	Slot              uint64                      `json:"slot"`
+	ShardIndex        uint64                      `json:"shardIndex"`
+	PandoraHeaderHash common.Hash                 `json:"pandoraHeaderHash"`
+	VanguardBlockHash common.Hash                 `json:"vanguardBlockHash"`
+	Reason            generalTypes.MismatchReason `json:"reason"`
+}
+
+// SlotInfo holds the hashes of a verified or invalid slot along with its current status
+type SlotInfo struct {
+	Slot              uint64              `json:"slot"`
+	ShardIndex        uint64              `json:"shardIndex"`
+	PandoraHeaderHash common.Hash         `json:"pandoraHeaderHash"`
+	VanguardBlockHash common.Hash         `json:"vanguardBlockHash"`
+	Status            generalTypes.Status `json:"status"`
+}
+
+// ShardInfo is the pandora shard of a vanguard block which is verified for a slot
+type ShardInfo struct {
+	Slot              uint64                  `json:"slot"`
+	ShardIndex        uint64                  `json:"shardIndex"`
+	VanguardBlockHash common.Hash             `json:"vanguardBlockHash"`
+	ShardInfo         *eth2Types.PandoraShard `json:"shardInfo"`
+}
+
+// SlotTime holds the time window of a slot in unix seconds
+type SlotTime struct {
+	Slot      uint64 `json:"slot"`
+	StartTime uint64 `json:"startTime"`
+	EndTime   uint64 `json:"endTime"`
+}
+
+// Equivocation is the slashing evidence of a double proposal. It holds both conflicting vanguard blocks or both
+// conflicting signed pandora headers of the slot proposer.
+type Equivocation struct {
+	Kind              generalTypes.EquivocationKind `json:"kind"`
+	Slot              uint64                        `json:"slot"`
+	ShardIndex        uint64                        `json:"shardIndex"`
+	ProposerIndex     uint64                        `json:"proposerIndex"`
+	ProposerPublicKey string                        `json:"proposerPublicKey"`
+	VanguardBlocks    []*VanguardBlockEvidence      `json:"vanguardBlocks,omitempty"`
+	PandoraHeaders    []*eth1Types.Header           `json:"pandoraHeaders,omitempty"`
+}
+
+// VerificationGap tells that the pending slot waits for the missing parent slots after the latest verified slot of
+// its shard
+type VerificationGap struct {
+	ShardIndex         uint64      `json:"shardIndex"`
+	LatestVerifiedSlot uint64      `json:"latestVerifiedSlot"`
+	LatestHeaderHash   common.Hash `json:"latestHeaderHash"`
+	WaitingSlot        uint64      `json:"waitingSlot"`
+	ParentHash         common.Hash `json:"parentHash"`
+}
+
+// VanguardBlockEvidence holds a vanguard block hash with the pandora shard which is proposed in the block
+type VanguardBlockEvidence struct {
+	BlockHash common.Hash             `json:"blockHash"`
+	ShardInfo *eth2Types.PandoraShard `json:"shardInfo"`
+}
+
 // NewPublicFilterAPI returns a new PublicFilterAPI instance.
 func NewPublicFilterAPI(backend Backend, timeout time.Duration) *PublicFilterAPI {
 	api := &PublicFilterAPI{
@@ -65,14 +138,15 @@ func (api *PublicFilterAPI) ConfirmPanBlockHashes(
 	}
 	res := make([]*BlockStatus, 0)
 	for _, req := range requests {
-		status := api.backend.GetSlotStatus(ctx, req.Slot, req.Hash, true)
-		log.WithField("slot", req.Slot).WithField("status", status).WithField(
+		status := api.backend.GetSlotStatus(ctx, req.Slot, req.ShardIndex, req.Hash, true)
+		log.WithField("slot", req.Slot).WithField("shardIndex", req.ShardIndex).WithField("status", status).WithField(
 			"api", "ConfirmPanBlockHashes").Debug("status of the requested slot")
 		hash := req.Hash
 		res = append(res, &BlockStatus{
 			BlockHash: BlockHash{
-				Slot: req.Slot,
-				Hash: hash,
+				Slot:       req.Slot,
+				ShardIndex: req.ShardIndex,
+				Hash:       hash,
 			},
 			Status: status,
 		})
@@ -91,14 +165,15 @@ func (api *PublicFilterAPI) ConfirmVanBlockHashes(
 	}
 	res := make([]*BlockStatus, 0)
 	for _, req := range requests {
-		status := api.backend.GetSlotStatus(ctx, req.Slot, req.Hash, false)
-		log.WithField("slot", req.Slot).WithField("status", status).WithField(
+		status := api.backend.GetSlotStatus(ctx, req.Slot, req.ShardIndex, req.Hash, false)
+		log.WithField("slot", req.Slot).WithField("shardIndex", req.ShardIndex).WithField("status", status).WithField(
 			"api", "ConfirmVanBlockHashes").Debug("Status of the requested slot")
 		hash := req.Hash
 		res = append(res, &BlockStatus{
 			BlockHash: BlockHash{
-				Slot: req.Slot,
-				Hash: hash,
+				Slot:       req.Slot,
+				ShardIndex: req.ShardIndex,
+				Hash:       hash,
 			},
 			Status: status,
 		})
@@ -106,6 +181,158 @@ func (api *PublicFilterAPI) ConfirmVanBlockHashes(
 	return res, nil
 }
 
+// GetInvalidSlotInfo returns the hashes of an invalid slot of a shard and the reason why it did not pass the
+// verification. Returns nil when the slot is not invalid.
+func (api *PublicFilterAPI) GetInvalidSlotInfo(
+	ctx context.Context,
+	slot uint64,
+	shardIndex uint64,
+) (*InvalidSlotInfo, error) {
+	invalidSlotInfo := api.backend.InvalidSlotInfo(slot, shardIndex)
+	if invalidSlotInfo == nil {
+		return nil, nil
+	}
+	return &InvalidSlotInfo{
+		Slot:              slot,
+		ShardIndex:        shardIndex,
+		PandoraHeaderHash: invalidSlotInfo.PandoraHeaderHash,
+		VanguardBlockHash: invalidSlotInfo.VanguardBlockHash,
+		Reason:            invalidSlotInfo.Reason,
+	}, nil
+}
+
+// GetSlotInfoByPanHeaderHash returns the slot info and status of a pandora header without knowing its slot. Returns
+// nil when no verified or invalid slot holds the header hash.
+func (api *PublicFilterAPI) GetSlotInfoByPanHeaderHash(ctx context.Context, hash common.Hash) (*SlotInfo, error) {
+	return api.slotInfoByHash(ctx, hash, true), nil
+}
+
+// GetSlotInfoByVanBlockHash returns the slot info and status of a vanguard block without knowing its slot. Returns
+// nil when no verified or invalid slot holds the block hash.
+func (api *PublicFilterAPI) GetSlotInfoByVanBlockHash(ctx context.Context, hash common.Hash) (*SlotInfo, error) {
+	return api.slotInfoByHash(ctx, hash, false), nil
+}
+
+func (api *PublicFilterAPI) slotInfoByHash(ctx context.Context, hash common.Hash, requestFrom bool) *SlotInfo {
+	shardSlot := api.backend.SlotByHash(hash, requestFrom)
+	if shardSlot == nil {
+		return nil
+	}
+	// a hash of a replaced verified slot info is still held by its invalid slot info
+	var slotInfo *generalTypes.SlotInfo
+	if verifiedSlotInfo := api.backend.VerifiedSlotInfo(shardSlot.Slot, shardSlot.ShardIndex); verifiedSlotInfo != nil &&
+		hash == slotInfoHash(verifiedSlotInfo, requestFrom) {
+		slotInfo = verifiedSlotInfo
+	} else if invalidSlotInfo := api.backend.InvalidSlotInfo(shardSlot.Slot, shardSlot.ShardIndex); invalidSlotInfo != nil {
+		slotInfo = &invalidSlotInfo.SlotInfo
+	}
+	if slotInfo == nil {
+		return nil
+	}
+	return &SlotInfo{
+		Slot:              shardSlot.Slot,
+		ShardIndex:        shardSlot.ShardIndex,
+		PandoraHeaderHash: slotInfo.PandoraHeaderHash,
+		VanguardBlockHash: slotInfo.VanguardBlockHash,
+		Status:            api.backend.GetSlotStatus(ctx, shardSlot.Slot, shardSlot.ShardIndex, hash, requestFrom),
+	}
+}
+
+// slotInfoHash returns the pandora header hash of the slot info when requestFrom is true, otherwise the vanguard
+// block hash
+func slotInfoHash(slotInfo *generalTypes.SlotInfo, requestFrom bool) common.Hash {
+	if requestFrom {
+		return slotInfo.PandoraHeaderHash
+	}
+	return slotInfo.VanguardBlockHash
+}
+
+// GetVerifiedHeader returns the full pandora header which is verified for the slot. Shard 0 is used when the shard
+// index is omitted. Returns nil when the slot is not verified or its payload is pruned.
+func (api *PublicFilterAPI) GetVerifiedHeader(
+	ctx context.Context,
+	slot uint64,
+	shardIndex *uint64,
+) (*eth1Types.Header, error) {
+	return api.backend.VerifiedHeader(slot, shardIndexOrDefault(shardIndex)), nil
+}
+
+// GetShardInfo returns the vanguard shard info which is verified for the slot. Shard 0 is used when the shard index
+// is omitted. Returns nil when the slot is not verified or its payload is pruned.
+func (api *PublicFilterAPI) GetShardInfo(ctx context.Context, slot uint64, shardIndex *uint64) (*ShardInfo, error) {
+	vanShardInfo := api.backend.VerifiedShardInfo(slot, shardIndexOrDefault(shardIndex))
+	if vanShardInfo == nil {
+		return nil, nil
+	}
+	return &ShardInfo{
+		Slot:              vanShardInfo.Slot,
+		ShardIndex:        vanShardInfo.ShardIndex,
+		VanguardBlockHash: common.BytesToHash(vanShardInfo.BlockHash),
+		ShardInfo:         vanShardInfo.ShardInfo,
+	}, nil
+}
+
+func shardIndexOrDefault(shardIndex *uint64) uint64 {
+	if shardIndex == nil {
+		return 0
+	}
+	return *shardIndex
+}
+
+// SlotTime returns the expected time window of the slot. A pandora header of the slot must be produced within it.
+func (api *PublicFilterAPI) SlotTime(ctx context.Context, slot uint64) (*SlotTime, error) {
+	epoch := slot / generalTypes.SlotsPerEpoch
+	consensusInfo := api.backend.ConsensusInfoByEpoch(ctx, epoch)
+	if consensusInfo == nil {
+		return nil, fmt.Errorf("consensus info of epoch %d is not available", epoch)
+	}
+	return &SlotTime{
+		Slot:      slot,
+		StartTime: consensusInfo.SlotStartTime(slot),
+		EndTime:   consensusInfo.SlotEndTime(slot),
+	}, nil
+}
+
+// GetEquivocations returns the evidences of double proposals from the given slot in a format which a slasher
+// can consume
+func (api *PublicFilterAPI) GetEquivocations(ctx context.Context, fromSlot uint64) ([]*Equivocation, error) {
+	res := make([]*Equivocation, 0)
+	for _, equivocation := range api.backend.Equivocations(fromSlot) {
+		evidence := &Equivocation{
+			Kind:              equivocation.Kind,
+			Slot:              equivocation.Slot,
+			ShardIndex:        equivocation.ShardIndex,
+			ProposerIndex:     equivocation.ProposerIndex,
+			ProposerPublicKey: equivocation.ProposerPublicKey,
+			PandoraHeaders:    equivocation.PandoraHeaders,
+		}
+		for _, vanShardInfo := range equivocation.VanguardShardInfos {
+			evidence.VanguardBlocks = append(evidence.VanguardBlocks, &VanguardBlockEvidence{
+				BlockHash: common.BytesToHash(vanShardInfo.BlockHash),
+				ShardInfo: vanShardInfo.ShardInfo,
+			})
+		}
+		res = append(res, evidence)
+	}
+	return res, nil
+}
+
+// GetVerificationGaps returns, for every shard, the gap between the latest verified slot and the lowest pending slot
+// whose pandora header waits for a missing parent
+func (api *PublicFilterAPI) GetVerificationGaps(ctx context.Context) ([]*VerificationGap, error) {
+	res := make([]*VerificationGap, 0)
+	for _, gap := range api.backend.VerificationGaps() {
+		res = append(res, &VerificationGap{
+			ShardIndex:         gap.ShardIndex,
+			LatestVerifiedSlot: gap.LatestVerifiedSlot,
+			LatestHeaderHash:   gap.LatestHeaderHash,
+			WaitingSlot:        gap.WaitingSlot,
+			ParentHash:         gap.ParentHash,
+		})
+	}
+	return res, nil
+}
+
 // MinimalConsensusInfo
 func (api *PublicFilterAPI) MinimalConsensusInfo(ctx context.Context, requestedEpoch uint64) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
@@ -19,7 +19,15 @@ type MockBackend struct {
 
 	ConsensusInfos    []*eventTypes.MinimalEpochConsensusInfo
 	verifiedSlotInfos map[uint64]*eventTypes.SlotInfo
+	InvalidSlotInfos  map[eventTypes.ShardSlot]*eventTypes.InvalidSlotInfo
+	SkippedSlotInfos  map[eventTypes.ShardSlot]*eventTypes.SlotInfo
+	TimedOutSlotInfos map[eventTypes.ShardSlot]*eventTypes.SlotInfo
+	VerifiedHeaders   map[eventTypes.ShardSlot]*eth1Types.Header
+	ShardInfos        map[eventTypes.ShardSlot]*eventTypes.VanguardShardInfo
+	EquivocationList  []*eventTypes.Equivocation
+	Gaps              []*eventTypes.VerificationGap
 	CurEpoch          uint64
+	FinalizedSlot     uint64
 }
 
 var _ Backend = &MockBackend{}
@@ -32,6 +40,15 @@ func (b *MockBackend) ConsensusInfoByEpochRange(fromEpoch uint64) []*eventTypes.
 	return consensusInfos
 }
 
+func (b *MockBackend) ConsensusInfoByEpoch(ctx context.Context, epoch uint64) *eventTypes.MinimalEpochConsensusInfo {
+	for _, consensusInfo := range b.ConsensusInfos {
+		if consensusInfo.Epoch == epoch {
+			return consensusInfo
+		}
+	}
+	return nil
+}
+
 func (b *MockBackend) SubscribeNewEpochEvent(ch chan<- *eventTypes.MinimalEpochConsensusInfo) event.Subscription {
 	return b.ConsensusInfoFeed.Subscribe(ch)
 }
@@ -40,7 +57,7 @@ func (b *MockBackend) SubscribeNewVerifiedSlotInfoEvent(ch chan<- *eventTypes.Sl
 	return b.verifiedSlotInfoFeed.Subscribe(ch)
 }
 
-func (mb *MockBackend) GetSlotStatus(ctx context.Context, slot uint64, hash common.Hash, requestType bool) eventTypes.Status {
+func (mb *MockBackend) GetSlotStatus(ctx context.Context, slot uint64, shardIndex uint64, hash common.Hash, requestType bool) eventTypes.Status {
 	return eventTypes.Pending
 }
 
@@ -52,7 +69,7 @@ func (mb *MockBackend) PendingPandoraHeaders() []*eth1Types.Header {
 	return nil
 }
 
-func (mb *MockBackend) VerifiedSlotInfos(fromSlot uint64) map[uint64]*eventTypes.SlotInfo {
+func (mb *MockBackend) VerifiedSlotInfos(fromSlot uint64, shardIndex uint64) map[uint64]*eventTypes.SlotInfo {
 	slotInfos := make(map[uint64]*eventTypes.SlotInfo)
 	for slot, slotInfo := range mb.verifiedSlotInfos {
 		slotInfos[slot] = slotInfo
@@ -60,6 +77,62 @@ func (mb *MockBackend) VerifiedSlotInfos(fromSlot uint64) map[uint64]*eventTypes
 	return slotInfos
 }
 
-func (mb *MockBackend) LatestVerifiedSlot() uint64 {
+func (mb *MockBackend) VerifiedSlotInfo(slot uint64, shardIndex uint64) *eventTypes.SlotInfo {
+	return mb.verifiedSlotInfos[slot]
+}
+
+func (mb *MockBackend) SlotByHash(hash common.Hash, requestFrom bool) *eventTypes.ShardSlot {
+	for slot, slotInfo := range mb.verifiedSlotInfos {
+		if slotInfoHash(slotInfo, requestFrom) == hash {
+			return &eventTypes.ShardSlot{Slot: slot}
+		}
+	}
+	for shardSlot, slotInfo := range mb.InvalidSlotInfos {
+		if slotInfoHash(&slotInfo.SlotInfo, requestFrom) == hash {
+			return &eventTypes.ShardSlot{Slot: shardSlot.Slot, ShardIndex: shardSlot.ShardIndex}
+		}
+	}
+	return nil
+}
+
+func (mb *MockBackend) VerifiedHeader(slot uint64, shardIndex uint64) *eth1Types.Header {
+	return mb.VerifiedHeaders[eventTypes.ShardSlot{Slot: slot, ShardIndex: shardIndex}]
+}
+
+func (mb *MockBackend) VerifiedShardInfo(slot uint64, shardIndex uint64) *eventTypes.VanguardShardInfo {
+	return mb.ShardInfos[eventTypes.ShardSlot{Slot: slot, ShardIndex: shardIndex}]
+}
+
+func (mb *MockBackend) InvalidSlotInfo(slot uint64, shardIndex uint64) *eventTypes.InvalidSlotInfo {
+	return mb.InvalidSlotInfos[eventTypes.ShardSlot{Slot: slot, ShardIndex: shardIndex}]
+}
+
+func (mb *MockBackend) SkippedSlotInfo(slot uint64, shardIndex uint64) *eventTypes.SlotInfo {
+	return mb.SkippedSlotInfos[eventTypes.ShardSlot{Slot: slot, ShardIndex: shardIndex}]
+}
+
+func (mb *MockBackend) TimedOutSlotInfo(slot uint64, shardIndex uint64) *eventTypes.SlotInfo {
+	return mb.TimedOutSlotInfos[eventTypes.ShardSlot{Slot: slot, ShardIndex: shardIndex}]
+}
+
+func (mb *MockBackend) Equivocations(fromSlot uint64) []*eventTypes.Equivocation {
+	equivocations := make([]*eventTypes.Equivocation, 0)
+	for _, equivocation := range mb.EquivocationList {
+		if equivocation.Slot >= fromSlot {
+			equivocations = append(equivocations, equivocation)
+		}
+	}
+	return equivocations
+}
+
+func (mb *MockBackend) VerificationGaps() []*eventTypes.VerificationGap {
+	return mb.Gaps
+}
+
+func (mb *MockBackend) LatestVerifiedSlot(shardIndex uint64) uint64 {
 	return 100
 }
+
+func (mb *MockBackend) LatestFinalizedSlot(shardIndex uint64) uint64 {
+	return mb.FinalizedSlot
+}
@@ -3,6 +3,7 @@ package events
 import (
 	"context"
 
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/rpc"
 	generalTypes "github.com/lukso-network/lukso-orchestrator/shared/types"
 	"github.com/pkg/errors"
@@ -13,6 +14,25 @@ func (api *PublicFilterAPI) SteamConfirmedPanBlockHashes(
 	ctx context.Context,
 	request *BlockHash,
 ) (*rpc.Subscription, error) {
+	return api.streamConfirmedBlockHashes(ctx, request, true)
+}
+
+// SteamConfirmedVanBlockHashes streams the status of vanguard blocks. Decided blocks from the requested slot are
+// sent first so that a reconnected vanguard node receives the status of every replayed block.
+func (api *PublicFilterAPI) SteamConfirmedVanBlockHashes(
+	ctx context.Context,
+	request *BlockHash,
+) (*rpc.Subscription, error) {
+	return api.streamConfirmedBlockHashes(ctx, request, false)
+}
+
+// streamConfirmedBlockHashes streams the status of pandora header hashes when requestFrom is true, otherwise the
+// status of vanguard block hashes
+func (api *PublicFilterAPI) streamConfirmedBlockHashes(
+	ctx context.Context,
+	request *BlockHash,
+	requestFrom bool,
+) (*rpc.Subscription, error) {
 
 	notifier, supported := rpc.NotifierFromContext(ctx)
 	if !supported {
@@ -20,23 +40,31 @@ func (api *PublicFilterAPI) SteamConfirmedPanBlockHashes(
 	}
 	rpcSub := notifier.CreateSubscription()
 
+	apiName := "SteamConfirmedVanBlockHashes"
+	hashOf := func(slotInfo *generalTypes.SlotInfo) common.Hash {
+		return slotInfo.VanguardBlockHash
+	}
+	if requestFrom {
+		apiName = "SteamConfirmedPanBlockHashes"
+		hashOf = func(slotInfo *generalTypes.SlotInfo) common.Hash {
+			return slotInfo.PandoraHeaderHash
+		}
+	}
+
 	go func() {
 
 		batchSender := func(start, end uint64) error {
-			slotInfos := api.backend.VerifiedSlotInfos(start)
+			slotInfos := api.backend.VerifiedSlotInfos(start, request.ShardIndex)
+			latestFinalizedSlot := api.backend.LatestFinalizedSlot(request.ShardIndex)
 
 			for i := start; i <= end; i++ {
-				log.WithField("slot", i).WithField("slotInfo", slotInfos[i]).Debug("sending verifiedInfo to pandora batchsender")
-				if slotInfos[i] == nil {
-					// invalid slot requested. maybe slot 0.
+				sendingInfo := api.replayedBlockStatus(i, request.ShardIndex, slotInfos[i], latestFinalizedSlot, requestFrom)
+				if sendingInfo == nil {
+					// no block of the requested chain is known for the slot. maybe slot 0.
 					continue
 				}
-				log.WithField("hash", slotInfos[i].PandoraHeaderHash).Debug("sending verifiedInfo to pandora batchsender")
-				sendingInfo := &generalTypes.BlockStatus{
-					Hash:   slotInfos[i].PandoraHeaderHash,
-					Status: generalTypes.Verified,
-				}
-				log.WithField("info", *sendingInfo).Debug("Sending pendingness status to pandora")
+				log.WithField("slot", i).WithField("info", *sendingInfo).WithField("api", apiName).
+					Debug("Sending replayed block status")
 				if err := notifier.Notify(rpcSub.ID, sendingInfo); err != nil {
 					log.WithField("start", start).
 						WithField("end", end).
@@ -49,9 +77,9 @@ func (api *PublicFilterAPI) SteamConfirmedPanBlockHashes(
 		}
 
 		startSlot := request.Slot
-		endSlot := api.backend.LatestVerifiedSlot()
-		log.WithField("startSlot", startSlot).WithField("endSlot", endSlot).
-			Debug("received information from pandora")
+		endSlot := api.backend.LatestVerifiedSlot(request.ShardIndex)
+		log.WithField("startSlot", startSlot).WithField("endSlot", endSlot).WithField("api", apiName).
+			Debug("received stream request")
 
 		if startSlot < endSlot {
 			if err := batchSender(startSlot, endSlot); err != nil {
@@ -66,11 +94,23 @@ func (api *PublicFilterAPI) SteamConfirmedPanBlockHashes(
 		for {
 			select {
 			case slotInfoWithStatus := <-slotInfoCh:
-				log.WithField("hash", slotInfoWithStatus.PandoraHeaderHash).Debug("Sending slot info status to pandora")
+				// every shard is streamed over its own subscription
+				if slotInfoWithStatus.ShardIndex != request.ShardIndex {
+					continue
+				}
+				hash := hashOf(&generalTypes.SlotInfo{
+					VanguardBlockHash: slotInfoWithStatus.VanguardBlockHash,
+					PandoraHeaderHash: slotInfoWithStatus.PandoraHeaderHash,
+				})
+				// status is about the block of the other chain
+				if hash == (common.Hash{}) {
+					continue
+				}
+				log.WithField("hash", hash).WithField("api", apiName).Debug("Sending slot info status")
 				if firstTime {
 					firstTime = false
 					startSlot = endSlot
-					endSlot = api.backend.LatestVerifiedSlot()
+					endSlot = api.backend.LatestVerifiedSlot(request.ShardIndex)
 					log.WithField("startSlot", startSlot).WithField("endSlot", endSlot).Debug("for the first time")
 					if startSlot+1 < endSlot {
 						if err := batchSender(startSlot, endSlot); err != nil {
@@ -80,19 +120,19 @@ func (api *PublicFilterAPI) SteamConfirmedPanBlockHashes(
 				}
 
 				if err := notifier.Notify(rpcSub.ID, &generalTypes.BlockStatus{
-					Hash:   slotInfoWithStatus.PandoraHeaderHash,
+					Hash:   hash,
 					Status: slotInfoWithStatus.Status,
 				}); err != nil {
-					log.WithField("hash", slotInfoWithStatus.PandoraHeaderHash).
+					log.WithField("hash", hash).
 						Error("Failed to notify slot info status. Could not send over stream.")
 					return
 				}
 			case <-rpcSub.Err():
-				log.Info("Unsubscribing registered subscriber from SteamConfirmedPanBlockHashes")
+				log.WithField("api", apiName).Info("Unsubscribing registered subscriber")
 				verifiedSlotInfoSub.Unsubscribe()
 				return
 			case <-notifier.Closed():
-				log.Info("Closing notifier. Unsubscribing registered subscriber from SteamConfirmedPanBlockHashes")
+				log.WithField("api", apiName).Info("Closing notifier. Unsubscribing registered subscriber")
 				verifiedSlotInfoSub.Unsubscribe()
 				return
 			}
@@ -101,3 +141,38 @@ func (api *PublicFilterAPI) SteamConfirmedPanBlockHashes(
 
 	return rpcSub, nil
 }
+
+// replayedBlockStatus returns the status of the block of a slot which is replayed to a reconnected node. Verified,
+// invalid, skipped and timed out slots keep their stored status. It returns nil when no block of the requested
+// chain is decided for the slot.
+func (api *PublicFilterAPI) replayedBlockStatus(
+	slot uint64,
+	shardIndex uint64,
+	verifiedSlotInfo *generalTypes.SlotInfo,
+	latestFinalizedSlot uint64,
+	requestFrom bool,
+) *generalTypes.BlockStatus {
+	var (
+		slotInfo *generalTypes.SlotInfo
+		status   generalTypes.Status
+	)
+	if verifiedSlotInfo != nil {
+		slotInfo, status = verifiedSlotInfo, generalTypes.Verified
+		if slot <= latestFinalizedSlot {
+			status = generalTypes.Finalized
+		}
+	} else if invalidSlotInfo := api.backend.InvalidSlotInfo(slot, shardIndex); invalidSlotInfo != nil {
+		slotInfo, status = &invalidSlotInfo.SlotInfo, generalTypes.Invalid
+	} else if skippedSlotInfo := api.backend.SkippedSlotInfo(slot, shardIndex); skippedSlotInfo != nil {
+		slotInfo, status = skippedSlotInfo, generalTypes.Skipped
+	} else if timedOutSlotInfo := api.backend.TimedOutSlotInfo(slot, shardIndex); timedOutSlotInfo != nil {
+		slotInfo, status = timedOutSlotInfo, generalTypes.TimedOut
+	}
+	if slotInfo == nil || slotInfoHash(slotInfo, requestFrom) == (common.Hash{}) {
+		return nil
+	}
+	return &generalTypes.BlockStatus{
+		Hash:   slotInfoHash(slotInfo, requestFrom),
+		Status: status,
+	}
+}
@@ -1,6 +1,9 @@
 package events
 
 import (
+	"context"
+	"github.com/ethereum/go-ethereum/common"
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
 	eventTypes "github.com/lukso-network/lukso-orchestrator/shared/types"
@@ -96,3 +99,194 @@ func Test_MinimalConsensusInfo_With_Future_Epoch(t *testing.T) {
 
 	<-subscriber.Err()
 }
+
+// Test_GetInvalidSlotInfo checks that the mismatch reason of an invalid slot is returned by the api
+func Test_GetInvalidSlotInfo(t *testing.T) {
+	backend, eventApi := setup(t)
+	header := testutil.NewEth1Header(1)
+	backend.InvalidSlotInfos = map[eventTypes.ShardSlot]*eventTypes.InvalidSlotInfo{
+		{Slot: 1, ShardIndex: 1}: {
+			SlotInfo: eventTypes.SlotInfo{PandoraHeaderHash: header.Hash()},
+			Reason:   eventTypes.StateRootMismatch,
+		},
+	}
+
+	invalidSlotInfo, err := eventApi.GetInvalidSlotInfo(context.Background(), 1, 1)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, &InvalidSlotInfo{
+		Slot:              1,
+		ShardIndex:        1,
+		PandoraHeaderHash: header.Hash(),
+		Reason:            eventTypes.StateRootMismatch,
+	}, invalidSlotInfo)
+
+	invalidSlotInfo, err = eventApi.GetInvalidSlotInfo(context.Background(), 2, 1)
+	assert.NoError(t, err)
+	assert.Equal(t, (*InvalidSlotInfo)(nil), invalidSlotInfo)
+
+	invalidSlotInfo, err = eventApi.GetInvalidSlotInfo(context.Background(), 1, 0)
+	assert.NoError(t, err)
+	assert.Equal(t, (*InvalidSlotInfo)(nil), invalidSlotInfo)
+}
+
+// Test_GetSlotInfoByHash checks that verified and invalid slot infos are found by their hashes only
+func Test_GetSlotInfoByHash(t *testing.T) {
+	backend, eventApi := setup(t)
+	verifiedHeader, invalidHeader := testutil.NewEth1Header(1), testutil.NewEth1Header(2)
+	vanBlockHash := common.HexToHash("0x0a")
+	backend.verifiedSlotInfos = map[uint64]*eventTypes.SlotInfo{
+		1: {PandoraHeaderHash: verifiedHeader.Hash(), VanguardBlockHash: vanBlockHash},
+	}
+	backend.InvalidSlotInfos = map[eventTypes.ShardSlot]*eventTypes.InvalidSlotInfo{
+		{Slot: 2, ShardIndex: 1}: {
+			SlotInfo: eventTypes.SlotInfo{PandoraHeaderHash: invalidHeader.Hash()},
+			Reason:   eventTypes.StateRootMismatch,
+		},
+	}
+
+	slotInfo, err := eventApi.GetSlotInfoByPanHeaderHash(context.Background(), verifiedHeader.Hash())
+	assert.NoError(t, err)
+	assert.DeepEqual(t, &SlotInfo{
+		Slot:              1,
+		PandoraHeaderHash: verifiedHeader.Hash(),
+		VanguardBlockHash: vanBlockHash,
+		Status:            eventTypes.Pending,
+	}, slotInfo)
+
+	slotInfo, err = eventApi.GetSlotInfoByVanBlockHash(context.Background(), vanBlockHash)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, &SlotInfo{
+		Slot:              1,
+		PandoraHeaderHash: verifiedHeader.Hash(),
+		VanguardBlockHash: vanBlockHash,
+		Status:            eventTypes.Pending,
+	}, slotInfo)
+
+	slotInfo, err = eventApi.GetSlotInfoByPanHeaderHash(context.Background(), invalidHeader.Hash())
+	assert.NoError(t, err)
+	assert.DeepEqual(t, &SlotInfo{
+		Slot:              2,
+		ShardIndex:        1,
+		PandoraHeaderHash: invalidHeader.Hash(),
+		Status:            eventTypes.Pending,
+	}, slotInfo)
+
+	// a pandora header hash is not found as vanguard block hash
+	slotInfo, err = eventApi.GetSlotInfoByVanBlockHash(context.Background(), verifiedHeader.Hash())
+	assert.NoError(t, err)
+	assert.Equal(t, (*SlotInfo)(nil), slotInfo)
+}
+
+// Test_GetEquivocations checks that the stored evidences are exported from the requested slot
+func Test_GetEquivocations(t *testing.T) {
+	backend, eventApi := setup(t)
+	firstHeader, secondHeader := testutil.NewEth1Header(1), testutil.NewEth1Header(1)
+	secondHeader.Time++
+	vanShardInfo := testutil.NewVanguardShardInfo(3, firstHeader)
+	backend.EquivocationList = []*eventTypes.Equivocation{
+		{
+			Kind:           eventTypes.PandoraEquivocation,
+			Slot:           1,
+			ProposerIndex:  1,
+			PandoraHeaders: []*eth1Types.Header{firstHeader, secondHeader},
+		},
+		{
+			Kind:               eventTypes.VanguardEquivocation,
+			Slot:               3,
+			ProposerIndex:      3,
+			VanguardShardInfos: []*eventTypes.VanguardShardInfo{vanShardInfo, vanShardInfo},
+		},
+	}
+
+	equivocations, err := eventApi.GetEquivocations(context.Background(), 0)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(equivocations))
+	assert.DeepEqual(t, []*eth1Types.Header{firstHeader, secondHeader}, equivocations[0].PandoraHeaders)
+
+	equivocations, err = eventApi.GetEquivocations(context.Background(), 2)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, len(equivocations))
+	assert.DeepEqual(t, &Equivocation{
+		Kind:          eventTypes.VanguardEquivocation,
+		Slot:          3,
+		ProposerIndex: 3,
+		VanguardBlocks: []*VanguardBlockEvidence{
+			{BlockHash: common.BytesToHash(vanShardInfo.BlockHash), ShardInfo: vanShardInfo.ShardInfo},
+			{BlockHash: common.BytesToHash(vanShardInfo.BlockHash), ShardInfo: vanShardInfo.ShardInfo},
+		},
+	}, equivocations[0])
+}
+
+// Test_GetVerificationGaps checks that the gaps of the consensus service are exposed with their hashes
+func Test_GetVerificationGaps(t *testing.T) {
+	backend, eventApi := setup(t)
+	gaps, err := eventApi.GetVerificationGaps(context.Background())
+	assert.NoError(t, err)
+	assert.Equal(t, 0, len(gaps))
+
+	backend.Gaps = []*eventTypes.VerificationGap{{
+		ShardIndex:         0,
+		LatestVerifiedSlot: 4,
+		LatestHeaderHash:   common.HexToHash("0x04"),
+		WaitingSlot:        7,
+		ParentHash:         common.HexToHash("0x06"),
+	}}
+	gaps, err = eventApi.GetVerificationGaps(context.Background())
+	assert.NoError(t, err)
+	assert.DeepEqual(t, []*VerificationGap{{
+		LatestVerifiedSlot: 4,
+		LatestHeaderHash:   common.HexToHash("0x04"),
+		WaitingSlot:        7,
+		ParentHash:         common.HexToHash("0x06"),
+	}}, gaps)
+}
+
+// Test_SlotTime checks that the time window of a slot is derived from the consensus info of its epoch
+func Test_SlotTime(t *testing.T) {
+	_, eventApi := setup(t)
+	consensusInfo := testutil.NewMinimalConsensusInfo(1)
+
+	slotTime, err := eventApi.SlotTime(context.Background(), 33)
+	assert.NoError(t, err)
+	assert.DeepEqual(t, &SlotTime{
+		Slot:      33,
+		StartTime: consensusInfo.EpochStartTime + uint64(consensusInfo.SlotTimeDuration),
+		EndTime:   consensusInfo.EpochStartTime + 2*uint64(consensusInfo.SlotTimeDuration),
+	}, slotTime)
+
+	// consensus info of epoch 10 is not known
+	_, err = eventApi.SlotTime(context.Background(), 320)
+	assert.ErrorContains(t, "consensus info of epoch 10 is not available", err)
+}
+
+// Test_ReplayedBlockStatus checks that every decided block of a replayed range gets its stored status
+func Test_ReplayedBlockStatus(t *testing.T) {
+	backend, eventApi := setup(t)
+	panHash, vanHash := common.HexToHash("0x01"), common.HexToHash("0x02")
+	backend.InvalidSlotInfos = map[eventTypes.ShardSlot]*eventTypes.InvalidSlotInfo{
+		{Slot: 2}: {SlotInfo: eventTypes.SlotInfo{PandoraHeaderHash: panHash, VanguardBlockHash: vanHash}},
+	}
+	backend.SkippedSlotInfos = map[eventTypes.ShardSlot]*eventTypes.SlotInfo{
+		{Slot: 3}: {VanguardBlockHash: vanHash},
+	}
+	backend.TimedOutSlotInfos = map[eventTypes.ShardSlot]*eventTypes.SlotInfo{
+		{Slot: 4}: {PandoraHeaderHash: panHash},
+	}
+	verifiedSlotInfo := &eventTypes.SlotInfo{PandoraHeaderHash: panHash, VanguardBlockHash: vanHash}
+
+	status := func(hash common.Hash, status eventTypes.Status) *eventTypes.BlockStatus {
+		return &eventTypes.BlockStatus{Hash: hash, Status: status}
+	}
+	assert.DeepEqual(t, status(vanHash, eventTypes.Finalized), eventApi.replayedBlockStatus(1, 0, verifiedSlotInfo, 1, false))
+	assert.DeepEqual(t, status(panHash, eventTypes.Verified), eventApi.replayedBlockStatus(5, 0, verifiedSlotInfo, 1, true))
+	assert.DeepEqual(t, status(panHash, eventTypes.Invalid), eventApi.replayedBlockStatus(2, 0, nil, 1, true))
+	assert.DeepEqual(t, status(vanHash, eventTypes.Invalid), eventApi.replayedBlockStatus(2, 0, nil, 1, false))
+	assert.DeepEqual(t, status(vanHash, eventTypes.Skipped), eventApi.replayedBlockStatus(3, 0, nil, 1, false))
+	assert.DeepEqual(t, status(panHash, eventTypes.TimedOut), eventApi.replayedBlockStatus(4, 0, nil, 1, true))
+
+	// statuses about the block of the other chain or of unknown slots are not replayed
+	assert.Equal(t, (*eventTypes.BlockStatus)(nil), eventApi.replayedBlockStatus(3, 0, nil, 1, true))
+	assert.Equal(t, (*eventTypes.BlockStatus)(nil), eventApi.replayedBlockStatus(4, 0, nil, 1, false))
+	assert.Equal(t, (*eventTypes.BlockStatus)(nil), eventApi.replayedBlockStatus(6, 0, nil, 1, false))
+	assert.Equal(t, (*eventTypes.BlockStatus)(nil), eventApi.replayedBlockStatus(2, 1, nil, 1, false))
+}
@@ -7,6 +7,7 @@ import (
 	conIface "github.com/lukso-network/lukso-orchestrator/orchestrator/consensus/iface"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/db"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/rpc/api"
+	"github.com/lukso-network/lukso-orchestrator/orchestrator/rpc/api/admin"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/rpc/api/events"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/vanguardchain/iface"
 	"sync"
@@ -17,9 +18,12 @@ import (
 type Config struct {
 	ConsensusInfoFeed            iface.ConsensusInfoFeed
 	VerifiedSlotInfoFeed         conIface.VerifiedSlotInfoFeed
+	VerificationGapReader        conIface.VerificationGapReader
 	Db                           db.Database
 	VanguardPendingShardingCache cache.VanguardShardCache
 	PandoraPendingHeaderCache    cache.PandoraHeaderCache
+	// DBBackupDir is the directory whose on-demand subdirectory keeps the backups which are requested over the admin api
+	DBBackupDir string
 	// ipc config
 	IPCPath string
 	// http config
@@ -73,9 +77,15 @@ func NewService(ctx context.Context, cfg *Config) (*Service, error) {
 			ConsensusInfoDB:              cfg.Db,
 			VerifiedSlotInfoDB:           cfg.Db,
 			InvalidSlotInfoDB:            cfg.Db,
+			SkippedSlotInfoDB:            cfg.Db,
+			TimedOutSlotInfoDB:           cfg.Db,
+			EquivocationDB:               cfg.Db,
+			HashIndexDB:                  cfg.Db,
+			VerifiedPayloadDB:            cfg.Db,
 			PandoraPendingHeaderCache:    cfg.PandoraPendingHeaderCache,
 			VanguardPendingShardingCache: cfg.VanguardPendingShardingCache,
 			VerifiedSlotInfoFeed:         cfg.VerifiedSlotInfoFeed,
+			VerificationGapReader:        cfg.VerificationGapReader,
 		},
 	}
 	// Configure RPC servers.
@@ -229,5 +239,11 @@ func (s *Service) APIs() []rpc.API {
 			Service:   events.NewPublicFilterAPI(s.backend, 5*time.Minute),
 			Public:    true,
 		},
+		{
+			Namespace: "admin",
+			Version:   "1.0",
+			Service:   admin.NewPrivateAdminAPI(s.config.Db, s.config.DBBackupDir),
+			Public:    false,
+		},
 	}
 }
@@ -2,13 +2,19 @@ package rpc
 
 import (
 	"context"
+	"github.com/ethereum/go-ethereum/common"
+	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	ethRpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/cache"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/consensus"
 	testDB "github.com/lukso-network/lukso-orchestrator/orchestrator/db/testing"
 	"github.com/lukso-network/lukso-orchestrator/orchestrator/vanguardchain"
 	"github.com/lukso-network/lukso-orchestrator/shared/cmd"
+	"github.com/lukso-network/lukso-orchestrator/shared/fileutil"
+	"github.com/lukso-network/lukso-orchestrator/shared/testutil"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	"github.com/lukso-network/lukso-orchestrator/shared/types"
 	logTest "github.com/sirupsen/logrus/hooks/test"
 	"testing"
 )
@@ -26,28 +32,36 @@ func setup(t *testing.T) (*Config, error) {
 		return nil, err
 	}
 
-	consensusSvr := consensus.New(
+	consensusSvr, err := consensus.New(
 		context.Background(),
 		&consensus.Config{
-			orchestratorDB,
-			orchestratorDB,
-			cache.NewVanShardInfoCache(1 << 10),
-			cache.NewPanHeaderCache(),
-			nil,
-			nil,
+			ConsensusInfoDB:              orchestratorDB,
+			VerifiedSlotInfoDB:           orchestratorDB,
+			InvalidSlotInfoDB:            orchestratorDB,
+			SkippedSlotInfoDB:            orchestratorDB,
+			TimedOutSlotInfoDB:           orchestratorDB,
+			EquivocationDB:               orchestratorDB,
+			VerdictDB:                    orchestratorDB,
+			VerifiedPayloadDB:            orchestratorDB,
+			VanguardPendingShardingCache: cache.NewVanShardInfoCache(1 << 10),
+			PandoraPendingHeaderCache:    cache.NewPanHeaderCache(),
 		})
+	if err != nil {
+		return nil, err
+	}
 
 	return &Config{
-		ConsensusInfoFeed:    consensusInfoFeed,
-		VerifiedSlotInfoFeed: consensusSvr,
-		Db:                   orchestratorDB,
-		IPCPath:              cmd.DefaultIpcPath,
-		HTTPEnable:           true,
-		HTTPHost:             cmd.DefaultHTTPHost,
-		HTTPPort:             9874,
-		WSEnable:             true,
-		WSHost:               cmd.DefaultWSHost,
-		WSPort:               9875,
+		ConsensusInfoFeed:     consensusInfoFeed,
+		VerifiedSlotInfoFeed:  consensusSvr,
+		VerificationGapReader: consensusSvr,
+		Db:                    orchestratorDB,
+		IPCPath:               cmd.DefaultIpcPath,
+		HTTPEnable:            true,
+		HTTPHost:              cmd.DefaultHTTPHost,
+		HTTPPort:              9874,
+		WSEnable:              true,
+		WSHost:                cmd.DefaultWSHost,
+		WSPort:                9875,
 	}, nil
 }
 
@@ -73,3 +87,66 @@ func TestServerStart_Success(t *testing.T) {
 	hook.Reset()
 	assert.NoError(t, rpcService.Stop())
 }
+
+// TestService_AdminBackup checks that the admin api writes a backup and is only served by the private endpoints
+func TestService_AdminBackup(t *testing.T) {
+	config, err := setup(t)
+	require.NoError(t, err)
+	config.DBBackupDir = t.TempDir()
+	rpcService, err := NewService(context.Background(), config)
+	require.NoError(t, err)
+
+	require.NoError(t, rpcService.startInProc())
+	defer rpcService.stopInProc()
+	client := ethRpc.DialInProc(rpcService.inprocHandler)
+	defer client.Close()
+	var backupPath string
+	require.NoError(t, client.Call(&backupPath, "admin_backup"))
+	assert.Equal(t, true, fileutil.FileExists(backupPath))
+
+	publicServer := ethRpc.NewServer()
+	require.NoError(t, RegisterApisFromWhitelist(rpcService.rpcAPIs, nil, publicServer, false))
+	defer publicServer.Stop()
+	publicClient := ethRpc.DialInProc(publicServer)
+	defer publicClient.Close()
+	err = publicClient.Call(&backupPath, "admin_backup")
+	assert.ErrorContains(t, "does not exist", err)
+}
+
+// TestService_VerifiedPayload checks that the verified header and shard info of a slot are served by the orc api
+func TestService_VerifiedPayload(t *testing.T) {
+	config, err := setup(t)
+	require.NoError(t, err)
+	header := testutil.NewEth1Header(3)
+	shardInfo := testutil.NewVanguardShardInfo(3, header)
+	require.NoError(t, config.Db.CommitVerifiedSlots(0, []*types.VerifiedSlot{{
+		Slot: 3,
+		SlotInfo: &types.SlotInfo{
+			VanguardBlockHash: common.BytesToHash(shardInfo.BlockHash),
+			PandoraHeaderHash: header.Hash(),
+		},
+		Header:    header,
+		ShardInfo: shardInfo,
+	}}))
+	rpcService, err := NewService(context.Background(), config)
+	require.NoError(t, err)
+
+	require.NoError(t, rpcService.startInProc())
+	defer rpcService.stopInProc()
+	client := ethRpc.DialInProc(rpcService.inprocHandler)
+	defer client.Close()
+
+	// shard index is optional
+	var verifiedHeader *eth1Types.Header
+	require.NoError(t, client.Call(&verifiedHeader, "orc_getVerifiedHeader", 3))
+	require.NotNil(t, verifiedHeader)
+	assert.Equal(t, header.Hash(), verifiedHeader.Hash())
+	verifiedHeader = nil
+	require.NoError(t, client.Call(&verifiedHeader, "orc_getVerifiedHeader", 3, 1))
+	assert.Equal(t, (*eth1Types.Header)(nil), verifiedHeader)
+
+	var verifiedShardInfo map[string]interface{}
+	require.NoError(t, client.Call(&verifiedShardInfo, "orc_getShardInfo", 3, 0))
+	require.NotNil(t, verifiedShardInfo)
+	assert.Equal(t, float64(3), verifiedShardInfo["slot"])
+}
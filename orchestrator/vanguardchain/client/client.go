@@ -16,6 +16,7 @@ type VanguardClient interface {
 	CanonicalHeadSlot() (types.Slot, error)
 	StreamNewPendingBlocks(blockRoot []byte, fromSlot types.Slot) (ethpb.BeaconChain_StreamNewPendingBlocksClient, error)
 	StreamMinimalConsensusInfo(epoch uint64) (stream ethpb.BeaconChain_StreamMinimalConsensusInfoClient, err error)
+	StreamChainHead() (ethpb.BeaconChain_StreamChainHeadClient, error)
 	Close()
 }
 
@@ -111,6 +112,20 @@ func (vanClient *GRPCClient) StreamMinimalConsensusInfo(epoch uint64) (
 	return
 }
 
+// StreamChainHead streams the chain head of vanguard which carries the latest finalized checkpoint
+func (vanClient *GRPCClient) StreamChainHead() (
+	stream ethpb.BeaconChain_StreamChainHeadClient,
+	err error,
+) {
+	stream, err = vanClient.beaconClient.StreamChainHead(vanClient.ctx, &emptypb.Empty{})
+	if err != nil {
+		log.WithError(err).Error("Failed to subscribe to StreamChainHead")
+		return
+	}
+	log.Info("Successfully subscribed to StreamChainHead event")
+	return
+}
+
 // constructDialOptions constructs a list of grpc dial options
 func constructDialOptions(
 	maxCallRecvMsgSize int,
@@ -3,19 +3,19 @@ package vanguardchain
 import (
 	"context"
 	"errors"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
 	eth "github.com/prysmaticlabs/prysm/proto/eth/v1alpha1"
 	"github.com/prysmaticlabs/prysm/proto/eth/v1alpha1/wrapper"
+	"sync/atomic"
 )
 
 // OnNewConsensusInfo :
-//	- sends the new consensus info to all subscribed pandora clients
 //  - store consensus info into cache as well as into kv consensusInfoDB
+//	- sends the new consensus info to all subscribed pandora clients and the consensus service. It is stored first
+//	  so that the consensus service finds it when it verifies the pending slots of the epoch.
 func (s *Service) OnNewConsensusInfo(ctx context.Context, consensusInfo *types.MinimalEpochConsensusInfo) error {
-	nsent := s.consensusInfoFeed.Send(consensusInfo)
-	log.WithField("nsent", nsent).Trace("Send consensus info to subscribers")
-
 	if err := s.orchestratorDB.SaveConsensusInfo(ctx, consensusInfo); err != nil {
 		log.WithError(err).Warn("failed to save consensus info into consensusInfoDB!")
 		return err
@@ -25,6 +25,9 @@ func (s *Service) OnNewConsensusInfo(ctx context.Context, consensusInfo *types.M
 		log.WithError(err).Warn("failed to save latest epoch into consensusInfoDB!")
 		return err
 	}
+
+	nsent := s.consensusInfoFeed.Send(consensusInfo)
+	log.WithField("nsent", nsent).Trace("Send consensus info to subscribers")
 	return nil
 }
 
@@ -43,18 +46,43 @@ func (s *Service) OnNewPendingVanguardBlock(ctx context.Context, block *eth.Beac
 		return errors.New("invalid shard info length in vanguard block body")
 	}
 
-	shardInfo := pandoraShards[0]
-	cachedShardInfo := &types.VanguardShardInfo{
-		Slot:      uint64(block.Slot),
-		BlockHash: blockHash[:],
-		ShardInfo: shardInfo,
-	}
+	// every pandora shard is verified independently, the position in the block body is the shard index
+	for shardIndex, shardInfo := range pandoraShards {
+		cachedShardInfo := &types.VanguardShardInfo{
+			Slot:       uint64(block.Slot),
+			ShardIndex: uint64(shardIndex),
+			BlockHash:  blockHash[:],
+			ShardInfo:  shardInfo,
+		}
 
-	log.WithField("slot", block.Slot).
-		WithField("blockNumber", shardInfo.BlockNumber).
-		WithField("shardInfoHash", hexutil.Encode(shardInfo.Hash)).
-		Info("New vanguard shard info has arrived")
+		log.WithField("slot", block.Slot).
+			WithField("shardIndex", shardIndex).
+			WithField("blockNumber", shardInfo.BlockNumber).
+			WithField("shardInfoHash", hexutil.Encode(shardInfo.Hash)).
+			Info("New vanguard shard info has arrived")
 
-	s.vanguardShardingInfoFeed.Send(cachedShardInfo)
+		s.vanguardShardingInfoFeed.Send(cachedShardInfo)
+	}
 	return nil
 }
+
+// OnNewChainHead sends the finalized checkpoint of the vanguard chain head to the subscribers when finality
+// has advanced
+func (s *Service) OnNewChainHead(chainHead *eth.ChainHead) {
+	finalizedSlot := uint64(chainHead.FinalizedSlot)
+	if finalizedSlot <= atomic.LoadUint64(&s.latestFinalizedSlot) {
+		return
+	}
+	atomic.StoreUint64(&s.latestFinalizedSlot, finalizedSlot)
+
+	log.WithField("finalizedSlot", finalizedSlot).
+		WithField("finalizedEpoch", chainHead.FinalizedEpoch).
+		WithField("finalizedBlockRoot", hexutil.Encode(chainHead.FinalizedBlockRoot)).
+		Info("New vanguard finalized checkpoint has arrived")
+
+	s.finalizedCheckpointFeed.Send(&types.FinalizedCheckpoint{
+		Slot:      finalizedSlot,
+		Epoch:     uint64(chainHead.FinalizedEpoch),
+		BlockRoot: common.BytesToHash(chainHead.FinalizedBlockRoot),
+	})
+}
@@ -2,8 +2,10 @@ package vanguardchain
 
 import (
 	"context"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/assert"
 	"github.com/lukso-network/lukso-orchestrator/shared/testutil/require"
+	eventTypes "github.com/lukso-network/lukso-orchestrator/shared/types"
 	types "github.com/prysmaticlabs/eth2-types"
 	eth "github.com/prysmaticlabs/prysm/proto/eth/v1alpha1"
 	logTest "github.com/sirupsen/logrus/hooks/test"
@@ -54,3 +56,23 @@ func TestService_OnNewPendingVanguardBlock(t *testing.T) {
 	time.Sleep(100 * time.Millisecond)
 	assert.LogsContain(t, hook, "New vanguard shard info has arrived")
 }
+
+// TestService_OnNewChainHead checks that a finalized checkpoint is only sent when finality advances
+func TestService_OnNewChainHead(t *testing.T) {
+	ctx := context.Background()
+	vanSvc, _ := SetupVanguardSvc(ctx, t, GRPCFunc)
+
+	checkpointCh := make(chan *eventTypes.FinalizedCheckpoint, 3)
+	sub := vanSvc.SubscribeFinalizedCheckpointEvent(checkpointCh)
+	defer sub.Unsubscribe()
+
+	blockRoot := common.BytesToHash([]byte{1})
+	vanSvc.OnNewChainHead(&eth.ChainHead{FinalizedSlot: 64, FinalizedEpoch: 2, FinalizedBlockRoot: blockRoot.Bytes()})
+	// same finalized checkpoint comes with every new chain head
+	vanSvc.OnNewChainHead(&eth.ChainHead{FinalizedSlot: 64, FinalizedEpoch: 2, FinalizedBlockRoot: blockRoot.Bytes()})
+	vanSvc.OnNewChainHead(&eth.ChainHead{FinalizedSlot: 96, FinalizedEpoch: 3, FinalizedBlockRoot: blockRoot.Bytes()})
+
+	assert.DeepEqual(t, &eventTypes.FinalizedCheckpoint{Slot: 64, Epoch: 2, BlockRoot: blockRoot}, <-checkpointCh)
+	assert.DeepEqual(t, &eventTypes.FinalizedCheckpoint{Slot: 96, Epoch: 3, BlockRoot: blockRoot}, <-checkpointCh)
+	assert.Equal(t, 0, len(checkpointCh))
+}
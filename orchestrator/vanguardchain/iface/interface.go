@@ -12,3 +12,7 @@ type ConsensusInfoFeed interface {
 type VanguardShardInfoFeed interface {
 	SubscribeShardInfoEvent(chan<- *types.VanguardShardInfo) event.Subscription
 }
+
+type FinalizedCheckpointFeed interface {
+	SubscribeFinalizedCheckpointEvent(chan<- *types.FinalizedCheckpoint) event.Subscription
+}
@@ -43,6 +43,9 @@ type Service struct {
 	conInfoSubErrCh          chan error
 	conInfoSub               *rpc.ClientSubscription
 	vanguardShardingInfoFeed event.Feed
+	finalizedCheckpointFeed  event.Feed
+	// slot of the latest finalized checkpoint which is sent to the subscribers
+	latestFinalizedSlot uint64
 	// db support
 	orchestratorDB db.Database
 	// lru cache support
@@ -193,6 +196,12 @@ func (s *Service) connectToVanguardChain() (err error) {
 		return
 	}
 
+	err = s.subscribeFinalizedCheckpoint(vanguardClient)
+
+	if nil != err {
+		return
+	}
+
 	return
 }
 
@@ -215,3 +224,8 @@ func (s *Service) SubscribeMinConsensusInfoEvent(ch chan<- *types.MinimalEpochCo
 func (s *Service) SubscribeShardInfoEvent(ch chan<- *types.VanguardShardInfo) event.Subscription {
 	return s.scope.Track(s.vanguardShardingInfoFeed.Subscribe(ch))
 }
+
+// SubscribeFinalizedCheckpointEvent registers a subscription of vanguard finalized checkpoints
+func (s *Service) SubscribeFinalizedCheckpointEvent(ch chan<- *types.FinalizedCheckpoint) event.Subscription {
+	return s.scope.Track(s.finalizedCheckpointFeed.Subscribe(ch))
+}
@@ -17,6 +17,7 @@ var (
 	errConsensusInfoNil       = errors.New("Incoming consensus info is nil")
 	errInvalidValidatorLength = errors.New("Incoming consensus info's validator list is invalid")
 	errConsensusInfoProcess   = errors.New("Could not process minimal consensus info")
+	errChainHeadNil           = errors.New("Incoming chain head is nil")
 )
 
 // subscribeVanNewPendingBlockHash
@@ -24,8 +25,9 @@ func (s *Service) subscribeVanNewPendingBlockHash(
 	client client.VanguardClient,
 ) error {
 
-	latestVerifiedSlot := s.orchestratorDB.LatestSavedVerifiedSlot()
-	latestVerifiedSlotInfo, err := s.orchestratorDB.VerifiedSlotInfo(latestVerifiedSlot)
+	// vanguard blocks carry every shard, so the stream is resumed from the first shard's progress
+	latestVerifiedSlot := s.orchestratorDB.LatestSavedVerifiedSlot(0)
+	latestVerifiedSlotInfo, err := s.orchestratorDB.VerifiedSlotInfo(latestVerifiedSlot, 0)
 	var blockRoot []byte
 	if err != nil {
 		log.WithField("latestVerifiedSlot", latestVerifiedSlot).
@@ -144,3 +146,42 @@ func (s *Service) subscribeNewConsensusInfoGRPC(client client.VanguardClient) er
 
 	return nil
 }
+
+// subscribeFinalizedCheckpoint streams the vanguard chain head and sends the finalized checkpoint whenever it moves
+func (s *Service) subscribeFinalizedCheckpoint(client client.VanguardClient) error {
+	stream, err := client.StreamChainHead()
+	if nil != err {
+		log.WithError(err).Error("Failed to subscribe to stream of chain head")
+		return err
+	}
+
+	log.Info("Successfully subscribed to vanguard finalized checkpoints")
+
+	go func() {
+		for {
+			select {
+			case <-s.ctx.Done():
+				log.Debug("closing subscribeFinalizedCheckpoint")
+				return
+			default:
+				chainHead, err := stream.Recv()
+				if e, ok := status.FromError(err); ok {
+					switch e.Code() {
+					case codes.Canceled, codes.Internal, codes.Unavailable:
+						log.WithError(err).Infof("Trying to restart connection. rpc status: %v", e.Code())
+						s.conInfoSubErrCh <- err
+						return
+					}
+				}
+
+				if nil == chainHead {
+					log.Error("Received nil chain head")
+					s.conInfoSubErrCh <- errChainHeadNil
+					return
+				}
+				s.OnNewChainHead(chainHead)
+			}
+		}
+	}()
+	return nil
+}
@@ -14,6 +14,7 @@ import (
 	types "github.com/prysmaticlabs/eth2-types"
 	eth "github.com/prysmaticlabs/prysm/proto/eth/v1alpha1"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
 	"google.golang.org/grpc/metadata"
 	"testing"
 	"time"
@@ -26,20 +27,24 @@ type mocks struct {
 type vanClientMock struct {
 	pendingBlocksClient eth.BeaconChain_StreamNewPendingBlocksClient
 	consensusInfoClient eth.BeaconChain_StreamMinimalConsensusInfoClient
+	chainHeadClient     eth.BeaconChain_StreamChainHeadClient
 }
 
 var (
 	ConsensusInfoMocks        []*eth.MinimalConsensusInfo
 	PendingBlockMocks         []*eth.BeaconBlock
+	ChainHeadMocks            []*eth.ChainHead
 	mockedStreamPendingBlocks eth.BeaconChain_StreamNewPendingBlocksClient = streamNewPendingBlocksClient{
 		pendingBlocks: PendingBlockMocks,
 	}
 	mockedStreamConsensusInfoClient eth.BeaconChain_StreamMinimalConsensusInfoClient = streamConsensusInfoClient{
 		consensusInfos: ConsensusInfoMocks,
 	}
-	mockedVanClientStruct = &vanClientMock{
+	mockedStreamChainHeadClient eth.BeaconChain_StreamChainHeadClient = streamChainHeadClient{}
+	mockedVanClientStruct                                             = &vanClientMock{
 		mockedStreamPendingBlocks,
 		mockedStreamConsensusInfoClient,
+		mockedStreamChainHeadClient,
 	}
 	mockedClient client.VanguardClient = mockedVanClientStruct
 )
@@ -97,6 +102,10 @@ func (v vanClientMock) StreamNewPendingBlocks(blockRoot []byte, fromSlot types.S
 	return v.pendingBlocksClient, nil
 }
 
+func (v vanClientMock) StreamChainHead() (eth.BeaconChain_StreamChainHeadClient, error) {
+	return v.chainHeadClient, nil
+}
+
 func (v vanClientMock) Close() {
 	panic("implement me")
 }
@@ -142,6 +151,23 @@ func (s streamConsensusInfoClient) RecvMsg(m interface{}) error {
 	panic("implement me")
 }
 
+type streamChainHeadClient struct {
+	grpc.ClientStream
+}
+
+func (s streamChainHeadClient) Recv() (*eth.ChainHead, error) {
+	if len(ChainHeadMocks) > 0 {
+		toReturn := ChainHeadMocks[0]
+		ChainHeadMocks = append([]*eth.ChainHead(nil), ChainHeadMocks[1:]...)
+
+		return toReturn, nil
+	}
+
+	//     Should not receive anything until mocks are present
+	time.Sleep(time.Millisecond * 200)
+	return s.Recv()
+}
+
 func GRPCFunc(endpoint string) (client.VanguardClient, error) {
 	return mockedClient, nil
 }
@@ -5,6 +5,7 @@ import (
 	"os"
 	"path/filepath"
 	"runtime"
+	"time"
 )
 
 const (
@@ -15,6 +16,15 @@ const (
 	DefaultIpcPath              = "orchestrator.ipc"
 	DefaultVanguardGRPCEndpoint = "127.0.0.1:4000"
 	DefaultPandoraRPCEndpoint   = "http://127.0.0.1:8545"
+	DefaultPendingSlotTimeout   = 32
+	DefaultVerificationRules    = "sharding-info,extra-data,signature,timestamp"
+	DefaultSlotTimeTolerance    = 1
+	DefaultCatchUpBatchSize     = 256
+	DefaultDBPrunePolicy        = "none"
+	DefaultDBPruneRetainEpochs  = 4096
+	DefaultDBPruneInterval      = 10 * time.Minute
+	DefaultDBBackupInterval     = time.Hour
+	DefaultDBBackupKeep         = 3
 )
 
 // DefaultConfigDir is the default config directory to use for the vaults and other
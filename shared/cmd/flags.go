@@ -22,6 +22,69 @@ var (
 		Name:  "clear-db",
 		Usage: "Prompt for clearing any previously stored data at the data directory",
 	}
+	// DBMigrateDryRunFlag reports the pending database migrations without applying them.
+	DBMigrateDryRunFlag = &cli.BoolFlag{
+		Name:  "db-migrate-dry-run",
+		Usage: "Report the pending migrations of the database in the data directory and exit without applying them",
+	}
+	// DBPrunePolicyFlag selects which historical data is removed from the database.
+	DBPrunePolicyFlag = &cli.StringFlag{
+		Name:  "db-prune-policy",
+		Usage: "Pruning of old slot infos and consensus infos (none, epochs=keep the latest --db-prune-retain-epochs epochs, finalized=keep from the latest finalized slot on)",
+		Value: DefaultDBPrunePolicy,
+	}
+	// DBPruneRetainEpochsFlag defines how many latest epochs are kept by the epochs prune policy.
+	DBPruneRetainEpochsFlag = &cli.Uint64Flag{
+		Name:  "db-prune-retain-epochs",
+		Usage: "Number of latest epochs which are kept by the epochs prune policy",
+		Value: DefaultDBPruneRetainEpochs,
+	}
+	// DBPruneIntervalFlag defines how often the database is pruned.
+	DBPruneIntervalFlag = &cli.DurationFlag{
+		Name:  "db-prune-interval",
+		Usage: "Interval between two prunings of the database",
+		Value: DefaultDBPruneInterval,
+	}
+	// DBPayloadRetainEpochsFlag defines how many latest epochs keep the full payloads of their verified slots.
+	DBPayloadRetainEpochsFlag = &cli.Uint64Flag{
+		Name:  "db-payload-retain-epochs",
+		Usage: "Number of latest epochs whose verified pandora headers and vanguard shard infos are kept (0 keeps them as long as their slot infos)",
+	}
+	// DBBackupDirFlag enables periodic backups of the database into the directory.
+	DBBackupDirFlag = &cli.StringFlag{
+		Name:  "db-backup-dir",
+		Usage: "Directory of periodic database backups (no periodic backups when empty). Backups which are requested with admin_backup are kept in its on-demand subdirectory",
+	}
+	// DBBackupIntervalFlag defines how often the database is backed up.
+	DBBackupIntervalFlag = &cli.DurationFlag{
+		Name:  "db-backup-interval",
+		Usage: "Interval between two periodic database backups",
+		Value: DefaultDBBackupInterval,
+	}
+	// DBBackupKeepFlag defines how many periodic backups are kept.
+	DBBackupKeepFlag = &cli.IntFlag{
+		Name:  "db-backup-keep",
+		Usage: "Number of latest database backups which are kept by periodic backups (0 keeps all)",
+		Value: DefaultDBBackupKeep,
+	}
+	// RestoreSourceFileFlag defines the database snapshot which is restored.
+	RestoreSourceFileFlag = &cli.StringFlag{
+		Name:     "restore-source-file",
+		Usage:    "Database snapshot which is restored into the data directory",
+		Required: true,
+	}
+	// DBExportFileFlag defines the file which the database history is exported into.
+	DBExportFileFlag = &cli.StringFlag{
+		Name:     "export-file",
+		Usage:    "File which the database history is exported into",
+		Required: true,
+	}
+	// DBImportFileFlag defines the exported database history which is imported.
+	DBImportFileFlag = &cli.StringFlag{
+		Name:     "import-file",
+		Usage:    "Exported database history which is imported into the empty database",
+		Required: true,
+	}
 
 	IPCPathFlag = &cli.StringFlag{
 		Name:  "ipcpath",
@@ -68,13 +131,41 @@ var (
 		Value: DefaultVanguardGRPCEndpoint,
 	}
 
-	// PandoraRPCEndpoint provides an WSS/IPC access endpoint to an Pandora RPC.
+	// PandoraRPCEndpoint provides WSS/IPC access endpoints to Pandora RPCs, one per shard.
 	PandoraRPCEndpoint = &cli.StringFlag{
 		Name:  "pandora-rpc-endpoint",
-		Usage: "Pandora node RPC provider endpoint",
+		Usage: "Comma separated Pandora node RPC provider endpoints. The position of an endpoint is its shard index",
 		Value: DefaultPandoraRPCEndpoint,
 	}
 
+	// PendingSlotTimeoutFlag defines the number of slots until which an unmatched slot stays pending.
+	PendingSlotTimeoutFlag = &cli.Uint64Flag{
+		Name:  "pending-slot-timeout",
+		Usage: "Number of slots after the slot start time until which an unmatched slot stays pending (0 disables timeout)",
+		Value: DefaultPendingSlotTimeout,
+	}
+
+	// VerificationRulesFlag selects the rules which verify pandora headers against vanguard shard infos.
+	VerificationRulesFlag = &cli.StringFlag{
+		Name:  "verification-rules",
+		Usage: "Comma separated verification rules which are applied in order to every sharding info",
+		Value: DefaultVerificationRules,
+	}
+
+	// SlotTimeToleranceFlag defines how many seconds a pandora header timestamp may be out of its slot window.
+	SlotTimeToleranceFlag = &cli.Uint64Flag{
+		Name:  "slot-time-tolerance",
+		Usage: "Number of seconds a pandora header timestamp may be out of its slot window for the timestamp rule",
+		Value: DefaultSlotTimeTolerance,
+	}
+
+	// CatchUpBatchSizeFlag defines how many queued events are verified together while catching up.
+	CatchUpBatchSizeFlag = &cli.Uint64Flag{
+		Name:  "catch-up-batch-size",
+		Usage: "Maximum number of queued sharding infos which are verified in one batch while catching up (0 disables batching)",
+		Value: DefaultCatchUpBatchSize,
+	}
+
 	// VerbosityFlag defines the logrus configuration.
 	VerbosityFlag = &cli.StringFlag{
 		Name:  "verbosity",
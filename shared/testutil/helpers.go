@@ -7,18 +7,32 @@ import (
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/lukso-network/lukso-orchestrator/shared/types"
 	ethpb "github.com/prysmaticlabs/prysm/proto/eth/v1alpha1"
-	"golang.org/x/crypto/sha3"
+	"github.com/prysmaticlabs/prysm/shared/bls"
 	"math/big"
 	"time"
 )
 
-func NewMinimalConsensusInfo(epoch uint64) *types.MinimalEpochConsensusInfo {
-	validatorList := make([]string, 32)
+// ValidatorKeys are the secret keys of the validators in NewMinimalConsensusInfo. The proposer of a slot
+// signs the header of NewEth1Header with its key.
+var ValidatorKeys, validatorPubKeys = newValidatorKeys(types.SlotsPerEpoch)
 
-	for idx := 0; idx < 32; idx++ {
-		pubKey := make([]byte, 48)
-		validatorList[idx] = hexutil.Encode(pubKey)
+func newValidatorKeys(num int) ([]bls.SecretKey, []string) {
+	keys := make([]bls.SecretKey, num)
+	pubKeys := make([]string, num)
+	for idx := 0; idx < num; idx++ {
+		key, err := bls.RandKey()
+		if err != nil {
+			panic(err)
+		}
+		keys[idx] = key
+		pubKeys[idx] = hexutil.Encode(key.PublicKey().Marshal())
 	}
+	return keys, pubKeys
+}
+
+func NewMinimalConsensusInfo(epoch uint64) *types.MinimalEpochConsensusInfo {
+	validatorList := make([]string, 32)
+	copy(validatorList, validatorPubKeys)
 
 	var validatorList32 [32]string
 	copy(validatorList32[:], validatorList)
@@ -32,25 +46,22 @@ func NewMinimalConsensusInfo(epoch uint64) *types.MinimalEpochConsensusInfo {
 
 // NewEth1Header
 func NewEth1Header(slot uint64) *eth1Types.Header {
+	return NewEth1HeaderWithParent(slot, eth1Types.EmptyRootHash)
+}
+
+// NewEth1HeaderWithParent returns a signed pandora header of the slot which is the child of the given parent header.
+// The header is produced at the start time of the slot in NewMinimalConsensusInfo.
+func NewEth1HeaderWithParent(slot uint64, parentHash common.Hash) *eth1Types.Header {
 	blockNumber := int64(slot)
 	epoch := slot / 32
 	extraData := types.ExtraData{
-		Slot:  slot,
-		Epoch: epoch,
-		// TODO: remove this, we do not have this information
-		ProposerIndex: 786,
+		Slot:          slot,
+		Epoch:         epoch,
+		ProposerIndex: slot % types.SlotsPerEpoch,
 	}
 
-	signatureBytes := []byte("df7284286281db4c0bea60b338a62ddfde0d34736ad2657f2bea159fc8c6675cd5bbb68373e9f3d4bba017a82ed0d9b9")
-	var blsSignatureBytes types.BlsSignatureBytes
-	copy(blsSignatureBytes[:], signatureBytes[:])
-	extraDataWithSig := types.PanExtraDataWithBLSSig{
-		extraData,
-		blsSignatureBytes,
-	}
-	extraDataByte, _ := rlp.EncodeToBytes(extraDataWithSig)
 	header := &eth1Types.Header{
-		ParentHash:  eth1Types.EmptyRootHash,
+		ParentHash:  parentHash,
 		UncleHash:   eth1Types.EmptyUncleHash,
 		Coinbase:    common.HexToAddress("8888f1f195afa192cfee860698584c030f4c9db1"),
 		Root:        common.HexToHash("ef1552a40b7165c3cd773806b9e0c165b75356e0314bf0706f279c729f51e017"),
@@ -60,37 +71,23 @@ func NewEth1Header(slot uint64) *eth1Types.Header {
 		Number:      big.NewInt(blockNumber),
 		GasLimit:    uint64(3141592),
 		GasUsed:     uint64(21000),
-		Time:        uint64(1426516743),
-		Extra:       extraDataByte,
+		Time:        NewMinimalConsensusInfo(epoch).SlotStartTime(slot),
 		MixDigest:   eth1Types.EmptyRootHash,
 		Nonce:       eth1Types.BlockNonce{0x01, 0x02, 0x03},
 	}
+	SignEth1Header(header, extraData, ValidatorKeys[slot%types.SlotsPerEpoch])
 	return header
 }
 
-// SealHash returns the hash of a block prior to it being sealed.
-func SealHash(header *eth1Types.Header) (hash common.Hash) {
-	hasher := sha3.NewLegacyKeccak256()
-
-	if err := rlp.Encode(hasher, []interface{}{
-		header.ParentHash,
-		header.UncleHash,
-		header.Coinbase,
-		header.Root,
-		header.TxHash,
-		header.ReceiptHash,
-		header.Bloom,
-		header.Difficulty,
-		header.Number,
-		header.GasLimit,
-		header.GasUsed,
-		header.Time,
-		header.Extra,
-	}); err != nil {
-		return eth1Types.EmptyRootHash
+// SignEth1Header signs the seal hash of the header with the given key and stores the extra data with
+// signature into the header
+func SignEth1Header(header *eth1Types.Header, extraData types.ExtraData, key bls.SecretKey) {
+	sealHash, _ := types.SealHash(header, &extraData)
+	extraDataWithSig := types.PanExtraDataWithBLSSig{
+		ExtraData:         extraData,
+		BlsSignatureBytes: types.BytesToSig(key.Sign(sealHash[:]).Marshal()),
 	}
-	hasher.Sum(hash[:0])
-	return hash
+	header.Extra, _ = rlp.EncodeToBytes(extraDataWithSig)
 }
 
 // NewBeaconBlock
@@ -103,6 +100,9 @@ func NewVanguardShardInfo(slot uint64, header *eth1Types.Header) *types.Vanguard
 }
 
 func NewPandoraShard(panHeader *eth1Types.Header) *ethpb.PandoraShard {
+	extraDataWithSig := new(types.PanExtraDataWithBLSSig)
+	_ = rlp.DecodeBytes(panHeader.Extra, extraDataWithSig)
+	sealHash, _ := types.SealHash(panHeader, &extraDataWithSig.ExtraData)
 	return &ethpb.PandoraShard{
 		BlockNumber: panHeader.Number.Uint64(),
 		Hash:        panHeader.Hash().Bytes(),
@@ -110,6 +110,7 @@ func NewPandoraShard(panHeader *eth1Types.Header) *ethpb.PandoraShard {
 		StateRoot:   panHeader.Root.Bytes(),
 		TxHash:      panHeader.TxHash.Bytes(),
 		ReceiptHash: panHeader.ReceiptHash.Bytes(),
-		Signature:   []byte("df7284286281db4c0bea60b338a62ddfde0d34736ad2657f2bea159fc8c6675cd5bbb68373e9f3d4bba017a82ed0d9b9"),
+		SealHash:    sealHash.Bytes(),
+		Signature:   extraDataWithSig.BlsSignatureBytes.Bytes(),
 	}
 }
@@ -7,7 +7,10 @@ import (
 	"time"
 )
 
-const BLSSignatureSize = 96
+const (
+	BLSSignatureSize = 96
+	SlotsPerEpoch    = 32
+)
 
 type MinimalEpochConsensusInfo struct {
 	Epoch            uint64        `json:"epoch"`
@@ -16,6 +19,17 @@ type MinimalEpochConsensusInfo struct {
 	SlotTimeDuration time.Duration `json:"slotTimeDuration"`
 }
 
+// SlotStartTime returns the start time of the given slot in unix seconds. The slot must belong to the epoch
+// of the consensus info. SlotTimeDuration holds the slot duration in seconds.
+func (ci *MinimalEpochConsensusInfo) SlotStartTime(slot uint64) uint64 {
+	return ci.EpochStartTime + (slot%SlotsPerEpoch)*uint64(ci.SlotTimeDuration)
+}
+
+// SlotEndTime returns the time in unix seconds at which the next slot starts
+func (ci *MinimalEpochConsensusInfo) SlotEndTime(slot uint64) uint64 {
+	return ci.SlotStartTime(slot) + uint64(ci.SlotTimeDuration)
+}
+
 type BlockStatus struct {
 	Hash   common.Hash `json:"hash"`
 	Status Status      `json:"status"`
@@ -34,15 +48,31 @@ type PanExtraDataWithBLSSig struct {
 
 // PandoraHeaderInfo
 type PandoraHeaderInfo struct {
-	Slot   uint64
-	Header *eth1Types.Header
+	Slot       uint64
+	ShardIndex uint64
+	Header     *eth1Types.Header
 }
 
-// VanguardShardInfo
+// VanguardShardInfo holds one pandora shard of a vanguard block. ShardIndex is the position of the shard in the
+// block body.
 type VanguardShardInfo struct {
+	Slot       uint64
+	ShardIndex uint64
+	ShardInfo  *eth2Types.PandoraShard
+	BlockHash  []byte
+}
+
+// ShardSlot identifies a pandora shard of a slot
+type ShardSlot struct {
+	Slot       uint64
+	ShardIndex uint64
+}
+
+// FinalizedCheckpoint is the latest finalized vanguard checkpoint. Verified slots up to its slot can not be reverted.
+type FinalizedCheckpoint struct {
 	Slot      uint64
-	ShardInfo *eth2Types.PandoraShard
-	BlockHash []byte
+	Epoch     uint64
+	BlockRoot common.Hash
 }
 
 type BlsSignatureBytes [BLSSignatureSize]byte
@@ -51,6 +81,7 @@ type BlsSignatureBytes [BLSSignatureSize]byte
 type SlotInfoWithStatus struct {
 	VanguardBlockHash common.Hash
 	PandoraHeaderHash common.Hash
+	ShardIndex        uint64
 	Status
 }
 
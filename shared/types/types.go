@@ -5,16 +5,45 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	eth1Types "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
+	"golang.org/x/crypto/sha3"
 )
 
 type Status string
 
 const (
-	Pending  Status = "Pending"
-	Verified Status = "Verified"
-	Invalid  Status = "Invalid"
-	Skipped  Status = "Skipped"
-	Unknown  Status = "Unknown"
+	Pending   Status = "Pending"
+	Verified  Status = "Verified"
+	Invalid   Status = "Invalid"
+	Skipped   Status = "Skipped"
+	TimedOut  Status = "TimedOut"
+	Reorged   Status = "Reorged"
+	Finalized Status = "Finalized"
+	Unknown   Status = "Unknown"
+)
+
+// MismatchReason tells which field failed to match while verifying sharding info
+type MismatchReason string
+
+const (
+	NoMismatch            MismatchReason = ""
+	BlockNumberMismatch   MismatchReason = "BlockNumberMismatch"
+	HeaderHashMismatch    MismatchReason = "HeaderHashMismatch"
+	ParentHashMismatch    MismatchReason = "ParentHashMismatch"
+	StateRootMismatch     MismatchReason = "StateRootMismatch"
+	TxHashMismatch        MismatchReason = "TxHashMismatch"
+	ReceiptHashMismatch   MismatchReason = "ReceiptHashMismatch"
+	ExtraDataDecodeError  MismatchReason = "ExtraDataDecodeError"
+	SignatureMismatch     MismatchReason = "SignatureMismatch"
+	SlotMismatch          MismatchReason = "SlotMismatch"
+	EpochMismatch         MismatchReason = "EpochMismatch"
+	ProposerIndexMismatch MismatchReason = "ProposerIndexMismatch"
+	ConsensusInfoMissing  MismatchReason = "ConsensusInfoMissing"
+	InvalidSignature      MismatchReason = "InvalidSignature"
+	SealHashMismatch      MismatchReason = "SealHashMismatch"
+	TimestampMismatch     MismatchReason = "TimestampMismatch"
+	// UnknownMismatch is the reason of invalid slots which were stored before reasons were recorded
+	UnknownMismatch MismatchReason = "UnknownMismatch"
 )
 
 // ExtraData
@@ -30,6 +59,65 @@ type SlotInfo struct {
 	PandoraHeaderHash common.Hash
 }
 
+// InvalidSlotInfo holds the slot info of an invalid slot with the reason of verification failure
+type InvalidSlotInfo struct {
+	SlotInfo
+	Reason MismatchReason
+}
+
+// RuleVerdict holds the result of one verification rule for a slot
+type RuleVerdict struct {
+	Rule   string
+	Reason MismatchReason
+}
+
+// Passed returns true when the rule has not found any mismatch
+func (rv *RuleVerdict) Passed() bool {
+	return rv.Reason == NoMismatch
+}
+
+// VerifiedSlot holds everything which is committed for a verified slot of a shard
+type VerifiedSlot struct {
+	Slot      uint64
+	SlotInfo  *SlotInfo
+	Header    *eth1Types.Header
+	ShardInfo *VanguardShardInfo
+	Verdicts  []*RuleVerdict
+}
+
+// VerificationGap tells that a pending slot of a shard waits for the missing parent slots between the latest
+// verified slot and itself
+type VerificationGap struct {
+	ShardIndex         uint64
+	LatestVerifiedSlot uint64
+	LatestHeaderHash   common.Hash
+	WaitingSlot        uint64
+	ParentHash         common.Hash
+}
+
+// EquivocationKind tells which chain has delivered two conflicting proposals for the same slot. FinalityConflict
+// tells that verified slots conflict with the vanguard finalized checkpoint, which is not a double proposal.
+type EquivocationKind string
+
+const (
+	VanguardEquivocation EquivocationKind = "Vanguard"
+	PandoraEquivocation  EquivocationKind = "Pandora"
+	FinalityConflict     EquivocationKind = "Finality"
+)
+
+// Equivocation holds the evidence of a double proposal. Vanguard shard infos are set for a vanguard equivocation
+// and signed pandora headers are set for a pandora equivocation.
+type Equivocation struct {
+	Kind              EquivocationKind
+	Slot              uint64
+	ShardIndex        uint64
+	ProposerIndex     uint64
+	ProposerPublicKey string
+
+	VanguardShardInfos []*VanguardShardInfo
+	PandoraHeaders     []*eth1Types.Header
+}
+
 // CopyHeader creates a deep copy of a block header to prevent side effects from
 // modifying a header variable.
 func CopyHeader(h *eth1Types.Header) *eth1Types.Header {
@@ -46,3 +134,34 @@ func CopyHeader(h *eth1Types.Header) *eth1Types.Header {
 	}
 	return &cpy
 }
+
+// SealHash returns the hash of a pandora header prior to it being sealed. It follows the seal hash of pandora's
+// consensus engine, which the vanguard validator checks before it signs the header and ships as the SealHash of
+// the pandora shard: the header fields up to the timestamp with the extra data without signature.
+func SealHash(header *eth1Types.Header, extraData *ExtraData) (hash common.Hash, err error) {
+	extraDataBytes, err := rlp.EncodeToBytes(extraData)
+	if err != nil {
+		return hash, err
+	}
+
+	hasher := sha3.NewLegacyKeccak256()
+	if err := rlp.Encode(hasher, []interface{}{
+		header.ParentHash,
+		header.UncleHash,
+		header.Coinbase,
+		header.Root,
+		header.TxHash,
+		header.ReceiptHash,
+		header.Bloom,
+		header.Difficulty,
+		header.Number,
+		header.GasLimit,
+		header.GasUsed,
+		header.Time,
+		extraDataBytes,
+	}); err != nil {
+		return hash, err
+	}
+	hasher.Sum(hash[:0])
+	return hash, nil
+}